@@ -0,0 +1,181 @@
+// Package balance provides the balance of categories with goals, similar to a
+// flexitime account: the cumulative surplus or deficit of time spent relative
+// to the time the goals require, since a start date.
+package balance
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// Settings define how balances are computed.
+type Settings struct {
+	// Start is the first date that is accounted for.
+	Start model.Date
+	// CarryOver is the balance per category name at the start.
+	CarryOver map[string]time.Duration
+	// Cap is the maximum surplus carried over into a new month, if any.
+	Cap *time.Duration
+	// ResetDates are the dates at the start of which balances are reset to
+	// zero.
+	ResetDates map[model.Date]bool
+}
+
+// NewSettingsFromConfig constructs balance settings from config data.
+// If no start date is configured, it returns nil (and no error).
+func NewSettingsFromConfig(cfg config.Balance) (*Settings, error) {
+	if cfg.Start == "" {
+		return nil, nil
+	}
+
+	start, err := model.FromString(cfg.Start)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing balance start date: %w", err)
+	}
+	result := Settings{
+		Start:      start,
+		CarryOver:  make(map[string]time.Duration),
+		ResetDates: make(map[model.Date]bool),
+	}
+	for category, durationString := range cfg.CarryOver {
+		duration, err := time.ParseDuration(durationString)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing balance carry-over for category '%s': %w", category, err)
+		}
+		result.CarryOver[category] = duration
+	}
+	if cfg.Cap != "" {
+		capDuration, err := time.ParseDuration(cfg.Cap)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing balance cap: %w", err)
+		}
+		if capDuration < 0 {
+			return nil, fmt.Errorf("balance cap %s is negative", capDuration)
+		}
+		result.Cap = &capDuration
+	}
+	for i, dateString := range cfg.ResetDates {
+		date, err := model.FromString(dateString)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing balance reset date no. %d: %w", i, err)
+		}
+		result.ResetDates[date] = true
+	}
+	return &result, nil
+}
+
+// Account is the balance of a single category.
+type Account struct {
+	Category model.Category
+	// Balance is the current balance, i.e., the surplus (positive) or deficit
+	// (negative).
+	Balance time.Duration
+	// Forfeited is the total surplus that exceeded the cap at the start of a
+	// month and was thus not carried over.
+	Forfeited time.Duration
+	// Months are the balances at the end of each month.
+	Months []Month
+}
+
+// Month is the balance of a category in a month.
+type Month struct {
+	// Month identifies the month, e.g. "2022-12".
+	Month string
+	// Delta is the difference between the time spent and the time required in
+	// the month.
+	Delta time.Duration
+	// Balance is the balance at the end of the month.
+	Balance time.Duration
+}
+
+// Compute computes the balances of all given categories with goals from the
+// settings' start date until the given date (inclusive), sorted by category
+// name.
+// The days are provided by getDay; a nil day counts as empty.
+func Compute(settings Settings, til model.Date, categories []model.Category, getDay func(model.Date) *model.Day) []Account {
+	accounts := make([]*Account, 0)
+	for _, cat := range categories {
+		if cat.Goal == nil {
+			continue
+		}
+		accounts = append(accounts, &Account{
+			Category: cat,
+			Balance:  settings.CarryOver[cat.Name],
+			Months:   make([]Month, 0),
+		})
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Category.Name < accounts[j].Category.Name })
+
+	monthDeltas := make([]time.Duration, len(accounts))
+	closeMonth := func(d model.Date) {
+		for i, account := range accounts {
+			account.Months = append(account.Months, Month{
+				Month:   fmt.Sprintf("%04d-%02d", d.Year, d.Month),
+				Delta:   monthDeltas[i],
+				Balance: account.Balance,
+			})
+			monthDeltas[i] = 0
+		}
+	}
+
+	for date := settings.Start; !date.IsAfter(til); date = date.Next() {
+		if date.Day == 1 && date != settings.Start {
+			closeMonth(date.Prev())
+			if settings.Cap != nil {
+				for _, account := range accounts {
+					if account.Balance > *settings.Cap {
+						account.Forfeited += account.Balance - *settings.Cap
+						account.Balance = *settings.Cap
+					}
+				}
+			}
+		}
+		if settings.ResetDates[date] {
+			for _, account := range accounts {
+				account.Balance = 0
+			}
+		}
+
+		durations := make(map[string]int)
+		if day := getDay(date); day != nil {
+			for cat, minutes := range day.SumUpByCategory() {
+				durations[cat.Name] += minutes
+			}
+		}
+		for i, account := range accounts {
+			delta := time.Duration(durations[account.Category.Name])*time.Minute - account.Category.Goal.Requires(date)
+			account.Balance += delta
+			monthDeltas[i] += delta
+		}
+	}
+	if !settings.Start.IsAfter(til) {
+		closeMonth(til)
+	}
+
+	result := make([]Account, len(accounts))
+	for i := range accounts {
+		result[i] = *accounts[i]
+	}
+	return result
+}
+
+// FormatDuration formats a balance compactly and with explicit sign, e.g.
+// "+12h30m", "-45m" or "0m".
+func FormatDuration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	sign := "+"
+	switch {
+	case minutes == 0:
+		sign = ""
+	case minutes < 0:
+		sign, minutes = "-", -minutes
+	}
+	if minutes < 60 {
+		return fmt.Sprintf("%s%dm", sign, minutes)
+	}
+	return fmt.Sprintf("%s%dh%02dm", sign, minutes/60, minutes%60)
+}
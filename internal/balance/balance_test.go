@@ -0,0 +1,112 @@
+package balance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func TestCompute(t *testing.T) {
+	workGoal := &model.WorkweekGoal{Monday: 8 * time.Hour, Tuesday: 8 * time.Hour, Wednesday: 8 * time.Hour, Thursday: 8 * time.Hour, Friday: 8 * time.Hour}
+	categories := []model.Category{
+		{Name: "work", Goal: workGoal},
+		{Name: "eating"},
+	}
+	// Wed 2022-11-30 through Fri 2022-12-02
+	days := map[model.Date]*model.Day{
+		{Year: 2022, Month: 11, Day: 30}: model.NewDayWithEvents([]*model.Event{
+			model.NewEvent("08:00|18:00|work|Long day", categories),
+		}),
+		{Year: 2022, Month: 12, Day: 1}: model.NewDayWithEvents([]*model.Event{
+			model.NewEvent("09:00|15:00|work|Short day", categories),
+			model.NewEvent("12:00|12:30|eating|Lunch", categories),
+		}),
+	}
+	getDay := func(d model.Date) *model.Day { return days[d] }
+	start := model.Date{Year: 2022, Month: 11, Day: 30}
+	friday := model.Date{Year: 2022, Month: 12, Day: 2}
+
+	{
+		testcase := "simple balance with carry-over"
+		accounts := Compute(Settings{Start: start, CarryOver: map[string]time.Duration{"work": 3 * time.Hour}}, friday, categories, getDay)
+		// +3h carry-over, +2h, -2h (no flattening overlap as eating has priority 0), -8h
+		if len(accounts) != 1 || accounts[0].Category.Name != "work" || accounts[0].Balance != -5*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, accounts)
+		}
+		months := accounts[0].Months
+		if len(months) != 2 ||
+			months[0].Month != "2022-11" || months[0].Delta != 2*time.Hour || months[0].Balance != 5*time.Hour ||
+			months[1].Month != "2022-12" || months[1].Delta != -10*time.Hour || months[1].Balance != -5*time.Hour {
+			t.Fatalf("testcase '%s' failed: got months %v", testcase, months)
+		}
+	}
+	{
+		testcase := "cap at month start"
+		capDuration := time.Hour
+		accounts := Compute(Settings{Start: start, CarryOver: map[string]time.Duration{"work": 3 * time.Hour}, Cap: &capDuration}, friday, categories, getDay)
+		if accounts[0].Forfeited != 4*time.Hour || accounts[0].Balance != -9*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, accounts)
+		}
+	}
+	{
+		testcase := "reset"
+		accounts := Compute(Settings{Start: start, ResetDates: map[model.Date]bool{friday: true}}, friday, categories, getDay)
+		if accounts[0].Balance != -8*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, accounts)
+		}
+	}
+	{
+		testcase := "til before start"
+		accounts := Compute(Settings{Start: friday}, start, categories, getDay)
+		if len(accounts) != 1 || accounts[0].Balance != 0 || len(accounts[0].Months) != 0 {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, accounts)
+		}
+	}
+}
+
+func TestNewSettingsFromConfig(t *testing.T) {
+	{
+		testcase := "unconfigured"
+		settings, err := NewSettingsFromConfig(config.Balance{})
+		if settings != nil || err != nil {
+			t.Fatalf("testcase '%s' failed: got %v, %v", testcase, settings, err)
+		}
+	}
+	{
+		testcase := "full"
+		settings, err := NewSettingsFromConfig(config.Balance{
+			Start:      "2022-01-01",
+			CarryOver:  map[string]string{"work": "-2h30m"},
+			Cap:        "40h",
+			ResetDates: []string{"2023-01-01"},
+		})
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if settings.CarryOver["work"] != -150*time.Minute || *settings.Cap != 40*time.Hour || !settings.ResetDates[model.Date{Year: 2023, Month: 1, Day: 1}] {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, settings)
+		}
+	}
+	{
+		testcase := "invalid"
+		_, err := NewSettingsFromConfig(config.Balance{Start: "2022-01-01", Cap: "lots"})
+		if err == nil {
+			t.Fatalf("testcase '%s' failed: expected error", testcase)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for d, expected := range map[time.Duration]string{
+		0:                 "0m",
+		45 * time.Minute:  "+45m",
+		-45 * time.Minute: "-45m",
+		750 * time.Minute: "+12h30m",
+	} {
+		if FormatDuration(d) != expected {
+			t.Fatalf("duration %s should be formatted as '%s', but is '%s'", d, expected, FormatDuration(d))
+		}
+	}
+}
@@ -9,8 +9,19 @@ import (
 // Config is the configuration data as present in a config file at
 // '${DAYPLAN_HOME}/config.yaml'.
 type Config struct {
-	Stylesheet Stylesheet `yaml:"stylesheet"`
-	Categories []Category `yaml:"categories"`
+	Stylesheet Stylesheet    `yaml:"stylesheet"`
+	Categories []Category    `yaml:"categories"`
+	Export     Export        `yaml:"export"`
+	Balance    Balance       `yaml:"balance"`
+	Goals      []PatternGoal `yaml:"goals"`
+	Timesheet  Timesheet     `yaml:"timesheet"`
+	Invoice    Invoice       `yaml:"invoice"`
+
+	// Keybindings override the default keybindings of the TUI.
+	// They map pane names (e.g. "events") to mode names (e.g. "normal" or
+	// "move") to key sequences (e.g. "<c-w>h") to action IDs (e.g.
+	// "events.delete"); an empty action ID unbinds the key sequence.
+	Keybindings map[string]map[string]map[string]string `yaml:"keybindings"`
 }
 
 // A Stylesheet is the stylesheet contents defined in a config file.
@@ -38,6 +49,7 @@ type Stylesheet struct {
 	SummaryDefault    Styling `yaml:"summary-default"`
 	SummaryTitleBox   Styling `yaml:"summary-title-box"`
 	CategoryFallback  Styling `yaml:"category-fallback"`
+	Heatmap           Styling `yaml:"heatmap"`
 }
 
 // A Styling is a styling as defined in a config file.
@@ -59,17 +71,58 @@ type FontStyle struct {
 // A Category as defined in a config file.
 // It combines the style definition with the name and priority definition.
 type Category struct {
-	Name       string `yaml:"name,omitempty"`
-	Color      string `yaml:"color,omitempty"`
-	Priority   int    `yaml:"priority,omitempty"`
-	Goal       Goal   `yaml:"goal,omitempty"`
-	Deprecated bool   `yaml:"deprecated"`
+	Name       string   `yaml:"name,omitempty"`
+	Color      string   `yaml:"color,omitempty"`
+	Priority   int      `yaml:"priority,omitempty"`
+	Goal       Goal     `yaml:"goal,omitempty"`
+	Limit      Goal     `yaml:"limit,omitempty"`
+	Billing    *Billing `yaml:"billing,omitempty"`
+	Deprecated bool     `yaml:"deprecated"`
+}
+
+// Billing defines how the time spent on a category is billed to a client.
+type Billing struct {
+	// Client is the client the category is billed to.
+	Client string `yaml:"client"`
+	// Rate is the hourly rate.
+	Rate float64 `yaml:"rate"`
+	// Currency is the currency of the rate, e.g. "EUR".
+	Currency string `yaml:"currency,omitempty"`
+	// Rounding defines the rounding of the duration of each line item, e.g. up
+	// to "15m".
+	Rounding Rounding `yaml:"rounding,omitempty"`
 }
 
 // Goal is a time goal.
+// When used as a limit, it defines the maximum rather than the minimum time.
+//
+// At most one kind of goal may be defined.
+// Daily, weekly and monthly goals are durations (see time.ParseDuration);
+// weekly and monthly totals are required in any distribution over the period.
 type Goal struct {
 	Workweek *WorkweekGoal `yaml:"workweek,omitempty"`
 	Ranged   *[]RangedGoal `yaml:"ranged,omitempty"`
+	Daily    string        `yaml:"daily,omitempty"`
+	Weekly   string        `yaml:"weekly,omitempty"`
+	Monthly  string        `yaml:"monthly,omitempty"`
+}
+
+// PatternGoal is a goal and/or limit on the events matched by a tag or a name
+// pattern (rather than on a category), e.g. "at most 1h/day of social media".
+type PatternGoal struct {
+	// Name identifies the goal in summaries.
+	Name string `yaml:"name"`
+	// Tag matches events whose names contain the tag, e.g. "deep" matches
+	// "Refactoring #deep".
+	Tag string `yaml:"tag,omitempty"`
+	// Pattern is a regular expression matching event names.
+	Pattern string `yaml:"pattern,omitempty"`
+	// Category optionally restricts the goal to events of the category of this
+	// name.
+	Category string `yaml:"category,omitempty"`
+
+	Goal  Goal `yaml:"goal,omitempty"`
+	Limit Goal `yaml:"limit,omitempty"`
 }
 
 // WorkweekGoal allows defining an expected duration per weekday.
@@ -96,6 +149,97 @@ type RangedGoal struct {
 	Time  string `yaml:"time"`
 }
 
+// Export is the configuration for exporting dayplan data for use with other
+// tools, such as plain-text accounting programs (ledger, hledger, ...).
+type Export struct {
+	// Accounts are the rules by which categories are mapped to accounts.
+	// They are applied in order and the first matching rule is used.
+	Accounts []AccountMapping `yaml:"accounts"`
+	// DefaultAccount is the account used for categories not matched by any
+	// rule. If empty, such categories are not exported.
+	DefaultAccount string `yaml:"default-account,omitempty"`
+}
+
+// AccountMapping maps the categories matched by a regular expression to an
+// account.
+//
+// The account may reference submatches of the category regex, e.g., a mapping
+// with category `^client-(.*)$` and account `clients:$1` would map the
+// category `client-acme` to the account `clients:acme`.
+type AccountMapping struct {
+	Category string `yaml:"category"`
+	Account  string `yaml:"account"`
+}
+
+// Timesheet is the configuration for timesheets, i.e., how their entries are
+// rounded and which labor-law rules they are checked against.
+type Timesheet struct {
+	Rounding   TimesheetRounding   `yaml:"rounding"`
+	Compliance TimesheetCompliance `yaml:"compliance"`
+}
+
+// TimesheetRounding defines the rounding of the start, end and break duration
+// of timesheet entries; an undefined rounding means no rounding.
+type TimesheetRounding struct {
+	Start Rounding `yaml:"start,omitempty"`
+	End   Rounding `yaml:"end,omitempty"`
+	Break Rounding `yaml:"break,omitempty"`
+}
+
+// Rounding defines rounding to a multiple of a duration (e.g. "15m") in a mode
+// ("nearest", "up" or "down").
+type Rounding struct {
+	Mode string `yaml:"mode,omitempty"`
+	To   string `yaml:"to,omitempty"`
+}
+
+// TimesheetCompliance defines the rules timesheet entries are checked against.
+type TimesheetCompliance struct {
+	// Breaks are the minimum break durations required after working for a
+	// given duration, e.g. 30m after 6h.
+	Breaks []BreakRule `yaml:"breaks,omitempty"`
+	// MaxWork is the maximum work duration per day (e.g. "10h"), if any.
+	MaxWork string `yaml:"max-work,omitempty"`
+	// Adjust defines whether violating entries are adjusted (rather than only
+	// flagged).
+	Adjust bool `yaml:"adjust,omitempty"`
+}
+
+// Invoice is the configuration for invoices (see Billing for the per-category
+// configuration).
+type Invoice struct {
+	// Tax is the tax rate in percent, e.g. 19.
+	Tax float64 `yaml:"tax,omitempty"`
+	// NumberFormat is the format (see fmt) of invoice numbers, given the
+	// sequence number of the invoice, e.g. "INV-%04d". Defaults to "%d".
+	NumberFormat string `yaml:"number-format,omitempty"`
+}
+
+// BreakRule requires breaks of a minimum total duration when working for more
+// than a given duration (e.g., after "6h" a "minimum" of "30m").
+type BreakRule struct {
+	After   string `yaml:"after"`
+	Minimum string `yaml:"minimum"`
+}
+
+// Balance is the configuration for the balance (like a flexitime account) of
+// the categories that have goals, i.e., the cumulative surplus or deficit of
+// time spent relative to the goals.
+type Balance struct {
+	// Start is the date (YYYY-MM-DD) from which on the balance is computed.
+	// If empty, no balance is computed.
+	Start string `yaml:"start,omitempty"`
+	// CarryOver maps category names to the balance they start with (e.g.,
+	// "12h30m" or "-2h"), e.g., carried over from before the start date.
+	CarryOver map[string]string `yaml:"carry-over,omitempty"`
+	// Cap is the maximum surplus (e.g. "40h") that is carried over into a new
+	// month; any surplus beyond it is forfeited. If empty, there is no cap.
+	Cap string `yaml:"cap,omitempty"`
+	// ResetDates are dates (YYYY-MM-DD) at the start of which the balance is
+	// reset to zero.
+	ResetDates []string `yaml:"reset-dates,omitempty"`
+}
+
 // ParseConfigAugmentDefaults parses the configuration specified in
 // YAML-formatted data and uses it to augment a given default configuration.
 func ParseConfigAugmentDefaults(defaultTheme ColorschemeType, yamlData []byte) (Config, error) {
@@ -127,6 +271,30 @@ func (base Config) augmentWith(augment Config) Config {
 		result.Categories = augment.Categories
 	}
 
+	if len(augment.Export.Accounts) > 0 || augment.Export.DefaultAccount != "" {
+		result.Export = augment.Export
+	}
+
+	if augment.Balance.Start != "" {
+		result.Balance = augment.Balance
+	}
+
+	if len(augment.Goals) > 0 {
+		result.Goals = augment.Goals
+	}
+
+	if augment.Timesheet.Rounding != (TimesheetRounding{}) || len(augment.Timesheet.Compliance.Breaks) > 0 || augment.Timesheet.Compliance.MaxWork != "" {
+		result.Timesheet = augment.Timesheet
+	}
+
+	if augment.Invoice != (Invoice{}) {
+		result.Invoice = augment.Invoice
+	}
+
+	if len(augment.Keybindings) > 0 {
+		result.Keybindings = augment.Keybindings
+	}
+
 	return result
 }
 
@@ -155,6 +323,7 @@ func (base Stylesheet) augmentWith(augment Stylesheet) Stylesheet {
 	result.Editor.overwriteIfDefined(augment.Editor)
 	result.SummaryDefault.overwriteIfDefined(augment.SummaryDefault)
 	result.SummaryTitleBox.overwriteIfDefined(augment.SummaryTitleBox)
+	result.Heatmap.overwriteIfDefined(augment.Heatmap)
 
 	return result
 }
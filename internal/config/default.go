@@ -40,6 +40,7 @@ func defaultStylesheet(colorschemeType ColorschemeType) Stylesheet {
 			SummaryDefault:    Styling{Fg: "#ffffff", Bg: "#000000", Style: &FontStyle{}},
 			SummaryTitleBox:   Styling{Fg: "#f0f0f0", Bg: "#000000", Style: &FontStyle{Bold: true}},
 			CategoryFallback:  Styling{Fg: "#ffaaaa", Bg: "#882222", Style: &FontStyle{}},
+			Heatmap:           Styling{Fg: "#ffffff", Bg: "#39d353", Style: &FontStyle{}},
 		}
 	} else {
 		return Stylesheet{
@@ -66,6 +67,7 @@ func defaultStylesheet(colorschemeType ColorschemeType) Stylesheet {
 			SummaryDefault:    Styling{Fg: "#000000", Bg: "#ffffff", Style: &FontStyle{}},
 			SummaryTitleBox:   Styling{Fg: "#000000", Bg: "#f0f0f0", Style: &FontStyle{Bold: true}},
 			CategoryFallback:  Styling{Fg: "#882222", Bg: "#ffaaaa", Style: &FontStyle{}},
+			Heatmap:           Styling{Fg: "#ffffff", Bg: "#216e39", Style: &FontStyle{}},
 		}
 	}
 }
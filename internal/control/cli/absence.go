@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ja-he/dayplan/internal/ics"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/storage"
+)
+
+// AbsenceCommand is the command `absence`, which manages days of absence
+// (vacation, sick days, holidays, half-days), which goals are adjusted to.
+type AbsenceCommand struct {
+	SetCommand       AbsenceSetCommand       `command:"set" description:"set the absence on a day (or a range of days)"`
+	UnsetCommand     AbsenceUnsetCommand     `command:"unset" description:"remove the absence on a day (or a range of days)"`
+	ListCommand      AbsenceListCommand      `command:"list" description:"list absences"`
+	ImportICSCommand AbsenceImportICSCommand `command:"import-ics" description:"import (e.g. public holidays) from an iCalendar file"`
+}
+
+// AbsenceSetCommand is the command `absence set`.
+type AbsenceSetCommand struct {
+	Date  string `short:"d" long:"date" description:"the (first) day of the absence" value-name:"<yyyy-mm-dd>|today" default:"today"`
+	Til   string `short:"t" long:"til" description:"the last day of the absence (inclusive), if it spans multiple days" value-name:"<yyyy-mm-dd>"`
+	Kind  string `short:"k" long:"kind" choice:"vacation" choice:"sick" choice:"holiday" choice:"half-day" description:"the kind of absence" required:"true"`
+	Note  string `short:"n" long:"note" description:"an optional note, e.g. the name of a holiday" value-name:"<note>"`
+	Force bool   `long:"force" description:"overwrite existing absences"`
+}
+
+// AbsenceUnsetCommand is the command `absence unset`.
+type AbsenceUnsetCommand struct {
+	Date string `short:"d" long:"date" description:"the (first) day" value-name:"<yyyy-mm-dd>|today" default:"today"`
+	Til  string `short:"t" long:"til" description:"the last day (inclusive), if it spans multiple days" value-name:"<yyyy-mm-dd>"`
+}
+
+// AbsenceListCommand is the command `absence list`.
+type AbsenceListCommand struct {
+	From string `short:"f" long:"from" description:"only list absences from this day on" value-name:"<yyyy-mm-dd>"`
+	Til  string `short:"t" long:"til" description:"only list absences up to this day (inclusive)" value-name:"<yyyy-mm-dd>"`
+}
+
+// AbsenceImportICSCommand is the command `absence import-ics`.
+type AbsenceImportICSCommand struct {
+	Kind  string `short:"k" long:"kind" choice:"vacation" choice:"sick" choice:"holiday" choice:"half-day" description:"the kind of absence for the imported days" default:"holiday"`
+	Force bool   `long:"force" description:"overwrite existing absences"`
+
+	Args struct {
+		File string `positional-arg-name:"file" description:"the iCalendar (.ics) file"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Execute executes the set command.
+func (command *AbsenceSetCommand) Execute(args []string) error {
+	from, til, err := parseAbsenceRange(command.Date, command.Til)
+	if err != nil {
+		return err
+	}
+	kind, err := model.AbsenceKindFromString(command.Kind)
+	if err != nil {
+		return err
+	}
+	return modifyAbsences(func(absences *model.Absences) error {
+		for date := from; !date.IsAfter(til); date = date.Next() {
+			if err := setAbsence(absences, date, model.Absence{Kind: kind, Note: command.Note}, command.Force); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Execute executes the unset command.
+func (command *AbsenceUnsetCommand) Execute(args []string) error {
+	from, til, err := parseAbsenceRange(command.Date, command.Til)
+	if err != nil {
+		return err
+	}
+	return modifyAbsences(func(absences *model.Absences) error {
+		for date := from; !date.IsAfter(til); date = date.Next() {
+			if previous, ok := absences.Get(date); ok {
+				absences.Unset(date)
+				fmt.Printf("unset %s: %s\n", date.ToString(), previous.String())
+			}
+		}
+		return nil
+	})
+}
+
+// Execute executes the list command.
+func (command *AbsenceListCommand) Execute(args []string) error {
+	var from, til *model.Date
+	if command.From != "" {
+		date, err := parseDateOrToday(command.From)
+		if err != nil {
+			return fmt.Errorf("from date '%s' invalid (%w)", command.From, err)
+		}
+		from = &date
+	}
+	if command.Til != "" {
+		date, err := parseDateOrToday(command.Til)
+		if err != nil {
+			return fmt.Errorf("til date '%s' invalid (%w)", command.Til, err)
+		}
+		til = &date
+	}
+
+	absences, err := readAbsences(getBaseDirPath())
+	if err != nil {
+		return err
+	}
+	for _, absence := range absences.All() {
+		if (from != nil && absence.Date.IsBefore(*from)) || (til != nil && absence.Date.IsAfter(*til)) {
+			continue
+		}
+		fmt.Printf("%s %-3s  %s\n", absence.Date.ToString(), absence.Date.ToWeekday().String()[:3], absence.String())
+	}
+	return nil
+}
+
+// Execute executes the import-ics command.
+func (command *AbsenceImportICSCommand) Execute(args []string) error {
+	kind, err := model.AbsenceKindFromString(command.Kind)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(command.Args.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	events, err := ics.ReadDayEvents(f)
+	if err != nil {
+		return fmt.Errorf("can't read '%s' (%w)", command.Args.File, err)
+	}
+
+	return modifyAbsences(func(absences *model.Absences) error {
+		for _, event := range events {
+			if err := setAbsence(absences, event.Date, model.Absence{Kind: kind, Note: event.Summary}, command.Force); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// parseAbsenceRange parses the date (which may be "today") and the optional
+// til date of an absence range.
+func parseAbsenceRange(dateString, tilString string) (from, til model.Date, err error) {
+	from, err = parseDateOrToday(dateString)
+	if err != nil {
+		return from, til, fmt.Errorf("date '%s' invalid (%w)", dateString, err)
+	}
+	if tilString == "" {
+		return from, from, nil
+	}
+	return parseDateRange(from.ToString(), tilString)
+}
+
+// setAbsence sets the absence on the given date, refusing to overwrite a
+// different existing absence unless forced.
+func setAbsence(absences *model.Absences, date model.Date, absence model.Absence, force bool) error {
+	if previous, ok := absences.Get(date); ok && previous != absence && !force {
+		return fmt.Errorf("%s already has absence '%s' (use '--force' to overwrite)", date.ToString(), previous.String())
+	}
+	absences.Set(date, absence)
+	fmt.Printf("set %s: %s\n", date.ToString(), absence.String())
+	return nil
+}
+
+// modifyAbsences reads the absences, modifies them and, if that succeeds,
+// writes them back.
+func modifyAbsences(modify func(*model.Absences) error) error {
+	baseDirPath := getBaseDirPath()
+	absences, err := readAbsences(baseDirPath)
+	if err != nil {
+		return err
+	}
+	if err := modify(absences); err != nil {
+		return err
+	}
+	return storage.WriteAbsences(baseDirPath+"/"+storage.AbsencesFileName, absences)
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/ja-he/dayplan/internal/balance"
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/storage"
+)
+
+// BalanceCommand is the command `balance`, which prints the balance (surplus
+// or deficit relative to their goals) of categories with goals since the
+// configured start date.
+type BalanceCommand struct {
+	TilDay  string `short:"t" long:"til" description:"the day til which to compute the balance (inclusive)" value-name:"<yyyy-mm-dd>|today" default:"today"`
+	ByMonth bool   `short:"m" long:"by-month" description:"also list the balance at the end of each month"`
+}
+
+// Execute executes the balance command.
+func (command *BalanceCommand) Execute(args []string) error {
+	til, err := parseDateOrToday(command.TilDay)
+	if err != nil {
+		return fmt.Errorf("til date '%s' invalid (%w)", command.TilDay, err)
+	}
+
+	baseDirPath := getBaseDirPath()
+	configData, err := readConfig(baseDirPath, config.Light)
+	if err != nil {
+		return err
+	}
+	settings, err := balance.NewSettingsFromConfig(configData.Balance)
+	if err != nil {
+		return err
+	}
+	if settings == nil {
+		return fmt.Errorf("no balance start date configured (see 'balance' section in config.yaml)")
+	}
+	categories, err := readCategories(baseDirPath)
+	if err != nil {
+		return err
+	}
+
+	accounts := balance.Compute(*settings, til, categories, func(date model.Date) *model.Day {
+		return storage.NewFileHandler(baseDirPath + "/days/" + date.ToString()).Read(categories)
+	})
+
+	fmt.Printf("balance from %s til %s:\n", settings.Start.ToString(), til.ToString())
+	for _, account := range accounts {
+		var forfeitedStr string
+		if account.Forfeited > 0 {
+			forfeitedStr = fmt.Sprintf(" (forfeited: %s)", balance.FormatDuration(account.Forfeited))
+		}
+		fmt.Printf("  % 20s: % 10s%s\n", account.Category.Name, balance.FormatDuration(account.Balance), forfeitedStr)
+		if command.ByMonth {
+			for _, month := range account.Months {
+				fmt.Printf("  % 20s  % 10s (% 10s)\n", month.Month, balance.FormatDuration(month.Balance), balance.FormatDuration(month.Delta))
+			}
+		}
+	}
+	return nil
+}
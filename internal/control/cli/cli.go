@@ -7,7 +7,19 @@ type CommandLineOpts struct {
 	TuiCommand       TUICommand       `command:"tui" subcommands-optional:"true"`
 	SummarizeCommand SummarizeCommand `command:"summarize" subcommands-optional:"true"`
 	TimesheetCommand TimesheetCommand `command:"timesheet" subcommands-optional:"true"`
+	InvoiceCommand   InvoiceCommand   `command:"invoice"`
 	AddCommand       AddCommand       `command:"add" subcommands-optional:"true"`
+	ExportCommand    ExportCommand    `command:"export"`
+	ReportCommand    ReportCommand    `command:"report"`
+	RenderCommand    RenderCommand    `command:"render"`
+	ShowCommand      ShowCommand      `command:"show"`
+	EditCommand      EditCommand      `command:"edit"`
+	StartCommand     StartCommand     `command:"start"`
+	StopCommand      StopCommand      `command:"stop"`
+	SwitchCommand    SwitchCommand    `command:"switch"`
+	StatusCommand    StatusCommand    `command:"status"`
+	BalanceCommand   BalanceCommand   `command:"balance"`
+	AbsenceCommand   AbsenceCommand   `command:"absence"`
 	VersionCommand   VersionCommand   `command:"version" subcommands-optional:"true"`
 }
 
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/storage"
+	"github.com/ja-he/dayplan/internal/styling"
+)
+
+// getBaseDirPath returns the dayplan base directory, which is
+// '${DAYPLAN_HOME}' if set, '${HOME}/.config/dayplan' otherwise.
+func getBaseDirPath() string {
+	dayplanHome := os.Getenv("DAYPLAN_HOME")
+	if dayplanHome == "" {
+		return os.Getenv("HOME") + "/.config/dayplan"
+	}
+	return strings.TrimRight(dayplanHome, "/")
+}
+
+// readConfig reads the config file from the given base directory and parses
+// it, augmenting the defaults for the given theme.
+func readConfig(baseDirPath string, theme config.ColorschemeType) (config.Config, error) {
+	yamlData, err := os.ReadFile(baseDirPath + "/" + "config.yaml")
+	if err != nil {
+		return config.Config{}, fmt.Errorf("can't read config file (%w)", err)
+	}
+	configData, err := config.ParseConfigAugmentDefaults(theme, yamlData)
+	if err != nil {
+		return config.Config{}, fmt.Errorf("can't parse config data (%w)", err)
+	}
+	return configData, nil
+}
+
+// readAbsences reads the absences stored in the given base directory.
+func readAbsences(baseDirPath string) (*model.Absences, error) {
+	absences, err := storage.ReadAbsences(baseDirPath + "/" + storage.AbsencesFileName)
+	if err != nil {
+		return nil, fmt.Errorf("can't read absences (%w)", err)
+	}
+	return absences, nil
+}
+
+// categoryFromConfig constructs a category (including its goal and limit)
+// from its config definition.
+// If absences are given, the goal is adjusted to them.
+func categoryFromConfig(category config.Category, absences *model.Absences) (model.Category, error) {
+	goal, err := model.NewGoalFromConfig(category.Goal)
+	if err != nil {
+		return model.Category{}, fmt.Errorf("invalid goal for category '%s' (%w)", category.Name, err)
+	}
+	if goal != nil && absences != nil {
+		goal = &model.AbsenceAdjustedGoal{Goal: goal, Absences: absences}
+	}
+	limit, err := model.NewGoalFromConfig(category.Limit)
+	if err != nil {
+		return model.Category{}, fmt.Errorf("invalid limit for category '%s' (%w)", category.Name, err)
+	}
+
+	return model.Category{
+		Name:       category.Name,
+		Priority:   category.Priority,
+		Goal:       goal,
+		Limit:      limit,
+		Deprecated: category.Deprecated,
+	}, nil
+}
+
+// patternGoalsFromConfig constructs the goals on tags or name patterns defined
+// in the given config.
+// If absences are given, the goals are adjusted to them.
+func patternGoalsFromConfig(configData config.Config, absences *model.Absences) ([]model.PatternGoal, error) {
+	result := make([]model.PatternGoal, 0, len(configData.Goals))
+	for _, cfg := range configData.Goals {
+		goal, err := model.NewPatternGoalFromConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if goal.Goal != nil && absences != nil {
+			goal.Goal = &model.AbsenceAdjustedGoal{Goal: goal.Goal, Absences: absences}
+		}
+		result = append(result, *goal)
+	}
+	return result, nil
+}
+
+// readCategories reads the categories defined in the config in the given base
+// directory, with their goals adjusted to the absences stored there.
+func readCategories(baseDirPath string) ([]model.Category, error) {
+	configData, err := readConfig(baseDirPath, config.Light)
+	if err != nil {
+		return nil, err
+	}
+	absences, err := readAbsences(baseDirPath)
+	if err != nil {
+		return nil, err
+	}
+	categories := make([]model.Category, 0, len(configData.Categories))
+	for _, category := range configData.Categories {
+		cat, err := categoryFromConfig(category, absences)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, cat)
+	}
+	return categories, nil
+}
+
+// categoryStylingFromConfig constructs the styled categories defined in the
+// given config.
+// If absences are given, the categories' goals are adjusted to them.
+func categoryStylingFromConfig(configData config.Config, darkBG bool, absences *model.Absences) (*styling.CategoryStyling, error) {
+	styledCategories := styling.EmptyCategoryStyling()
+	for _, category := range configData.Categories {
+		cat, err := categoryFromConfig(category, absences)
+		if err != nil {
+			return nil, err
+		}
+		styledCategories.Add(cat, styling.StyleFromHexSingle(category.Color, darkBG))
+	}
+	return styledCategories, nil
+}
+
+// datedDay is a day along with its date.
+type datedDay struct {
+	Date model.Date
+	Day  *model.Day
+}
+
+// readDays reads the days in the given range (inclusive) from the day files in
+// the given base directory.
+func readDays(baseDirPath string, categories []model.Category, from, til model.Date) []datedDay {
+	result := make([]datedDay, 0)
+	for current := from; current != til.Next(); current = current.Next() {
+		fh := storage.NewFileHandler(baseDirPath + "/days/" + current.ToString())
+		result = append(result, datedDay{Date: current, Day: fh.Read(categories)})
+	}
+	return result
+}
+
+// getCategories returns the plain categories of the given category styling.
+func getCategories(styledCategories *styling.CategoryStyling) []model.Category {
+	categories := make([]model.Category, 0)
+	for _, cat := range styledCategories.GetAll() {
+		categories = append(categories, cat.Cat)
+	}
+	return categories
+}
+
+// parseDateRange parses the given from and til date strings.
+func parseDateRange(fromString, tilString string) (from, til model.Date, err error) {
+	from, err = model.FromString(fromString)
+	if err != nil {
+		return from, til, fmt.Errorf("from date '%s' invalid (%w)", fromString, err)
+	}
+	til, err = model.FromString(tilString)
+	if err != nil {
+		return from, til, fmt.Errorf("til date '%s' invalid (%w)", tilString, err)
+	}
+	if til.IsBefore(from) {
+		return from, til, fmt.Errorf("til date %s is before from date %s", til.ToString(), from.ToString())
+	}
+	return from, til, nil
+}
+
+// categoryColors returns a function providing the foreground and background
+// colors (in hex notation) for a category, falling back to the configured
+// fallback style for unknown categories.
+func categoryColors(configData config.Config, styledCategories *styling.CategoryStyling) func(model.Category) (fg, bg string) {
+	fallbackFG, fallbackBG := styling.StyleFromConfig(configData.Stylesheet.CategoryFallback).HexColors()
+	return func(cat model.Category) (fg, bg string) {
+		style, err := styledCategories.GetStyle(cat)
+		if err != nil {
+			return fallbackFG, fallbackBG
+		}
+		return style.HexColors()
+	}
+}
+
+// parseDateOrToday parses the given date string, which may also be "today".
+func parseDateOrToday(s string) (model.Date, error) {
+	if s == "today" {
+		return model.FromTime(time.Now()).Date, nil
+	}
+	return model.FromString(s)
+}
@@ -6,13 +6,16 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/ja-he/dayplan/internal/balance"
 	"github.com/ja-he/dayplan/internal/control"
 	"github.com/ja-he/dayplan/internal/control/action"
+	"github.com/ja-he/dayplan/internal/control/command"
 	"github.com/ja-he/dayplan/internal/control/edit"
 	"github.com/ja-he/dayplan/internal/control/edit/editors"
 	"github.com/ja-he/dayplan/internal/input"
@@ -21,6 +24,7 @@ import (
 	"github.com/ja-he/dayplan/internal/potatolog"
 	"github.com/ja-he/dayplan/internal/storage"
 	"github.com/ja-he/dayplan/internal/styling"
+	"github.com/ja-he/dayplan/internal/tracking"
 	"github.com/ja-he/dayplan/internal/tui"
 	"github.com/ja-he/dayplan/internal/ui"
 	"github.com/ja-he/dayplan/internal/ui/panes"
@@ -67,66 +71,62 @@ type Controller struct {
 	// awkwardly accessing information that they shouldn't need to.
 	timestampGuesser func(int, int) model.Timestamp
 
+	balanceSettings *balance.Settings
+	// balances holds the state of the balances' computation in the background
+	// (see updateBalances)
+	balances struct {
+		sync.Mutex
+		// unloadedDays caches the days that were read from file for the
+		// computation, as they were not loaded
+		unloadedDays map[model.Date]*model.Day
+		// requested and computed count the computations, so that the result of
+		// an older one does not replace that of a newer one
+		requested, computed int
+		result              []balance.Account
+	}
+
+	// focusCurrentDate focusses the current date's events pane in the views
+	// showing multiple days.
+	focusCurrentDate func()
+
+	// agendaDays is the number of days (from today on) in the agenda view.
+	agendaDays int
+
+	// commands are the commands of the command line.
+	commands *command.Registry
+
 	screenEvents      tui.EventPollable
 	initializedScreen tui.InitializedScreen
 	syncer            tui.ScreenSynchronizer
 }
 
+// newScreenHandler returns the screen handler the TUI is rendered with (and
+// which tests replace).
+var newScreenHandler = tui.NewTUIScreenHandler
+
 // NewController creates a new Controller.
 func NewController(
 	date model.Date,
 	envData control.EnvData,
 	categoryStyling styling.CategoryStyling,
 	stylesheet styling.Stylesheet,
+	balanceSettings *balance.Settings,
+	absences *model.Absences,
+	patternGoals []model.PatternGoal,
+	keybindingOverrides map[string]map[string]map[string]string,
+	agendaDays int,
 ) (*Controller, error) {
-	controller := Controller{}
-
-	inputConfig := input.InputConfig{
-
-		Editor: map[input.Keyspec]input.Actionspec{
-			"j":       "next-field",
-			"k":       "prev-field",
-			"i":       "enter-subeditor",
-			":w<CR>":  "write",
-			"<CR>":    "write-and-quit",
-			":wq<CR>": "write-and-quit",
-			":q!<CR>": "quit",
-			"<ESC>":   "quit",
-		},
+	controller := Controller{agendaDays: agendaDays}
 
-		StringEditor: input.ModedSpec{
-			Normal: map[input.Keyspec]input.Actionspec{
-				"h":       "move-cursor-rune-left",
-				"l":       "move-cursor-rune-right",
-				"<left>":  "move-cursor-rune-left",
-				"<right>": "move-cursor-rune-right",
-				"0":       "move-cursor-to-beginning",
-				"$":       "move-cursor-to-end",
-				"w":       "move-cursor-to-next-word-beginning",
-				"b":       "move-cursor-to-prev-word-beginning",
-				"e":       "move-cursor-to-next-word-end",
-				"<ESC>":   "quit",
-				"D":       "delete-to-end",
-				"d$":      "delete-to-end",
-				"d0":      "backspace-to-beginning",
-				"C":       "delete-to-end-and-insert",
-				"c$":      "delete-to-end-and-insert",
-				"c0":      "backspace-to-beginning-and-insert",
-				"S":       "delete-everything-and-insert",
-				"x":       "delete-rune",
-				"s":       "delete-rune-and-insert",
-				"i":       "swap-mode-insert",
-				"a":       "swap-mode-insert-append",
-			},
-			Insert: map[input.Keyspec]input.Actionspec{
-				"<left>":  "move-cursor-rune-left",
-				"<right>": "move-cursor-rune-right",
-				"<ESC>":   "swap-mode-normal",
-				"<c-bs>":  "backspace",
-				"<bs>":    "backspace",
-				"<c-u>":   "backspace-to-beginning",
-			},
-		},
+	bindings, err := newKeybindings(keybindingOverrides)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keybindings config (%w)", err)
+	}
+	actions := make(actionRegistry)
+	inputConfig := bindings.editorInputConfig()
+	err = editors.ValidateInputConfig(inputConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keybindings config (%w)", err)
 	}
 
 	categoryGetter := func(name string) model.Category {
@@ -139,7 +139,29 @@ func NewController(
 		}
 	}
 
+	// the editors choose categories from the known ones
+	categoryChoices := editors.Choices{
+		NameOf:   func(value any) string { return value.(model.Category).Name },
+		FromName: func(name string) any { return categoryGetter(name) },
+	}
+	for _, c := range categoryStyling.GetAll() {
+		categoryChoices.Known = append(categoryChoices.Known, editors.Choice{Name: c.Cat.Name, Value: c.Cat, Deprecated: c.Cat.Deprecated})
+	}
+	editorSpec := map[string]any{"category": categoryChoices}
+	categorySwatch := func(value any) styling.DrawStyling {
+		cat, ok := value.(model.Category)
+		if !ok {
+			return nil
+		}
+		style, err := categoryStyling.GetStyle(cat)
+		if err != nil {
+			return stylesheet.CategoryFallback
+		}
+		return style
+	}
+
 	controller.data = control.NewControlData(categoryStyling)
+	controller.data.Absences = absences
 	backlogFilePath := path.Join(envData.BaseDirPath, "days", "backlog.yml") // TODO(ja_he): Migrate 'days' -> 'data', perhaps subdir 'days'
 	backlog, err := func() (*model.Backlog, error) {
 		backlogReader, err := os.Open(backlogFilePath)
@@ -175,18 +197,18 @@ func NewController(
 	editorWidth := 80
 	editorHeight := 20
 
-	scrollableZoomableInputMap := map[input.Keyspec]action.Action{
-		"<c-u>": action.NewSimple(func() string { return "scroll up" }, func() { controller.ScrollUp(10) }),
-		"<c-d>": action.NewSimple(func() string { return "scroll down" }, func() { controller.ScrollDown(10) }),
-		"gg":    action.NewSimple(func() string { return "scroll to top" }, controller.ScrollTop),
-		"G":     action.NewSimple(func() string { return "scroll to bottom" }, controller.ScrollBottom),
-		"+": action.NewSimple(func() string { return "zoom in" }, func() {
+	actions.register(map[input.Actionspec]action.Action{
+		"timeline.scroll-up":     action.NewSimple(func() string { return "scroll up" }, func() { controller.ScrollUp(10) }),
+		"timeline.scroll-down":   action.NewSimple(func() string { return "scroll down" }, func() { controller.ScrollDown(10) }),
+		"timeline.scroll-top":    action.NewSimple(func() string { return "scroll to top" }, controller.ScrollTop),
+		"timeline.scroll-bottom": action.NewSimple(func() string { return "scroll to bottom" }, controller.ScrollBottom),
+		"timeline.zoom-in": action.NewSimple(func() string { return "zoom in" }, func() {
 			if controller.data.MainTimelineViewParams.NRowsPerHour*2 <= 12 {
 				controller.data.MainTimelineViewParams.NRowsPerHour *= 2
 				controller.data.MainTimelineViewParams.ScrollOffset *= 2
 			}
 		}),
-		"-": action.NewSimple(func() string { return "zoom out" }, func() {
+		"timeline.zoom-out": action.NewSimple(func() string { return "zoom out" }, func() {
 			if (controller.data.MainTimelineViewParams.NRowsPerHour % 2) == 0 {
 				controller.data.MainTimelineViewParams.NRowsPerHour /= 2
 				controller.data.MainTimelineViewParams.ScrollOffset /= 2
@@ -194,18 +216,19 @@ func NewController(
 				log.Warn().Msg(fmt.Sprintf("can't decrease resolution below %d", controller.data.MainTimelineViewParams.NRowsPerHour))
 			}
 		}),
-	}
+	})
 
-	eventsViewBaseInputMap := map[input.Keyspec]action.Action{
-		"w": action.NewSimple(func() string { return "write day to file" }, controller.writeModel),
-		"h": action.NewSimple(func() string { return "go to previous day" }, controller.goToPreviousDay),
-		"l": action.NewSimple(func() string { return "go to next day" }, controller.goToNextDay),
-		"c": action.NewSimple(func() string { return "clear day's events" }, func() {
+	actions.register(map[input.Actionspec]action.Action{
+		"day.write":    action.NewSimple(func() string { return "write day to file" }, controller.writeModel),
+		"day.previous": action.NewSimple(func() string { return "go to previous day" }, controller.goToPreviousDay),
+		"day.next":     action.NewSimple(func() string { return "go to next day" }, controller.goToNextDay),
+		"day.clear": action.NewSimple(func() string { return "clear day's events" }, func() {
 			controller.data.Days.AddDay(controller.data.CurrentDate, model.NewDay(), controller.data.GetCurrentSuntimes())
 		}),
-	}
+		"day.cycle-absence": action.NewSimple(func() string { return "cycle day's absence (vacation, sick, holiday, half-day, none)" }, controller.cycleAbsence),
+	})
 
-	renderer := tui.NewTUIScreenHandler()
+	renderer := newScreenHandler()
 	screenSize := func() (w, h int) { _, _, w, h = renderer.Dimensions(); return }
 	screenDimensions := func() (x, y, w, h int) {
 		screenWidth, screenHeight := screenSize()
@@ -237,6 +260,8 @@ func NewController(
 	}
 	weekViewMainPaneDimensions := screenDimensions
 	monthViewMainPaneDimensions := screenDimensions
+	yearViewMainPaneDimensions := screenDimensions
+	agendaViewMainPaneDimensions := screenDimensions
 	weatherDimensions := func() (x, y, w, h int) {
 		parentX, parentY, _, parentH := dayViewScrollablePaneDimensions()
 		return parentX, parentY, weatherWidth, parentH
@@ -258,6 +283,18 @@ func NewController(
 		return 0, 0, timelineWidth, screenHeight - statusHeight
 	}
 	monthViewTimelineDimensions := weekViewTimelineDimensions
+	monthGridDimensions := func() (x, y, w, h int) {
+		baseX, baseY, baseW, baseH := monthViewMainPaneDimensions()
+		return baseX, baseY, baseW, baseH - statusHeight
+	}
+	yearDimensions := func() (x, y, w, h int) {
+		baseX, baseY, baseW, baseH := yearViewMainPaneDimensions()
+		return baseX, baseY, baseW, baseH - statusHeight
+	}
+	agendaDimensions := func() (x, y, w, h int) {
+		baseX, baseY, baseW, baseH := agendaViewMainPaneDimensions()
+		return baseX, baseY, baseW, baseH - statusHeight
+	}
 	weekdayDimensions := func(dayIndex int) func() (x, y, w, h int) {
 		return func() (x, y, w, h int) {
 			baseX, baseY, baseW, baseH := weekViewMainPaneDimensions()
@@ -266,7 +303,7 @@ func NewController(
 			return baseX + timelineWidth + (dayIndex * dayWidth), baseY, dayWidth, baseH - statusHeight
 		}
 	}
-	weekdayPaneInputTree, err := input.ConstructInputTree(eventsViewBaseInputMap)
+	weekdayPaneInputTree, err := bindings.tree("multiday-events", "normal", actions)
 	if err != nil {
 		return nil, fmt.Errorf("could not construct weekday pane input tree (%w)", err)
 	}
@@ -287,7 +324,12 @@ func NewController(
 			true,
 			false,
 			func() bool { return controller.data.CurrentDate.GetDayInWeek(dayIndex) == controller.data.CurrentDate },
-			func() *model.Event { return nil /* TODO */ },
+			func() *model.Event {
+				if controller.data.CurrentDate.GetDayInWeek(dayIndex) != controller.data.CurrentDate {
+					return nil
+				}
+				return controller.data.GetCurrentDay().Current
+			},
 			func() bool { return controller.data.MouseMode },
 		)
 	}
@@ -299,7 +341,7 @@ func NewController(
 			return baseX + timelineWidth + (dayIndex * dayWidth), baseY, dayWidth, baseH - statusHeight
 		}
 	}
-	monthdayPaneInputTree, err := input.ConstructInputTree(eventsViewBaseInputMap)
+	monthdayPaneInputTree, err := bindings.tree("multiday-events", "normal", actions)
 	if err != nil {
 		return nil, fmt.Errorf("could not construct monthday pane input tree (%w)", err)
 	}
@@ -324,7 +366,12 @@ func NewController(
 				false,
 				false,
 				func() bool { return controller.data.CurrentDate.GetDayInMonth(dayIndex) == controller.data.CurrentDate },
-				func() *model.Event { return nil /* TODO */ },
+				func() *model.Event {
+					if controller.data.CurrentDate.GetDayInMonth(dayIndex) != controller.data.CurrentDate {
+						return nil
+					}
+					return controller.data.GetCurrentDay().Current
+				},
 				func() bool { return controller.data.MouseMode },
 			),
 		)
@@ -340,6 +387,9 @@ func NewController(
 		monthViewEventsPanes[i] = monthdayPane(i)
 	}
 
+	// whether the month view shows the month as a calendar grid rather than
+	// the days' events side by side
+	monthGrid := false
 	statusPane := panes.NewStatusPane(
 		ui.NewConstrainedRenderer(renderer, statusDimensions),
 		statusDimensions,
@@ -353,26 +403,36 @@ func NewController(
 			case ui.ViewWeek:
 				return (w - timelineWidth) / 7
 			case ui.ViewMonth:
+				if monthGrid {
+					return w / 31
+				}
 				return (w - timelineWidth) / 31
+			case ui.ViewYear:
+				return 1
+			case ui.ViewAgenda:
+				return w
 			default:
 				panic("unknown view for status rendering")
 			}
 		},
 		func() int {
 			switch controller.data.ActiveView() {
-			case ui.ViewDay:
+			case ui.ViewDay, ui.ViewAgenda:
 				return 1
 			case ui.ViewWeek:
 				return 7
 			case ui.ViewMonth:
 				return controller.data.CurrentDate.GetLastOfMonth().Day
+			case ui.ViewYear:
+				_, _, w, _ := statusDimensions()
+				return w
 			default:
 				panic("unknown view for status rendering")
 			}
 		},
 		func() int {
 			switch controller.data.ActiveView() {
-			case ui.ViewDay:
+			case ui.ViewDay, ui.ViewAgenda:
 				return 1
 			case ui.ViewWeek:
 				switch controller.data.CurrentDate.ToWeekday() {
@@ -395,12 +455,29 @@ func NewController(
 				}
 			case ui.ViewMonth:
 				return controller.data.CurrentDate.Day
+			case ui.ViewYear:
+				_, _, w, _ := statusDimensions()
+				first := model.Date{Year: controller.data.CurrentDate.Year, Month: 1, Day: 1}
+				last := model.Date{Year: controller.data.CurrentDate.Year, Month: 12, Day: 31}
+				return w * (first.DaysUntil(controller.data.CurrentDate) + 1) / (first.DaysUntil(last) + 1)
 			default:
 				panic("unknown view for status rendering")
 			}
 		},
-		func() int { return timelineWidth },
+		func() int {
+			switch {
+			case controller.data.ActiveView() == ui.ViewMonth && monthGrid,
+				controller.data.ActiveView() == ui.ViewYear,
+				controller.data.ActiveView() == ui.ViewAgenda:
+				return 0
+			}
+			return timelineWidth
+		},
 		func() edit.EventEditMode { return controller.data.EventEditMode },
+		func() *tracking.Running { return controller.data.Tracking },
+		func() []balance.Account { return controller.data.Balances },
+		func() (model.Absence, bool) { return controller.data.Absences.Get(controller.data.CurrentDate) },
+		func() string { return controller.data.Message },
 	)
 
 	cursorWrangler := ui.NewCursorWrangler(renderer)
@@ -463,7 +540,7 @@ func NewController(
 			log.Warn().Msg("apparently, task editor was still active when a new one was activated, unexpected / error")
 		}
 		var err error
-		taskEditor, err := editors.ConstructEditor("root", task, nil, nil)
+		taskEditor, err := editors.ConstructEditor("root", task, editorSpec, nil)
 		if err != nil {
 			log.Error().Err(err).Interface("task", task).Msg("was not able to construct editor for task")
 			return
@@ -484,6 +561,7 @@ func NewController(
 			func() bool { return true },
 			inputConfig,
 			stylesheet,
+			categorySwatch,
 			controller.data.TaskEditor,
 		)
 		if err != nil {
@@ -500,205 +578,203 @@ func NewController(
 			controller.controllerEvents <- controllerEventTaskEditorExit
 		}()
 	}
-	tasksInputTree, err := input.ConstructInputTree(
-		map[input.Keyspec]action.Action{
-			"<c-u>": action.NewSimple(func() string { return "scroll up" }, func() {
-				backlogViewParams.SetScrollOffset(backlogViewParams.GetScrollOffset() - 10)
-				if backlogViewParams.GetScrollOffset() < 0 {
-					scrollBacklogTop()
-				}
-			}),
-			"<c-d>": action.NewSimple(func() string { return "scroll down" }, func() {
-				scrollTarget := backlogViewParams.GetScrollOffset() + 10
-				if scrollTarget > getBacklogBottomScrollOffset() {
-					scrollBacklogBottom()
-				} else {
-					backlogViewParams.SetScrollOffset(scrollTarget)
-				}
-			}),
-			"j": action.NewSimple(func() string { return "go down a task" }, func() {
-				if currentTask == nil {
-					if len(backlog.Tasks) > 0 {
-						currentTask = backlog.Tasks[0]
-					}
-					return
+	actions.register(map[input.Actionspec]action.Action{
+		"tasks.scroll-up": action.NewSimple(func() string { return "scroll up" }, func() {
+			backlogViewParams.SetScrollOffset(backlogViewParams.GetScrollOffset() - 10)
+			if backlogViewParams.GetScrollOffset() < 0 {
+				scrollBacklogTop()
+			}
+		}),
+		"tasks.scroll-down": action.NewSimple(func() string { return "scroll down" }, func() {
+			scrollTarget := backlogViewParams.GetScrollOffset() + 10
+			if scrollTarget > getBacklogBottomScrollOffset() {
+				scrollBacklogBottom()
+			} else {
+				backlogViewParams.SetScrollOffset(scrollTarget)
+			}
+		}),
+		"tasks.next": action.NewSimple(func() string { return "go down a task" }, func() {
+			if currentTask == nil {
+				if len(backlog.Tasks) > 0 {
+					currentTask = backlog.Tasks[0]
 				}
+				return
+			}
 
-				found := offsetCurrentTask(backlog.Tasks, true)
-				if !found {
-					setCurrentTask(nil)
-				}
-				ensureBacklogTaskVisible(currentTask)
-			}),
-			"k": action.NewSimple(func() string { return "go up a task" }, func() {
-				if currentTask == nil {
-					if len(backlog.Tasks) > 0 {
-						currentTask = backlog.Tasks[0]
-					}
-					return
+			found := offsetCurrentTask(backlog.Tasks, true)
+			if !found {
+				setCurrentTask(nil)
+			}
+			ensureBacklogTaskVisible(currentTask)
+		}),
+		"tasks.previous": action.NewSimple(func() string { return "go up a task" }, func() {
+			if currentTask == nil {
+				if len(backlog.Tasks) > 0 {
+					currentTask = backlog.Tasks[0]
 				}
+				return
+			}
 
-				found := offsetCurrentTask(backlog.Tasks, false)
-				if !found {
-					setCurrentTask(nil)
-				}
+			found := offsetCurrentTask(backlog.Tasks, false)
+			if !found {
+				setCurrentTask(nil)
+			}
+			ensureBacklogTaskVisible(currentTask)
+		}),
+		"tasks.first": action.NewSimple(func() string { return "scroll to top" }, func() {
+			backlogSetCurrentToTopmost()
+		}),
+		"tasks.last": action.NewSimple(func() string { return "scroll to bottom" }, func() {
+			backlogSetCurrentToBottommost()
+		}),
+		"tasks.schedule-now": action.NewSimple(func() string { return "schedule now" }, func() {
+			when := time.Now()
+			popAndScheduleCurrentTask(&when)
+		}),
+		"tasks.delete": action.NewSimple(func() string { return "delete task" }, func() {
+			popAndScheduleCurrentTask(nil)
+		}),
+		"tasks.step-into": action.NewSimple(func() string { return "step into subtasks" }, func() {
+			if currentTask == nil {
+				return
+			}
+			if len(currentTask.Subtasks) > 0 {
+				currentTask = currentTask.Subtasks[0]
 				ensureBacklogTaskVisible(currentTask)
-			}),
-			"gg": action.NewSimple(func() string { return "scroll to top" }, func() {
-				backlogSetCurrentToTopmost()
-			}),
-			"G": action.NewSimple(func() string { return "scroll to bottom" }, func() {
-				backlogSetCurrentToBottommost()
-			}),
-			"sn": action.NewSimple(func() string { return "schedule now" }, func() {
-				when := time.Now()
-				popAndScheduleCurrentTask(&when)
-			}),
-			"d": action.NewSimple(func() string { return "delete task" }, func() {
-				popAndScheduleCurrentTask(nil)
-			}),
-			"l": action.NewSimple(func() string { return "step into subtasks" }, func() {
-				if currentTask == nil {
-					return
-				}
-				if len(currentTask.Subtasks) > 0 {
-					currentTask = currentTask.Subtasks[0]
-					ensureBacklogTaskVisible(currentTask)
-				} else {
-					log.Debug().Msg("current task has no subtasks, so remaining at it")
-				}
-			}),
-			"h": action.NewSimple(func() string { return "step out to parent task" }, func() {
-				var findParent func(searchedTask *model.Task, parent *model.Task, tasks []*model.Task) *model.Task
-				findParent = func(searchedTask *model.Task, parent *model.Task, parentsTasks []*model.Task) *model.Task {
-					for _, t := range parentsTasks {
-						if t == searchedTask {
-							return parent
-						}
-						maybeParent := findParent(searchedTask, t, t.Subtasks)
-						if maybeParent != nil {
-							return maybeParent
-						}
+			} else {
+				log.Debug().Msg("current task has no subtasks, so remaining at it")
+			}
+		}),
+		"tasks.step-out": action.NewSimple(func() string { return "step out to parent task" }, func() {
+			var findParent func(searchedTask *model.Task, parent *model.Task, tasks []*model.Task) *model.Task
+			findParent = func(searchedTask *model.Task, parent *model.Task, parentsTasks []*model.Task) *model.Task {
+				for _, t := range parentsTasks {
+					if t == searchedTask {
+						return parent
+					}
+					maybeParent := findParent(searchedTask, t, t.Subtasks)
+					if maybeParent != nil {
+						return maybeParent
 					}
-					return nil
-				}
-				maybeParent := findParent(currentTask, nil, backlog.Tasks)
-				if maybeParent != nil {
-					setCurrentTask(maybeParent)
-					ensureBacklogTaskVisible(currentTask)
-				} else {
-					log.Debug().Msg("could not find parent, so not changing current task")
-				}
-			}),
-			"O": action.NewSimple(func() string { return "add a new task above the current one" }, func() {
-				if currentTask == nil {
-					log.Debug().Msgf("asked to add a task after to nil current task, adding as first")
-					newTask := backlog.AddLast()
-					newTask.Name = "" // user should be hinted to change this quite quickly, i.e. via immediate editor activation
-					newTask.Category = controller.data.CurrentCategory
-					currentTask = newTask
-					createAndEnableTaskEditor(currentTask)
-					return
-				}
-				newTask, parent, err := backlog.AddBefore(currentTask)
-				if err != nil {
-					log.Error().Err(err).Msgf("was unable to add a task after '%s'", currentTask.Name)
-					return
 				}
+				return nil
+			}
+			maybeParent := findParent(currentTask, nil, backlog.Tasks)
+			if maybeParent != nil {
+				setCurrentTask(maybeParent)
+				ensureBacklogTaskVisible(currentTask)
+			} else {
+				log.Debug().Msg("could not find parent, so not changing current task")
+			}
+		}),
+		"tasks.add-above": action.NewSimple(func() string { return "add a new task above the current one" }, func() {
+			if currentTask == nil {
+				log.Debug().Msgf("asked to add a task after to nil current task, adding as first")
+				newTask := backlog.AddLast()
 				newTask.Name = "" // user should be hinted to change this quite quickly, i.e. via immediate editor activation
-				if parent != nil {
-					newTask.Category = parent.Category
-				} else {
-					newTask.Category = controller.data.CurrentCategory
-				}
+				newTask.Category = controller.data.CurrentCategory
 				currentTask = newTask
 				createAndEnableTaskEditor(currentTask)
-			}),
-			"o": action.NewSimple(func() string { return "add a new task below the current one" }, func() {
-				if currentTask == nil {
-					log.Debug().Msgf("asked to add a task after to nil current task, adding as first")
-					newTask := backlog.AddLast()
-					newTask.Name = "" // user should be hinted to change this quite quickly, i.e. via immediate editor activation
-					newTask.Category = controller.data.CurrentCategory
-					currentTask = newTask
-					createAndEnableTaskEditor(currentTask)
-					return
-				}
-				newTask, parent, err := backlog.AddAfter(currentTask)
-				if err != nil {
-					log.Error().Err(err).Msgf("was unable to add a task after '%s'", currentTask.Name)
-					return
-				}
+				return
+			}
+			newTask, parent, err := backlog.AddBefore(currentTask)
+			if err != nil {
+				log.Error().Err(err).Msgf("was unable to add a task after '%s'", currentTask.Name)
+				return
+			}
+			newTask.Name = "" // user should be hinted to change this quite quickly, i.e. via immediate editor activation
+			if parent != nil {
+				newTask.Category = parent.Category
+			} else {
+				newTask.Category = controller.data.CurrentCategory
+			}
+			currentTask = newTask
+			createAndEnableTaskEditor(currentTask)
+		}),
+		"tasks.add-below": action.NewSimple(func() string { return "add a new task below the current one" }, func() {
+			if currentTask == nil {
+				log.Debug().Msgf("asked to add a task after to nil current task, adding as first")
+				newTask := backlog.AddLast()
 				newTask.Name = "" // user should be hinted to change this quite quickly, i.e. via immediate editor activation
-				if parent != nil {
-					newTask.Category = parent.Category
-				} else {
-					newTask.Category = controller.data.CurrentCategory
-				}
-				currentTask = newTask
-				createAndEnableTaskEditor(currentTask)
-			}),
-			"i": action.NewSimple(func() string { return "add a new subtask of the current task" }, func() {
-				if currentTask == nil {
-					log.Warn().Msgf("asked to add a subtask to nil current task")
-					return
-				}
-				newTask := &model.Task{
-					Name:     "", // user should be hinted to change this quite quickly, i.e. via immediate editor activation
-					Category: currentTask.Category,
-				}
-				currentTask.Subtasks = append(currentTask.Subtasks, newTask)
+				newTask.Category = controller.data.CurrentCategory
 				currentTask = newTask
 				createAndEnableTaskEditor(currentTask)
-			}),
-			"<cr>": action.NewSimple(func() string { return "begin editing of task" }, func() { createAndEnableTaskEditor(currentTask) }),
-			"w": action.NewSimple(func() string { return "store backlog to file" }, func() {
-				writer, err := os.OpenFile(backlogFilePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
-				if err != nil {
-					log.Error().Err(err).Msgf("unable to write open backlog file '%s' for writing", backlogFilePath)
-					return
-				}
-				defer writer.Close()
-				err = backlog.Write(writer)
-				if err != nil {
-					log.Error().Err(err).Msg("unable to write backlog to writer")
-					return
-				}
-				log.Info().Msgf("wrote backlog to '%s' successfully", backlogFilePath)
-			}),
-		},
-	)
+				return
+			}
+			newTask, parent, err := backlog.AddAfter(currentTask)
+			if err != nil {
+				log.Error().Err(err).Msgf("was unable to add a task after '%s'", currentTask.Name)
+				return
+			}
+			newTask.Name = "" // user should be hinted to change this quite quickly, i.e. via immediate editor activation
+			if parent != nil {
+				newTask.Category = parent.Category
+			} else {
+				newTask.Category = controller.data.CurrentCategory
+			}
+			currentTask = newTask
+			createAndEnableTaskEditor(currentTask)
+		}),
+		"tasks.add-subtask": action.NewSimple(func() string { return "add a new subtask of the current task" }, func() {
+			if currentTask == nil {
+				log.Warn().Msgf("asked to add a subtask to nil current task")
+				return
+			}
+			newTask := &model.Task{
+				Name:     "", // user should be hinted to change this quite quickly, i.e. via immediate editor activation
+				Category: currentTask.Category,
+			}
+			currentTask.Subtasks = append(currentTask.Subtasks, newTask)
+			currentTask = newTask
+			createAndEnableTaskEditor(currentTask)
+		}),
+		"tasks.edit": action.NewSimple(func() string { return "begin editing of task" }, func() { createAndEnableTaskEditor(currentTask) }),
+		"tasks.write": action.NewSimple(func() string { return "store backlog to file" }, func() {
+			writer, err := os.OpenFile(backlogFilePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+			if err != nil {
+				log.Error().Err(err).Msgf("unable to write open backlog file '%s' for writing", backlogFilePath)
+				return
+			}
+			defer writer.Close()
+			err = backlog.Write(writer)
+			if err != nil {
+				log.Error().Err(err).Msg("unable to write backlog to writer")
+				return
+			}
+			log.Info().Msgf("wrote backlog to '%s' successfully", backlogFilePath)
+		}),
+	})
+	tasksInputTree, err := bindings.tree("tasks", "normal", actions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct input tree for tasks pane (%w)", err)
 	}
-	toolsInputTree, err := input.ConstructInputTree(
-		map[input.Keyspec]action.Action{
-			"j": action.NewSimple(func() string { return "switch to next category" }, func() {
-				for i, cat := range controller.data.Categories {
-					if cat == controller.data.CurrentCategory {
-						for ii := i + 1; ii < len(controller.data.Categories); ii++ {
-							if !controller.data.Categories[ii].Deprecated {
-								controller.data.CurrentCategory = controller.data.Categories[ii]
-								return
-							}
+	actions.register(map[input.Actionspec]action.Action{
+		"tools.next-category": action.NewSimple(func() string { return "switch to next category" }, func() {
+			for i, cat := range controller.data.Categories {
+				if cat == controller.data.CurrentCategory {
+					for ii := i + 1; ii < len(controller.data.Categories); ii++ {
+						if !controller.data.Categories[ii].Deprecated {
+							controller.data.CurrentCategory = controller.data.Categories[ii]
+							return
 						}
 					}
 				}
-			}),
-			"k": action.NewSimple(func() string { return "switch to previous category" }, func() {
-				for i, cat := range controller.data.Categories {
-					if cat == controller.data.CurrentCategory {
-						for ii := i - 1; ii >= 0; ii-- {
-							if !controller.data.Categories[ii].Deprecated {
-								controller.data.CurrentCategory = controller.data.Categories[ii]
-								return
-							}
+			}
+		}),
+		"tools.previous-category": action.NewSimple(func() string { return "switch to previous category" }, func() {
+			for i, cat := range controller.data.Categories {
+				if cat == controller.data.CurrentCategory {
+					for ii := i - 1; ii >= 0; ii-- {
+						if !controller.data.Categories[ii].Deprecated {
+							controller.data.CurrentCategory = controller.data.Categories[ii]
+							return
 						}
 					}
 				}
-			}),
-		},
-	)
+			}
+		}),
+	})
+	toolsInputTree, err := bindings.tree("tools", "normal", actions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct input tree for tools pane (%w)", err)
 	}
@@ -715,30 +791,64 @@ func NewController(
 			controller.data.MainTimelineViewParams.ScrollOffset += ((controller.data.MainTimelineViewParams.YForTime(time)) - maxY)
 		}
 	}
-	var startMovePushing func()
-	// TODO: directly?
-	eventsPaneDayInputExtension := map[input.Keyspec]action.Action{
-		"j": action.NewSimple(func() string { return "switch to next event" }, func() {
+	var startMove, startMovePushing, startResize func()
+	// the events' modes (move, resize, ...) apply to the events pane of the
+	// active view, i.e., in week and month view to the events of all days, so
+	// that events can be moved between the days
+	var activeEventsPane func() ui.Pane
+	var modalEventsPane ui.Pane
+	eventsModeOverlays := make(map[string]map[string]*input.Tree)
+	for _, paneName := range []string{"events", "multiday-events"} {
+		eventsModeOverlays[paneName] = make(map[string]*input.Tree)
+		for _, modeName := range []string{"move", "move-pushing", "resize"} {
+			overlay, err := bindings.tree(paneName, modeName, actions)
+			if err != nil {
+				return nil, fmt.Errorf("failed to construct input tree for %s pane's %s mode (%w)", paneName, modeName, err)
+			}
+			eventsModeOverlays[paneName][modeName] = overlay
+		}
+	}
+	enterEventsMode := func(modeName string, mode edit.EventEditMode) {
+		if controller.data.GetCurrentDay().Current == nil {
+			return
+		}
+		paneName := "multiday-events"
+		if controller.data.ActiveView() == ui.ViewDay {
+			paneName = "events"
+		}
+		modalEventsPane = activeEventsPane()
+		modalEventsPane.ApplyModalOverlay(input.CapturingOverlayWrap(eventsModeOverlays[paneName][modeName]))
+		controller.data.EventEditMode = mode
+	}
+	exitEventsMode := func() {
+		if controller.data.EventEditMode == edit.EventEditModeNormal {
+			return
+		}
+		modalEventsPane.PopModalOverlay()
+		controller.data.EventEditMode = edit.EventEditModeNormal
+	}
+	actions.register(map[input.Actionspec]action.Action{
+		"events.next": action.NewSimple(func() string { return "switch to next event" }, func() {
 			controller.data.GetCurrentDay().CurrentNext()
 			if controller.data.GetCurrentDay().Current != nil {
 				ensureEventsPaneTimestampVisible(controller.data.GetCurrentDay().Current.Start)
 				ensureEventsPaneTimestampVisible(controller.data.GetCurrentDay().Current.End)
 			}
 		}),
-		"k": action.NewSimple(func() string { return "switch to previous event" }, func() {
+		"events.previous": action.NewSimple(func() string { return "switch to previous event" }, func() {
 			controller.data.GetCurrentDay().CurrentPrev()
 			if controller.data.GetCurrentDay().Current != nil {
 				ensureEventsPaneTimestampVisible(controller.data.GetCurrentDay().Current.End)
 				ensureEventsPaneTimestampVisible(controller.data.GetCurrentDay().Current.Start)
 			}
 		}),
-		"d": action.NewSimple(func() string { return "delete selected event" }, func() {
+		"events.delete": action.NewSimple(func() string { return "delete selected event" }, func() {
 			event := controller.data.GetCurrentDay().Current
 			if event != nil {
 				controller.data.GetCurrentDay().RemoveEvent(event)
 			}
 		}),
-		"<cr>": action.NewSimple(func() string { return "open the event editor" }, func() {
+		"events.edit": action.NewSimple(func() string { return "open the event editor" }, func() {
 			event := controller.data.GetCurrentDay().Current
 			if event == nil {
 				log.Warn().Msgf("ignoring event editing request since no current event selected")
@@ -749,7 +859,7 @@ func NewController(
 				log.Warn().Msgf("was about to construct new event editor but still have old one")
 				return
 			}
-			newEventEditor, err := editors.ConstructEditor("event", event, nil, nil)
+			newEventEditor, err := editors.ConstructEditor("event", event, editorSpec, nil)
 			if err != nil {
 				log.Warn().Err(err).Msgf("unable to construct event editor")
 				return
@@ -769,6 +879,7 @@ func NewController(
 				func() bool { return true },
 				inputConfig,
 				stylesheet,
+				categorySwatch,
 				controller.data.EventEditor,
 			)
 			if err != nil {
@@ -785,7 +896,7 @@ func NewController(
 				controller.controllerEvents <- controllerEventEventEditorExit
 			}()
 		}),
-		"o": action.NewSimple(func() string { return "add event after selected" }, func() {
+		"events.add-after": action.NewSimple(func() string { return "add event after selected" }, func() {
 			current := controller.data.GetCurrentDay().Current
 			newEvent := &model.Event{
 				Name: "",
@@ -806,7 +917,7 @@ func NewController(
 			controller.data.GetCurrentDay().AddEvent(newEvent)
 			ensureEventsPaneTimestampVisible(newEvent.End)
 		}),
-		"O": action.NewSimple(func() string { return "add event before selected" }, func() {
+		"events.add-before": action.NewSimple(func() string { return "add event before selected" }, func() {
 			current := controller.data.GetCurrentDay().Current
 			newEvent := &model.Event{
 				Name: "",
@@ -827,7 +938,7 @@ func NewController(
 			controller.data.GetCurrentDay().AddEvent(newEvent)
 			ensureEventsPaneTimestampVisible(newEvent.Start)
 		}),
-		"<c-o>": action.NewSimple(func() string { return "add event now" }, func() {
+		"events.add-now": action.NewSimple(func() string { return "add event now" }, func() {
 			newEvent := &model.Event{
 				Name: "",
 				Cat:  controller.data.CurrentCategory,
@@ -842,7 +953,7 @@ func NewController(
 			controller.data.GetCurrentDay().AddEvent(newEvent)
 			ensureEventsPaneTimestampVisible(newEvent.Start)
 		}),
-		"sn": action.NewSimple(func() string { return "split selected event now" }, func() {
+		"events.split-now": action.NewSimple(func() string { return "split selected event now" }, func() {
 			current := controller.data.GetCurrentDay().Current
 			if current == nil {
 				return
@@ -850,7 +961,7 @@ func NewController(
 			now := model.NewTimestampFromGotime(time.Now())
 			controller.data.GetCurrentDay().SplitEvent(current, *now)
 		}),
-		"sc": action.NewSimple(func() string { return "split selected event at its center" }, func() {
+		"events.split-center": action.NewSimple(func() string { return "split selected event at its center" }, func() {
 			current := controller.data.GetCurrentDay().Current
 			if current == nil {
 				return
@@ -858,16 +969,11 @@ func NewController(
 			center := current.Start.OffsetMinutes(current.Start.DurationInMinutesUntil(current.End) / 2)
 			controller.data.GetCurrentDay().SplitEvent(current, center)
 		}),
-		"M": action.NewSimple(func() string { return "start move pushing" }, func() { startMovePushing() }),
-	}
-	eventsPaneDayInputMap := make(map[input.Keyspec]action.Action)
-	for input, action := range eventsViewBaseInputMap {
-		eventsPaneDayInputMap[input] = action
-	}
-	for input, action := range eventsPaneDayInputExtension {
-		eventsPaneDayInputMap[input] = action
-	}
-	dayViewEventsPaneInputTree, err := input.ConstructInputTree(eventsPaneDayInputMap)
+		"events.move-mode":         action.NewSimple(func() string { return "enter event move mode" }, func() { startMove() }),
+		"events.move-pushing-mode": action.NewSimple(func() string { return "start move pushing" }, func() { startMovePushing() }),
+		"events.resize-mode":       action.NewSimple(func() string { return "enter event resize mode" }, func() { startResize() }),
+	})
+	dayViewEventsPaneInputTree, err := bindings.tree("events", "normal", actions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct input tree for day view pane's events subpane (%w)", err)
 	}
@@ -914,47 +1020,54 @@ func NewController(
 		func() *model.Event { return controller.data.GetCurrentDay().Current },
 		func() bool { return controller.data.MouseMode },
 	)
-	startMovePushing = func() {
-		if controller.data.GetCurrentDay().Current == nil {
-			return
-		}
-
-		overlay, err := input.ConstructInputTree(
-			map[input.Keyspec]action.Action{
-				"n": action.NewSimple(func() string { return "move to now" }, func() { panic("TODO") }),
-				"j": action.NewSimple(func() string { return "move down" }, func() {
-					err := controller.data.GetCurrentDay().MoveEventsPushingBy(
-						controller.data.GetCurrentDay().Current,
-						int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
-						int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
-					)
-					if err != nil {
-						panic(err)
-					}
-					ensureEventsPaneTimestampVisible(controller.data.GetCurrentDay().Current.End)
-				}),
-				"k": action.NewSimple(func() string { return "move up" }, func() {
-					err := controller.data.GetCurrentDay().MoveEventsPushingBy(
-						controller.data.GetCurrentDay().Current,
-						-int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
-						int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
-					)
-					if err != nil {
-						panic(err)
-					}
-					ensureEventsPaneTimestampVisible(controller.data.GetCurrentDay().Current.Start)
-				}),
-				"M":     action.NewSimple(func() string { return "exit move mode" }, func() { dayEventsPane.PopModalOverlay(); controller.data.EventEditMode = edit.EventEditModeNormal }),
-				"<esc>": action.NewSimple(func() string { return "exit move mode" }, func() { dayEventsPane.PopModalOverlay(); controller.data.EventEditMode = edit.EventEditModeNormal }),
-				// TODO(ja-he): mode switching
-			},
-		)
-		if err != nil {
-			panic(err.Error())
-		}
-		dayEventsPane.ApplyModalOverlay(input.CapturingOverlayWrap(overlay))
-		controller.data.EventEditMode = edit.EventEditModeMove
-	}
+	actions.register(map[input.Actionspec]action.Action{
+		"move-pushing.to-now": action.NewSimple(func() string { return "move to now" }, func() {
+			current := controller.data.GetCurrentDay().Current
+			if current == nil {
+				return
+			}
+			now := *model.NewTimestampFromGotime(time.Now())
+			err := controller.data.GetCurrentDay().MoveEventsPushingBy(current, current.Start.DurationInMinutesUntil(now), 1)
+			if err != nil {
+				log.Warn().Err(err).Msg("unable to move")
+			}
+			ensureEventsPaneTimestampVisible(current.Start)
+			ensureEventsPaneTimestampVisible(current.End)
+		}),
+		"move-pushing.down": action.NewSimple(func() string { return "move down" }, func() {
+			current := controller.data.GetCurrentDay().Current
+			if current == nil {
+				return
+			}
+			err := controller.data.GetCurrentDay().MoveEventsPushingBy(
+				current,
+				int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
+				int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
+			)
+			if err != nil {
+				log.Warn().Err(err).Msg("unable to move")
+			}
+			ensureEventsPaneTimestampVisible(current.End)
+		}),
+		"move-pushing.up": action.NewSimple(func() string { return "move up" }, func() {
+			current := controller.data.GetCurrentDay().Current
+			if current == nil {
+				return
+			}
+			err := controller.data.GetCurrentDay().MoveEventsPushingBy(
+				current,
+				-int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
+				int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
+			)
+			if err != nil {
+				log.Warn().Err(err).Msg("unable to move")
+			}
+			ensureEventsPaneTimestampVisible(current.Start)
+		}),
+		"move-pushing.exit": action.NewSimple(func() string { return "exit move mode" }, exitEventsMode),
+		// TODO(ja-he): mode switching
+	})
+	startMovePushing = func() { enterEventsMode("move-pushing", edit.EventEditModeMove) }
 	ensureBacklogTaskVisible = func(t *model.Task) {
 		viewportLB, viewportUB := tasksPane.GetTaskVisibilityBounds()
 		taskLB, taskUB := tasksPane.GetTaskUIYBounds(t)
@@ -1025,131 +1138,153 @@ func NewController(
 		return false
 	}
 
-	dayViewEventsPaneInputTree.Root.Children[input.Key{Key: tcell.KeyRune, Ch: 'm'}] = &input.Node{Action: action.NewSimple(func() string { return "enter event move mode" }, func() {
-		if controller.data.GetCurrentDay().Current == nil {
-			return
-		}
-
-		eventMoveOverlay, err := input.ConstructInputTree(
-			map[input.Keyspec]action.Action{
-				"n": action.NewSimple(func() string { return "move to now" }, func() {
-					current := controller.data.GetCurrentDay().Current
-					newStart := *model.NewTimestampFromGotime(time.Now())
-					controller.data.GetCurrentDay().MoveSingleEventTo(current, newStart)
-					ensureEventsPaneTimestampVisible(current.Start)
-					ensureEventsPaneTimestampVisible(current.End)
-				}),
-				"j": action.NewSimple(func() string { return "move down" }, func() {
-					current := controller.data.GetCurrentDay().Current
-					controller.data.GetCurrentDay().MoveSingleEventBy(
-						current,
-						int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
-						int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
-					)
-					ensureEventsPaneTimestampVisible(current.End)
-				}),
-				"k": action.NewSimple(func() string { return "move up" }, func() {
-					current := controller.data.GetCurrentDay().Current
-					controller.data.GetCurrentDay().MoveSingleEventBy(
-						current,
-						-int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
-						int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
-					)
-					ensureEventsPaneTimestampVisible(current.Start)
-				}),
-				"h": action.NewSimple(func() string { return "move to previous day" }, func() {
-					event := controller.data.GetCurrentDay().Current
-					controller.data.GetCurrentDay().RemoveEvent(event)
-					controller.goToPreviousDay()
-					controller.data.GetCurrentDay().AddEvent(event)
-				}),
-				"l": action.NewSimple(func() string { return "move to next day" }, func() {
-					event := controller.data.GetCurrentDay().Current
-					controller.data.GetCurrentDay().RemoveEvent(event)
-					controller.goToNextDay()
-					controller.data.GetCurrentDay().AddEvent(event)
-				}),
-				"m":     action.NewSimple(func() string { return "exit move mode" }, func() { dayEventsPane.PopModalOverlay(); controller.data.EventEditMode = edit.EventEditModeNormal }),
-				"<esc>": action.NewSimple(func() string { return "exit move mode" }, func() { dayEventsPane.PopModalOverlay(); controller.data.EventEditMode = edit.EventEditModeNormal }),
-			},
-		)
-		if err != nil {
-			panic(err.Error())
-		}
-		dayEventsPane.ApplyModalOverlay(input.CapturingOverlayWrap(eventMoveOverlay))
-		controller.data.EventEditMode = edit.EventEditModeMove
-	})}
-	dayViewEventsPaneInputTree.Root.Children[input.Key{Key: tcell.KeyRune, Ch: 'r'}] = &input.Node{Action: action.NewSimple(func() string { return "enter event resize mode" }, func() {
-		if controller.data.GetCurrentDay().Current == nil {
-			return
-		}
-
-		eventResizeOverlay, err := input.ConstructInputTree(
-			map[input.Keyspec]action.Action{
-				"n": action.NewSimple(func() string { return "resize to now" }, func() {
-					current := controller.data.GetCurrentDay().Current
-					newEnd := *model.NewTimestampFromGotime(time.Now())
-					controller.data.GetCurrentDay().ResizeTo(current, newEnd)
-					ensureEventsPaneTimestampVisible(newEnd)
-				}),
-				"j": action.NewSimple(func() string { return "increase size (lengthen)" }, func() {
-					var err error
-					current := controller.data.GetCurrentDay().Current
-					err = controller.data.GetCurrentDay().ResizeBy(
-						current,
-						int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
-					)
-					if err != nil {
-						log.Warn().Err(err).Msg("unable to resize")
-					}
-					err = controller.data.GetCurrentDay().SnapEnd(
-						current,
-						int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
-					)
-					if err != nil {
-						log.Warn().Err(err).Msg("unable to snap")
-					}
-					ensureEventsPaneTimestampVisible(current.End)
-				}),
-				"k": action.NewSimple(func() string { return "decrease size (shorten)" }, func() {
-					current := controller.data.GetCurrentDay().Current
-					controller.data.GetCurrentDay().ResizeBy(
-						current,
-						-int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
-					)
-					controller.data.GetCurrentDay().SnapEnd(
-						current,
-						int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
-					)
-					ensureEventsPaneTimestampVisible(current.End)
-				}),
-				"r":     action.NewSimple(func() string { return "exit resize mode" }, func() { dayEventsPane.PopModalOverlay(); controller.data.EventEditMode = edit.EventEditModeNormal }),
-				"<esc>": action.NewSimple(func() string { return "exit resize mode" }, func() { dayEventsPane.PopModalOverlay(); controller.data.EventEditMode = edit.EventEditModeNormal }),
-			},
-		)
-		if err != nil {
-			log.Error().Err(err).Msg("failed to construct input tree for event pane's resize mode (this should really never happen)")
-			return
-		}
-		dayEventsPane.ApplyModalOverlay(input.CapturingOverlayWrap(eventResizeOverlay))
-		controller.data.EventEditMode = edit.EventEditModeResize
-	})}
+	actions.register(map[input.Actionspec]action.Action{
+		"move.to-now": action.NewSimple(func() string { return "move to now" }, func() {
+			current := controller.data.GetCurrentDay().Current
+			if current == nil {
+				return
+			}
+			newStart := *model.NewTimestampFromGotime(time.Now())
+			controller.data.GetCurrentDay().MoveSingleEventTo(current, newStart)
+			ensureEventsPaneTimestampVisible(current.Start)
+			ensureEventsPaneTimestampVisible(current.End)
+		}),
+		"move.down": action.NewSimple(func() string { return "move down" }, func() {
+			current := controller.data.GetCurrentDay().Current
+			if current == nil {
+				return
+			}
+			controller.data.GetCurrentDay().MoveSingleEventBy(
+				current,
+				int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
+				int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
+			)
+			ensureEventsPaneTimestampVisible(current.End)
+		}),
+		"move.up": action.NewSimple(func() string { return "move up" }, func() {
+			current := controller.data.GetCurrentDay().Current
+			if current == nil {
+				return
+			}
+			controller.data.GetCurrentDay().MoveSingleEventBy(
+				current,
+				-int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
+				int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
+			)
+			ensureEventsPaneTimestampVisible(current.Start)
+		}),
+		"move.to-previous-day": action.NewSimple(func() string { return "move to previous day" }, func() {
+			event := controller.data.GetCurrentDay().Current
+			if event == nil {
+				return
+			}
+			controller.data.GetCurrentDay().RemoveEvent(event)
+			controller.goToPreviousDay()
+			controller.data.GetCurrentDay().AddEvent(event)
+		}),
+		"move.to-next-day": action.NewSimple(func() string { return "move to next day" }, func() {
+			event := controller.data.GetCurrentDay().Current
+			if event == nil {
+				return
+			}
+			controller.data.GetCurrentDay().RemoveEvent(event)
+			controller.goToNextDay()
+			controller.data.GetCurrentDay().AddEvent(event)
+		}),
+		"move.exit": action.NewSimple(func() string { return "exit move mode" }, exitEventsMode),
+	})
+	startMove = func() { enterEventsMode("move", edit.EventEditModeMove) }
 
-	var helpContentRegister func()
-	rootPaneInputTree, err := input.ConstructInputTree(
-		map[input.Keyspec]action.Action{
-			"q": action.NewSimple(func() string { return "exit program (unsaved progress is lost)" }, func() { controller.controllerEvents <- controllerEventExit }),
-			"P": action.NewSimple(func() string { return "show debug perf pane" }, func() { controller.data.ShowDebug = !controller.data.ShowDebug }),
-			"S": action.NewSimple(func() string { return "open summary" }, func() { controller.data.ShowSummary = true }),
-			"E": action.NewSimple(func() string { return "toggle log" }, func() { controller.data.ShowLog = !controller.data.ShowLog }),
-			"?": action.NewSimple(func() string { return "toggle help" }, func() {
-				helpContentRegister()
-				controller.data.ShowHelp = true
-			}),
-		},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to construct input tree for root pane (%w)", err)
+	actions.register(map[input.Actionspec]action.Action{
+		"resize.to-now": action.NewSimple(func() string { return "resize to now" }, func() {
+			current := controller.data.GetCurrentDay().Current
+			if current == nil {
+				return
+			}
+			newEnd := *model.NewTimestampFromGotime(time.Now())
+			controller.data.GetCurrentDay().ResizeTo(current, newEnd)
+			ensureEventsPaneTimestampVisible(newEnd)
+		}),
+		"resize.lengthen": action.NewSimple(func() string { return "increase size (lengthen)" }, func() {
+			var err error
+			current := controller.data.GetCurrentDay().Current
+			if current == nil {
+				return
+			}
+			err = controller.data.GetCurrentDay().ResizeBy(
+				current,
+				int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
+			)
+			if err != nil {
+				log.Warn().Err(err).Msg("unable to resize")
+			}
+			err = controller.data.GetCurrentDay().SnapEnd(
+				current,
+				int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
+			)
+			if err != nil {
+				log.Warn().Err(err).Msg("unable to snap")
+			}
+			ensureEventsPaneTimestampVisible(current.End)
+		}),
+		"resize.shorten": action.NewSimple(func() string { return "decrease size (shorten)" }, func() {
+			current := controller.data.GetCurrentDay().Current
+			if current == nil {
+				return
+			}
+			controller.data.GetCurrentDay().ResizeBy(
+				current,
+				-int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
+			)
+			controller.data.GetCurrentDay().SnapEnd(
+				current,
+				int(controller.data.MainTimelineViewParams.DurationOfHeight(1)/time.Minute),
+			)
+			ensureEventsPaneTimestampVisible(current.End)
+		}),
+		"resize.exit": action.NewSimple(func() string { return "exit resize mode" }, exitEventsMode),
+	})
+	startResize = func() { enterEventsMode("resize", edit.EventEditModeResize) }
+
+	var helpContentRegister func()
+	actions.register(map[input.Actionspec]action.Action{
+		"quit":         action.NewSimple(func() string { return "exit program (unsaved progress is lost)" }, func() { go func() { controller.controllerEvents <- controllerEventExit }() }),
+		"toggle-debug": action.NewSimple(func() string { return "show debug perf pane" }, func() { controller.data.ShowDebug = !controller.data.ShowDebug }),
+		"summary.open": action.NewSimple(func() string { return "open summary" }, func() { controller.data.ShowSummary = true }),
+		"toggle-log":   action.NewSimple(func() string { return "toggle log" }, func() { controller.data.ShowLog = !controller.data.ShowLog }),
+		"help.open": action.NewSimple(func() string { return "toggle help" }, func() {
+			helpContentRegister()
+			controller.data.ShowHelp = true
+		}),
+		"date.today": action.NewSimple(func() string { return "go to today" }, func() {
+			controller.jumpToDay(model.FromTime(time.Now()).Date)
+		}),
+		"date.previous-week": action.NewSimple(func() string { return "go back a week" }, func() {
+			controller.jumpToDay(controller.data.CurrentDate.Backward(7))
+		}),
+		"date.next-week": action.NewSimple(func() string { return "go forward a week" }, func() {
+			controller.jumpToDay(controller.data.CurrentDate.Forward(7))
+		}),
+		"date.previous-month": action.NewSimple(func() string { return "go back a month" }, func() {
+			controller.jumpToDay(controller.data.CurrentDate.AddMonths(-1))
+		}),
+		"date.next-month": action.NewSimple(func() string { return "go forward a month" }, func() {
+			controller.jumpToDay(controller.data.CurrentDate.AddMonths(+1))
+		}),
+		"jumplist.back": action.NewSimple(func() string { return "go back to the date jumped from" }, func() {
+			if date, ok := controller.data.Jumps.Back(controller.data.CurrentDate); ok {
+				controller.goToDay(date)
+			}
+		}),
+		"jumplist.forward": action.NewSimple(func() string { return "go forward to the date jumped to" }, func() {
+			if date, ok := controller.data.Jumps.Forward(); ok {
+				controller.goToDay(date)
+			}
+		}),
+	})
+	rootPaneInputTree, err := bindings.tree("root", "normal", actions)
+	if err != nil {
+		return nil, err
 	}
 	var ensureDayViewMainPaneFocusIsOnVisible func()
 	updateMainPaneRightFlexWidth := func() {
@@ -1177,22 +1312,21 @@ func NewController(
 	}
 
 	var dayViewFocusNext, dayViewFocusPrev func()
-	dayViewInputTree, err := input.ConstructInputTree(
-		map[input.Keyspec]action.Action{
-			"W":      action.NewSimple(func() string { return "update weather" }, controller.updateWeather),
-			"t":      action.NewSimple(func() string { return "toggle tools pane" }, toggleToolsPane),
-			"T":      action.NewSimple(func() string { return "toggle tasks pane" }, toggleTasksPane),
-			"<c-w>h": action.NewSimple(func() string { return "switch to previous pane" }, func() { dayViewFocusPrev() }),
-			"<c-w>l": action.NewSimple(func() string { return "switch to next pane" }, func() { dayViewFocusNext() }),
-		},
-	)
+	actions.register(map[input.Actionspec]action.Action{
+		"weather.update": action.NewSimple(func() string { return "update weather" }, controller.updateWeather),
+		"tools.toggle":   action.NewSimple(func() string { return "toggle tools pane" }, toggleToolsPane),
+		"tasks.toggle":   action.NewSimple(func() string { return "toggle tasks pane" }, toggleTasksPane),
+		"focus.previous": action.NewSimple(func() string { return "switch to previous pane" }, func() { dayViewFocusPrev() }),
+		"focus.next":     action.NewSimple(func() string { return "switch to next pane" }, func() { dayViewFocusNext() }),
+	})
+	dayViewInputTree, err := bindings.tree("day-view", "normal", actions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct input tree for day view pane (%w)", err)
+		return nil, err
 	}
 
-	dayViewScrollablePaneInputTree, err := input.ConstructInputTree(scrollableZoomableInputMap)
+	dayViewScrollablePaneInputTree, err := bindings.tree("timeline", "normal", actions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct input tree for day view scrollable pane (%w)", err)
+		return nil, err
 	}
 	dayViewScrollablePane := panes.NewWrapperPane(
 		[]ui.Pane{
@@ -1225,25 +1359,22 @@ func NewController(
 		},
 		processors.NewModalInputProcessor(dayViewScrollablePaneInputTree),
 	)
-	multidayViewEventsWrapperInputMap := scrollableZoomableInputMap
-	multidayViewEventsWrapperInputMap["h"] = action.NewSimple(func() string { return "go to previous day" }, controller.goToPreviousDay)
-	multidayViewEventsWrapperInputMap["l"] = action.NewSimple(func() string { return "go to next day" }, controller.goToNextDay)
-	weekViewEventsWrapperInputTree, err := input.ConstructInputTree(multidayViewEventsWrapperInputMap)
+	weekViewEventsWrapperInputTree, err := bindings.tree("multiday", "normal", actions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct input tree for multi-day wrapper pane (%w)", err)
+		return nil, err
 	}
 	weekViewEventsWrapper := panes.NewWrapperPane(
 		weekViewEventsPanes,
-		[]ui.Pane{},
+		weekViewEventsPanes,
 		processors.NewModalInputProcessor(weekViewEventsWrapperInputTree),
 	)
-	monthViewEventsWrapperInputTree, err := input.ConstructInputTree(multidayViewEventsWrapperInputMap)
+	monthViewEventsWrapperInputTree, err := bindings.tree("multiday", "normal", actions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct input tree for month view wrapper pane (%w)", err)
+		return nil, err
 	}
 	monthViewEventsWrapper := panes.NewWrapperPane(
 		monthViewEventsPanes,
-		[]ui.Pane{},
+		monthViewEventsPanes,
 		processors.NewModalInputProcessor(monthViewEventsWrapperInputTree),
 	)
 
@@ -1262,9 +1393,9 @@ func NewController(
 		processors.NewModalInputProcessor(dayViewInputTree),
 	)
 	ensureDayViewMainPaneFocusIsOnVisible = dayViewMainPane.EnsureFocusIsOnVisible
-	weekViewMainPaneInputTree, err := input.ConstructInputTree(map[input.Keyspec]action.Action{})
+	weekViewMainPaneInputTree, err := bindings.tree("week-view", "normal", actions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct input tree for week view main pane (%w)", err)
+		return nil, err
 	}
 	weekViewMainPane := panes.NewWrapperPane(
 		[]ui.Pane{
@@ -1284,34 +1415,160 @@ func NewController(
 		},
 		processors.NewModalInputProcessor(weekViewMainPaneInputTree),
 	)
-	monthViewMainPaneInputTree, err := input.ConstructInputTree(scrollableZoomableInputMap)
+	monthGridInputTree, err := bindings.tree("month-grid", "normal", actions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct input tree for month view main pane (%w)", err)
+		return nil, err
+	}
+	monthGridPane := panes.NewMonthGridPane(
+		ui.NewConstrainedRenderer(renderer, monthGridDimensions),
+		monthGridDimensions,
+		stylesheet,
+		processors.NewModalInputProcessor(monthGridInputTree),
+		&controller.data.CurrentDate,
+		controller.data.Days.GetDay,
+		&categoryStyling,
+		func() bool { return monthGrid },
+	)
+	monthViewEventsMaybe := panes.NewMaybePane(func() bool { return !monthGrid }, monthViewEventsWrapper)
+	monthViewMainPaneInputTree, err := bindings.tree("month-view", "normal", actions)
+	if err != nil {
+		return nil, err
 	}
 	monthViewMainPane := panes.NewWrapperPane(
 		[]ui.Pane{
 			statusPane,
-			panes.NewTimelinePane(
-				ui.NewConstrainedRenderer(renderer, monthViewTimelineDimensions),
-				monthViewTimelineDimensions,
-				stylesheet,
-				func() *model.SunTimes { return nil },
-				func() *model.Timestamp { return nil },
-				&controller.data.MainTimelineViewParams,
+			panes.NewMaybePane(
+				func() bool { return !monthGrid },
+				panes.NewTimelinePane(
+					ui.NewConstrainedRenderer(renderer, monthViewTimelineDimensions),
+					monthViewTimelineDimensions,
+					stylesheet,
+					func() *model.SunTimes { return nil },
+					func() *model.Timestamp { return nil },
+					&controller.data.MainTimelineViewParams,
+				),
 			),
-			monthViewEventsWrapper,
+			monthViewEventsMaybe,
+			monthGridPane,
 		},
 		[]ui.Pane{
-			monthViewEventsWrapper,
+			monthViewEventsMaybe,
+			monthGridPane,
 		},
 		processors.NewModalInputProcessor(monthViewMainPaneInputTree),
 	)
+	actions.register(map[input.Actionspec]action.Action{
+		"month-view.toggle-grid": action.NewSimple(func() string { return "toggle month view between days' events and calendar grid" }, func() {
+			monthGrid = !monthGrid
+			monthViewMainPane.EnsureFocusIsOnVisible()
+		}),
+		"month-grid.up": action.NewSimple(func() string { return "go to day a week earlier" }, func() {
+			controller.goToDay(controller.data.CurrentDate.Backward(7))
+		}),
+		"month-grid.down": action.NewSimple(func() string { return "go to day a week later" }, func() {
+			controller.goToDay(controller.data.CurrentDate.Forward(7))
+		}),
+	})
+	heatmapMode := panes.HeatmapTotal
+	yearPaneInputTree, err := bindings.tree("year", "normal", actions)
+	if err != nil {
+		return nil, err
+	}
+	yearPane := panes.NewYearPane(
+		ui.NewConstrainedRenderer(renderer, yearDimensions),
+		yearDimensions,
+		stylesheet,
+		processors.NewModalInputProcessor(yearPaneInputTree),
+		&controller.data.CurrentDate,
+		controller.data.Days.GetDay,
+		&heatmapMode,
+		&controller.data.CurrentCategory,
+		&categoryStyling,
+	)
+	yearViewMainPaneInputTree, err := bindings.tree("year-view", "normal", actions)
+	if err != nil {
+		return nil, err
+	}
+	yearViewMainPane := panes.NewWrapperPane(
+		[]ui.Pane{
+			statusPane,
+			yearPane,
+		},
+		[]ui.Pane{
+			yearPane,
+		},
+		processors.NewModalInputProcessor(yearViewMainPaneInputTree),
+	)
+	actions.register(map[input.Actionspec]action.Action{
+		"year.previous-week": action.NewSimple(func() string { return "go to day a week earlier" }, func() {
+			controller.goToDay(controller.data.CurrentDate.Backward(7))
+		}),
+		"year.next-week": action.NewSimple(func() string { return "go to day a week later" }, func() {
+			controller.goToDay(controller.data.CurrentDate.Forward(7))
+		}),
+		"year.cycle-mode": action.NewSimple(func() string { return "color by total time, current category's time, or goals" }, func() {
+			heatmapMode = heatmapMode.Next()
+		}),
+	})
+	agendaPaneInputTree, err := bindings.tree("agenda", "normal", actions)
+	if err != nil {
+		return nil, err
+	}
+	agendaPane := panes.NewAgendaPane(
+		ui.NewConstrainedRenderer(renderer, agendaDimensions),
+		agendaDimensions,
+		stylesheet,
+		processors.NewModalInputProcessor(agendaPaneInputTree),
+		func() model.Date { return model.FromTime(time.Now()).Date },
+		agendaDays,
+		controller.data.Days.GetDay,
+		backlog,
+		&categoryStyling,
+	)
+	agendaViewMainPaneInputTree, err := bindings.tree("agenda-view", "normal", actions)
+	if err != nil {
+		return nil, err
+	}
+	agendaViewMainPane := panes.NewWrapperPane(
+		[]ui.Pane{
+			statusPane,
+			agendaPane,
+		},
+		[]ui.Pane{
+			agendaPane,
+		},
+		processors.NewModalInputProcessor(agendaViewMainPaneInputTree),
+	)
+	actions.register(map[input.Actionspec]action.Action{
+		"agenda.next":         action.NewSimple(func() string { return "select next row" }, agendaPane.SelectNext),
+		"agenda.previous":     action.NewSimple(func() string { return "select previous row" }, agendaPane.SelectPrevious),
+		"agenda.next-day":     action.NewSimple(func() string { return "select next day" }, agendaPane.SelectNextDay),
+		"agenda.previous-day": action.NewSimple(func() string { return "select (previous) day" }, agendaPane.SelectPreviousDay),
+		"agenda.first":        action.NewSimple(func() string { return "select first row" }, agendaPane.SelectFirst),
+		"agenda.last":         action.NewSimple(func() string { return "select last row" }, agendaPane.SelectLast),
+	})
 	dayViewFocusNext = dayViewMainPane.FocusNext
 	dayViewFocusPrev = dayViewMainPane.FocusPrev
+	controller.focusCurrentDate = func() {
+		weekViewEventsWrapper.Focus(weekViewEventsPanes[(int(controller.data.CurrentDate.ToWeekday())+6)%7])
+		monthViewEventsWrapper.Focus(monthViewEventsPanes[controller.data.CurrentDate.Day-1])
+	}
+	activeEventsPane = func() ui.Pane {
+		switch controller.data.ActiveView() {
+		case ui.ViewDay:
+			return dayEventsPane
+		case ui.ViewWeek:
+			return weekViewEventsWrapper
+		case ui.ViewMonth:
+			return monthViewEventsWrapper
+		default:
+			panic("unknown view")
+		}
+	}
 
-	summaryPaneInputTree, err := input.ConstructInputTree(map[input.Keyspec]action.Action{
-		"S": action.NewSimple(func() string { return "close summary" }, func() { controller.data.ShowSummary = false }),
-		"h": action.NewSimple(func() string { return "switch to previous day/week/month" }, func() {
+	actions.register(map[input.Actionspec]action.Action{
+		"summary.close": action.NewSimple(func() string { return "close summary" }, func() { controller.data.ShowSummary = false }),
+		"summary.previous": action.NewSimple(func() string { return "switch to previous day/week/month/year" }, func() {
 			switch controller.data.ActiveView() {
 			case ui.ViewDay:
 				controller.goToPreviousDay()
@@ -1319,11 +1576,15 @@ func NewController(
 				controller.goToDay(controller.data.CurrentDate.GetDayInWeek(0).Backward(7))
 			case ui.ViewMonth:
 				controller.goToDay(controller.data.CurrentDate.GetDayInMonth(0).Prev().GetDayInMonth(0))
+			case ui.ViewYear:
+				controller.goToDay(controller.data.CurrentDate.AddMonths(-12))
+			case ui.ViewAgenda:
+				// the agenda always starts today
 			default:
 				panic("unknown view")
 			}
 		}),
-		"l": action.NewSimple(func() string { return "switch to next day/week/month" }, func() {
+		"summary.next": action.NewSimple(func() string { return "switch to next day/week/month/year" }, func() {
 			switch controller.data.ActiveView() {
 			case ui.ViewDay:
 				controller.goToNextDay()
@@ -1331,24 +1592,28 @@ func NewController(
 				controller.goToDay(controller.data.CurrentDate.GetDayInWeek(6).Forward(7))
 			case ui.ViewMonth:
 				controller.goToDay(controller.data.CurrentDate.GetLastOfMonth().Next().GetLastOfMonth())
+			case ui.ViewYear:
+				controller.goToDay(controller.data.CurrentDate.AddMonths(12))
+			case ui.ViewAgenda:
+				// the agenda always starts today
 			default:
 				panic("unknown view")
 			}
 		}),
 	})
+	summaryPaneInputTree, err := bindings.tree("summary", "normal", actions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct input tree for summary pane (%w)", err)
+		return nil, err
 	}
 
-	helpPaneInputTree, err := input.ConstructInputTree(
-		map[input.Keyspec]action.Action{
-			"?": action.NewSimple(func() string { return "close help" }, func() {
-				controller.data.ShowHelp = false
-			}),
-		},
-	)
+	actions.register(map[input.Actionspec]action.Action{
+		"help.close": action.NewSimple(func() string { return "close help" }, func() {
+			controller.data.ShowHelp = false
+		}),
+	})
+	helpPaneInputTree, err := bindings.tree("help", "normal", actions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct input tree for help pane (%w)", err)
+		return nil, err
 	}
 	helpPane := panes.NewHelpPane(
 		ui.NewConstrainedRenderer(renderer, helpDimensions),
@@ -1358,6 +1623,237 @@ func NewController(
 		processors.NewModalInputProcessor(helpPaneInputTree),
 	)
 
+	commandHistoryPath := path.Join(envData.BaseDirPath, storage.CommandHistoryFileName)
+	commandHistory := &command.History{Max: 100}
+	commandHistory.Entries, err = storage.ReadCommandHistory(commandHistoryPath)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not read command line history")
+		commandHistory.Entries = []string{}
+	}
+	categoryNames := func() []string {
+		result := make([]string, 0, len(controller.data.Categories))
+		for _, cat := range controller.data.Categories {
+			if !cat.Deprecated {
+				result = append(result, cat.Name)
+			}
+		}
+		return result
+	}
+	findCategory := func(name string) (model.Category, error) {
+		for _, cat := range controller.data.Categories {
+			if cat.Name == name {
+				return cat, nil
+			}
+		}
+		return model.Category{}, fmt.Errorf("unknown category '%s'", name)
+	}
+	templatesDir := path.Join(envData.BaseDirPath, storage.TemplatesDirName)
+	commands := command.NewRegistry(actions)
+	commands.ScopeToModes("move.", "move-pushing.", "resize.")
+	controller.commands = commands
+	commands.Register(
+		command.Command{Name: "w", Action: "day.write"},
+		command.Command{Name: "wa", Action: "days.write-all"},
+		command.Command{Name: "q", Action: "quit"},
+		command.Command{
+			Name:    "goto",
+			Args:    "<date>",
+			Explain: func() string { return "go to date (e.g. '2026-10-01', 'next fri', '+2w')" },
+			Run: func(args []string) error {
+				date, err := model.ParseRelativeDate(strings.Join(args, " "), model.FromTime(time.Now()).Date, controller.data.CurrentDate)
+				if err != nil {
+					return err
+				}
+				controller.jumpToDay(date)
+				return nil
+			},
+			Complete: func(args []string) []string {
+				weekdays := []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+				switch {
+				case len(args) == 1:
+					return append([]string{"today", "yesterday", "tomorrow", "next", "last"}, weekdays...)
+				case len(args) == 2 && (args[0] == "next" || args[0] == "last"):
+					return weekdays
+				default:
+					return nil
+				}
+			},
+		},
+		command.Command{
+			Name:    "category",
+			Args:    "<category>",
+			Explain: func() string { return "switch to category (for new events and tasks)" },
+			Run: func(args []string) error {
+				cat, err := findCategory(strings.Join(args, " "))
+				if err != nil {
+					return err
+				}
+				controller.data.CurrentCategory = cat
+				return nil
+			},
+			Complete: func(args []string) []string {
+				if len(args) == 1 {
+					return categoryNames()
+				}
+				return nil
+			},
+		},
+		command.Command{
+			Name:    "add",
+			Args:    "<HH:MM>-<HH:MM> <category> [<name>]",
+			Explain: func() string { return "add event to current day" },
+			Run: func(args []string) error {
+				if len(args) < 2 {
+					return fmt.Errorf("expected a time range and a category")
+				}
+				startString, endString, ok := strings.Cut(args[0], "-")
+				if !ok {
+					return fmt.Errorf("time range '%s' not of the form <HH:MM>-<HH:MM>", args[0])
+				}
+				start, err := model.TimestampFromString(startString)
+				if err != nil {
+					return err
+				}
+				end, err := model.TimestampFromString(endString)
+				if err != nil {
+					return err
+				}
+				if !end.IsAfter(start) {
+					return fmt.Errorf("end time %s is not after start time %s", end.ToString(), start.ToString())
+				}
+				cat, err := findCategory(args[1])
+				if err != nil {
+					return err
+				}
+				return controller.data.GetCurrentDay().AddEvent(&model.Event{
+					Start: start,
+					End:   end,
+					Name:  strings.Join(args[2:], " "),
+					Cat:   cat,
+				})
+			},
+			Complete: func(args []string) []string {
+				if len(args) == 2 {
+					return categoryNames()
+				}
+				return nil
+			},
+		},
+		command.Command{
+			Name:    "template",
+			Args:    "apply|save <template>",
+			Explain: func() string { return "add the events of a template to the current day, or save it as one" },
+			Run: func(args []string) error {
+				if len(args) != 2 {
+					return fmt.Errorf("expected 'apply' or 'save' and a template name")
+				}
+				switch args[0] {
+				case "apply":
+					template, err := storage.ReadTemplate(templatesDir, args[1], controller.data.Categories)
+					if err != nil {
+						return err
+					}
+					for _, event := range template.Events {
+						err := controller.data.GetCurrentDay().AddEvent(event)
+						if err != nil {
+							return err
+						}
+					}
+					return nil
+				case "save":
+					return storage.WriteTemplate(templatesDir, args[1], controller.data.GetCurrentDay())
+				default:
+					return fmt.Errorf("unknown subcommand '%s' (expected 'apply' or 'save')", args[0])
+				}
+			},
+			Complete: func(args []string) []string {
+				switch len(args) {
+				case 1:
+					return []string{"apply", "save"}
+				case 2:
+					templates, err := storage.ListTemplates(templatesDir)
+					if err != nil {
+						log.Warn().Err(err).Msg("could not list templates")
+					}
+					return templates
+				default:
+					return nil
+				}
+			},
+		},
+	)
+
+	withCommandLine := func(f func(prompt *command.Prompt)) func() {
+		return func() {
+			if controller.data.CommandLine != nil {
+				f(controller.data.CommandLine)
+			}
+		}
+	}
+	closeCommandLine := func() { controller.data.CommandLine = nil }
+	actions.register(map[input.Actionspec]action.Action{
+		"days.write-all": action.NewSimple(func() string { return "write all loaded days and the backlog to file" }, func() {
+			controller.writeAllDays()
+			actions["tasks.write"].Do()
+		}),
+		"command-line.open": action.NewSimple(func() string { return "open command line" }, func() {
+			controller.data.Message = ""
+			controller.data.CommandLine = command.NewPrompt(commandHistory, commands.Complete)
+		}),
+		"date.prompt": action.NewSimple(func() string { return "go to date (entered on command line)" }, func() {
+			actions["command-line.open"].Do()
+			controller.data.CommandLine.SetContent("goto ")
+		}),
+		"command-line.execute": action.NewSimple(func() string { return "run command" }, withCommandLine(func(prompt *command.Prompt) {
+			line := prompt.Submit()
+			closeCommandLine()
+			err := storage.WriteCommandHistory(commandHistoryPath, commandHistory.Entries)
+			if err != nil {
+				log.Warn().Err(err).Msg("could not write command line history")
+			}
+			err = commands.Run(line)
+			if err != nil {
+				log.Error().Err(err).Str("command", line).Msg("command failed")
+				controller.data.Message = err.Error()
+			}
+		})),
+		"command-line.cancel":            action.NewSimple(func() string { return "close command line" }, closeCommandLine),
+		"command-line.complete":          action.NewSimple(func() string { return "complete (next)" }, withCommandLine(func(prompt *command.Prompt) { prompt.Complete(false) })),
+		"command-line.complete-previous": action.NewSimple(func() string { return "complete (previous)" }, withCommandLine(func(prompt *command.Prompt) { prompt.Complete(true) })),
+		"command-line.history-previous":  action.NewSimple(func() string { return "previous command from history" }, withCommandLine(func(prompt *command.Prompt) { prompt.HistoryPrevious() })),
+		"command-line.history-next":      action.NewSimple(func() string { return "next command from history" }, withCommandLine(func(prompt *command.Prompt) { prompt.HistoryNext() })),
+		"command-line.left":              action.NewSimple(func() string { return "move cursor left" }, withCommandLine(func(prompt *command.Prompt) { prompt.MoveLeft() })),
+		"command-line.right":             action.NewSimple(func() string { return "move cursor right" }, withCommandLine(func(prompt *command.Prompt) { prompt.MoveRight() })),
+		"command-line.start":             action.NewSimple(func() string { return "move cursor to start" }, withCommandLine(func(prompt *command.Prompt) { prompt.MoveToStart() })),
+		"command-line.end":               action.NewSimple(func() string { return "move cursor to end" }, withCommandLine(func(prompt *command.Prompt) { prompt.MoveToEnd() })),
+		"command-line.backspace": action.NewSimple(func() string { return "delete character before cursor (close if empty)" }, withCommandLine(func(prompt *command.Prompt) {
+			if !prompt.Backspace() {
+				closeCommandLine()
+			}
+		})),
+		"command-line.delete":      action.NewSimple(func() string { return "delete character under cursor" }, withCommandLine(func(prompt *command.Prompt) { prompt.Delete() })),
+		"command-line.delete-word": action.NewSimple(func() string { return "delete word before cursor" }, withCommandLine(func(prompt *command.Prompt) { prompt.DeleteWordBackward() })),
+		"command-line.clear":       action.NewSimple(func() string { return "delete everything before cursor" }, withCommandLine(func(prompt *command.Prompt) { prompt.Clear() })),
+	})
+	commandLineMappings, err := bindings.mappings("command-line", "insert", actions)
+	if err != nil {
+		return nil, err
+	}
+	commandLineInputProcessor, err := processors.NewTextInputProcessor(commandLineMappings, func(r rune) {
+		withCommandLine(func(prompt *command.Prompt) { prompt.Insert(r) })()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid keybindings for pane 'command-line' in mode 'insert' (%w)", err)
+	}
+	commandLinePane := panes.NewCommandLinePane(
+		ui.NewConstrainedRenderer(renderer, statusDimensions),
+		statusDimensions,
+		cursorWrangler,
+		stylesheet,
+		processors.NewModalInputProcessor(commandLineInputProcessor),
+		func() *command.Prompt { return controller.data.CommandLine },
+	)
+
 	rootPane := panes.NewRootPane(
 		renderer,
 		cursorWrangler,
@@ -1366,6 +1862,8 @@ func NewController(
 		dayViewMainPane,
 		weekViewMainPane,
 		monthViewMainPane,
+		yearViewMainPane,
+		agendaViewMainPane,
 
 		panes.NewSummaryPane(
 			ui.NewConstrainedRenderer(renderer, screenDimensions),
@@ -1382,6 +1880,11 @@ func NewController(
 					dateString = fmt.Sprintf("week %s..%s", start.ToString(), end.ToString())
 				case ui.ViewMonth:
 					dateString = fmt.Sprintf("%s %d", controller.data.CurrentDate.ToGotime().Month().String(), controller.data.CurrentDate.Year)
+				case ui.ViewYear:
+					dateString = fmt.Sprint(controller.data.CurrentDate.Year)
+				case ui.ViewAgenda:
+					today := model.FromTime(time.Now()).Date
+					dateString = fmt.Sprintf("agenda %s..%s", today.ToString(), today.Forward(agendaDays-1).ToString())
 				}
 				return fmt.Sprintf("SUMMARY (%s)", dateString)
 			},
@@ -1407,10 +1910,41 @@ func NewController(
 						i++
 					}
 					return result
+				case ui.ViewAgenda:
+					result := make([]*model.Day, agendaDays)
+					today := model.FromTime(time.Now()).Date
+					for i := range result {
+						result[i] = controller.data.Days.GetDay(today.Forward(i))
+					}
+					return result
+				case ui.ViewYear:
+					result := make([]*model.Day, 0, 366)
+					for current := (model.Date{Year: controller.data.CurrentDate.Year, Month: 1, Day: 1}); current.Year == controller.data.CurrentDate.Year; current = current.Next() {
+						result = append(result, controller.data.Days.GetDay(current))
+					}
+					return result
 				default:
 					panic("unknown view in summary data gathering")
 				}
 			},
+			func() model.Date {
+				switch controller.data.ActiveView() {
+				case ui.ViewWeek:
+					start, _ := controller.data.CurrentDate.WeekBounds()
+					return start
+				case ui.ViewMonth:
+					start, _ := controller.data.CurrentDate.MonthBounds()
+					return start
+				case ui.ViewYear:
+					return model.Date{Year: controller.data.CurrentDate.Year, Month: 1, Day: 1}
+				case ui.ViewAgenda:
+					return model.FromTime(time.Now()).Date
+				default:
+					return controller.data.CurrentDate
+				}
+			},
+			func() bool { return controller.data.ActiveView() == ui.ViewWeek },
+			patternGoals,
 			&categoryStyling,
 			processors.NewModalInputProcessor(summaryPaneInputTree),
 		),
@@ -1423,6 +1957,7 @@ func NewController(
 			&potatolog.GlobalMemoryLogReaderWriter,
 		),
 		helpPane,
+		commandLinePane,
 
 		panes.NewPerfPane(
 			ui.NewConstrainedRenderer(renderer, func() (x, y, w, h int) { return 2, 2, 50, 2 }),
@@ -1435,21 +1970,52 @@ func NewController(
 		dayViewMainPane,
 	)
 	controller.data.ActiveView = rootPane.GetView
-	rootPaneInputTree.Root.Children[input.Key{Key: tcell.KeyESC}] = &input.Node{
-		Action: action.NewSimple(func() string { return "view up" }, func() {
+	actions.register(map[input.Actionspec]action.Action{
+		"view.up": action.NewSimple(func() string { return "view up" }, func() {
 			rootPane.ViewUp()
 			controller.loadDaysForView(controller.data.ActiveView())
 		}),
-	}
-	rootPaneInputTree.Root.Children[input.Key{Key: tcell.KeyRune, Ch: 'i'}] = &input.Node{
-		Action: action.NewSimple(func() string { return "view down" }, func() {
+		"view.down": action.NewSimple(func() string { return "view down" }, func() {
 			rootPane.ViewDown()
 			controller.loadDaysForView(controller.data.ActiveView())
 		}),
+		"month-grid.open-day": action.NewSimple(func() string { return "open day view for day" }, func() {
+			rootPane.SetView(ui.ViewDay)
+			controller.loadDaysForView(controller.data.ActiveView())
+		}),
+		"year.open-day": action.NewSimple(func() string { return "open day view for day" }, func() {
+			rootPane.SetView(ui.ViewDay)
+			controller.loadDaysForView(controller.data.ActiveView())
+		}),
+		"view.agenda": action.NewSimple(func() string { return "toggle agenda view" }, func() {
+			if controller.data.ActiveView() == ui.ViewAgenda {
+				rootPane.SetView(ui.ViewDay)
+			} else {
+				rootPane.SetView(ui.ViewAgenda)
+			}
+			controller.loadDaysForView(controller.data.ActiveView())
+		}),
+		"agenda.open": action.NewSimple(func() string { return "open selected row in day view" }, func() {
+			date, event, _ := agendaPane.Selected()
+			rootPane.SetView(ui.ViewDay)
+			controller.jumpToDay(date)
+			controller.loadDaysForView(controller.data.ActiveView())
+			if event != nil {
+				controller.data.Days.GetDay(date).Current = event
+				ensureEventsPaneTimestampVisible(event.Start)
+				ensureEventsPaneTimestampVisible(event.End)
+			}
+		}),
+	})
+	if err := bindings.validate(actions); err != nil {
+		return nil, err
 	}
 
 	helpContentRegister = func() {
 		helpPane.Content = rootPane.GetHelp()
+		for usage, explanation := range commands.Help() {
+			helpPane.Content[usage] = explanation
+		}
 	}
 
 	controller.data.EventEditMode = edit.EventEditModeNormal
@@ -1497,6 +2063,7 @@ func NewController(
 	controller.FileHandlers[date] = storage.NewFileHandler(controller.data.EnvData.BaseDirPath + "/days/" + date.ToString())
 
 	controller.data.CurrentDate = date
+	controller.focusCurrentDate()
 	if controller.FileHandlers[date] == nil {
 		controller.data.Days.AddDay(date, &model.Day{}, &suntimes)
 	} else {
@@ -1507,6 +2074,8 @@ func NewController(
 	controller.data.CurrentCategory.Name = "default"
 
 	controller.loadDaysForView(controller.data.ActiveView())
+	controller.updateTracking()
+	controller.balanceSettings = balanceSettings
 
 	controller.timestampGuesser = func(cursorX, cursorY int) model.Timestamp {
 		_, yOffset, _, _ := dayViewEventsPaneDimensions()
@@ -1601,9 +2170,20 @@ func (c *Controller) goToDay(newDate model.Date) {
 	log.Debug().Str("new-date", newDate.ToString()).Msg("going to new date")
 
 	c.data.CurrentDate = newDate
+	c.focusCurrentDate()
 	c.loadDaysForView(c.data.ActiveView())
 }
 
+// jumpToDay goes to the given date like goToDay, recording the jump from the
+// current date in the jumplist.
+func (c *Controller) jumpToDay(newDate model.Date) {
+	if newDate == c.data.CurrentDate {
+		return
+	}
+	c.data.Jumps.Jump(c.data.CurrentDate)
+	c.goToDay(newDate)
+}
+
 func (c *Controller) goToPreviousDay() {
 	prevDay := c.data.CurrentDate.Prev()
 	c.goToDay(prevDay)
@@ -1672,17 +2252,219 @@ func (c *Controller) loadDaysForView(view ui.ActiveView) {
 				}(current)
 			}
 		}
+	case ui.ViewAgenda:
+		{
+			today := model.FromTime(time.Now()).Date
+			for current := today; current != today.Forward(c.agendaDays); current = current.Next() {
+				go func(d model.Date) {
+					c.loadDay(d)
+					c.controllerEvents <- controllerEventRender
+				}(current)
+			}
+		}
+	case ui.ViewYear:
+		{
+			// only the days not yet loaded, as this is a lot of days to re-render for
+			for current := (model.Date{Year: c.data.CurrentDate.Year, Month: 1, Day: 1}); current.Year == c.data.CurrentDate.Year; current = current.Next() {
+				if c.data.Days.HasDay(current) {
+					continue
+				}
+				go func(d model.Date) {
+					c.loadDay(d)
+					c.controllerEvents <- controllerEventRender
+				}(current)
+			}
+		}
 	default:
 		panic("unknown ActiveView")
 	}
 }
 
+// updateTracking re-reads the live tracking state and, if an event is being
+// tracked, extends it to the current time.
+// If the tracked event was started (e.g., via the CLI) after its day was
+// loaded, it is added to the day once.
+// If the previously tracked event was stopped (e.g., via the CLI), it takes
+// the end it was stopped at from its day's file.
+//
+// The extension is only kept in memory, like any other change, and persisted
+// when the day is written; as the days are written, this is called first, so
+// that they are not written with a stale tracked event.
+func (c *Controller) updateTracking() {
+	running, err := tracking.Read(path.Join(c.data.EnvData.BaseDirPath, tracking.FileName))
+	if err != nil {
+		log.Warn().Err(err).Msg("could not read tracking state")
+		return
+	}
+	previous := c.data.Tracking
+	c.data.Tracking = running
+	if previous != nil && (running == nil || *running != *previous) {
+		c.adoptStoppedEnd(*previous)
+	}
+	if running == nil || !c.data.Days.HasDay(running.Date) {
+		return
+	}
+
+	day := c.data.Days.GetDay(running.Date)
+	now := model.FromTime(time.Now())
+	event := running.Find(day)
+	if event == nil {
+		// if the event has been seen before, it was changed or removed in the TUI
+		// and should not be resurrected
+		if previous != nil && *previous == *running {
+			return
+		}
+		err := day.AddEvent(running.ToEvent(now.Date, now.Timestamp, c.data.Categories))
+		if err != nil {
+			log.Warn().Err(err).Msg("could not add tracked event")
+		}
+		return
+	}
+	err = day.ResizeTo(event, running.EndAt(now.Date, now.Timestamp))
+	if err != nil {
+		log.Warn().Err(err).Msg("could not extend tracked event")
+	}
+}
+
+// adoptStoppedEnd sets the end of the given stopped event (if its day is
+// loaded) to the end it has in its day's file, where it was stopped.
+func (c *Controller) adoptStoppedEnd(stopped tracking.Running) {
+	if !c.data.Days.HasDay(stopped.Date) {
+		return
+	}
+	day := c.data.Days.GetDay(stopped.Date)
+	event := stopped.Find(day)
+	if event == nil {
+		return
+	}
+	stored := stopped.Find(storage.NewFileHandler(path.Join(c.data.EnvData.BaseDirPath, "days", stopped.Date.ToString())).Read(c.data.Categories))
+	if stored == nil {
+		return
+	}
+	err := day.ResizeTo(event, stored.End)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not adopt end of stopped tracked event")
+	}
+}
+
 func (c *Controller) writeModel() {
+	c.updateTracking()
 	go func() {
 		c.fhMutex.RLock()
 		c.FileHandlers[c.data.CurrentDate].Write(c.data.GetCurrentDay())
 		c.fhMutex.RUnlock()
 	}()
+	c.updateBalances()
+}
+
+// writeAllDays writes all loaded days to file, except for empty days that
+// have no file yet.
+func (c *Controller) writeAllDays() {
+	c.updateTracking()
+	for _, date := range c.data.Days.Dates() {
+		day := c.data.Days.GetDay(date)
+		filePath := path.Join(c.data.EnvData.BaseDirPath, "days", date.ToString())
+		if len(day.Events) == 0 {
+			if _, err := os.Stat(filePath); err != nil {
+				continue
+			}
+		}
+		c.fhMutex.Lock()
+		fh, ok := c.FileHandlers[date]
+		if !ok {
+			fh = storage.NewFileHandler(filePath)
+			c.FileHandlers[date] = fh
+		}
+		c.fhMutex.Unlock()
+		fh.Write(day)
+	}
+	c.updateBalances()
+}
+
+// cycleAbsence sets the current day's absence to the next kind (or none after
+// the last kind) and writes the absences to file.
+func (c *Controller) cycleAbsence() {
+	date := c.data.CurrentDate
+	current, ok := c.data.Absences.Get(date)
+	next := model.AbsenceKinds[0]
+	if ok {
+		i := 0
+		for i < len(model.AbsenceKinds) && model.AbsenceKinds[i] != current.Kind {
+			i++
+		}
+		if i+1 >= len(model.AbsenceKinds) {
+			c.data.Absences.Unset(date)
+			c.writeAbsences()
+			return
+		}
+		next = model.AbsenceKinds[i+1]
+	}
+	c.data.Absences.Set(date, model.Absence{Kind: next, Note: current.Note})
+	c.writeAbsences()
+}
+
+// writeAbsences writes the absences to file and updates the balances
+// accordingly.
+func (c *Controller) writeAbsences() {
+	err := storage.WriteAbsences(path.Join(c.data.EnvData.BaseDirPath, storage.AbsencesFileName), c.data.Absences)
+	if err != nil {
+		log.Error().Err(err).Msg("could not write absences")
+	}
+	c.updateBalances()
+}
+
+// updateBalances recomputes the category balances in the background (if
+// balances are configured), using the loaded days where available.
+// The computation works on a snapshot of the loaded days; the days that are
+// not loaded are read from file once and cached. The result is published via
+// a controllerEventBalancesUpdate (see publishBalances).
+func (c *Controller) updateBalances() {
+	if c.balanceSettings == nil {
+		return
+	}
+	settings := *c.balanceSettings
+	now := model.FromTime(time.Now())
+	categories := append([]model.Category{}, c.data.Categories...)
+	loaded := make(map[model.Date]*model.Day)
+	for date := settings.Start; !date.IsAfter(now.Date); date = date.Next() {
+		if c.data.Days.HasDay(date) {
+			loaded[date] = c.data.Days.GetDay(date).Clone()
+		}
+	}
+	c.balances.requested++
+	generation := c.balances.requested
+
+	go func() {
+		c.balances.Lock()
+		if c.balances.unloadedDays == nil {
+			c.balances.unloadedDays = make(map[model.Date]*model.Day)
+		}
+		result := balance.Compute(settings, now.Date, categories, func(date model.Date) *model.Day {
+			if day, ok := loaded[date]; ok {
+				return day
+			}
+			day, ok := c.balances.unloadedDays[date]
+			if !ok {
+				day = storage.NewFileHandler(path.Join(c.data.EnvData.BaseDirPath, "days", date.ToString())).Read(categories)
+				c.balances.unloadedDays[date] = day
+			}
+			return day
+		})
+		if generation > c.balances.computed {
+			c.balances.computed = generation
+			c.balances.result = result
+		}
+		c.balances.Unlock()
+		c.controllerEvents <- controllerEventBalancesUpdate
+	}()
+}
+
+// publishBalances makes the latest computed balances (see updateBalances) the
+// displayed ones.
+func (c *Controller) publishBalances() {
+	c.balances.Lock()
+	defer c.balances.Unlock()
+	c.data.Balances = c.balances.result
 }
 
 func (c *Controller) updateCursorPos(x, y int) {
@@ -1836,11 +2618,14 @@ const (
 	controllerEventRender
 	controllerEventTaskEditorExit
 	controllerEventEventEditorExit
+	controllerEventTrackingUpdate
+	controllerEventBalancesUpdate
 )
 
 // Empties all render events from the channel.
 // Returns true, if an exit event was encountered so the caller
 // knows to exit.
+// Other events are queued again, to be handled after the render.
 func emptyRenderEvents(c chan controllerEvent) bool {
 	for {
 		select {
@@ -1852,6 +2637,8 @@ func emptyRenderEvents(c chan controllerEvent) bool {
 				}
 			case controllerEventExit:
 				return true
+			default:
+				go func() { c <- bufferedEvent }()
 			}
 		default:
 			return false
@@ -1864,117 +2651,142 @@ func (c *Controller) Run() {
 	log.Info().Msg("dayplan TUI started")
 
 	c.controllerEvents = make(chan controllerEvent, 32)
+	screenEvents := make(chan tcell.Event)
 	var wg sync.WaitGroup
 
-	// Run the main render loop, that renders or exits when prompted accordingly
+	c.updateBalances()
+
+	// Run the main loop, that processes the screen events (i.e., input) and the
+	// controller events and renders or exits when prompted accordingly.
+	// As all changes to the data are made here, they do not need locking.
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		defer c.initializedScreen.Fini()
-		for controllerEvent := range c.controllerEvents {
-			switch controllerEvent {
-			case controllerEventRender:
-				start := time.Now()
+		for {
+			select {
+			case ev := <-screenEvents:
+				c.handleScreenEvent(ev)
+				go func() { c.controllerEvents <- controllerEventRender }()
+
+			case controllerEvent := <-c.controllerEvents:
+				switch controllerEvent {
+				case controllerEventRender:
+					start := time.Now()
+
+					// empty all further render events before rendering
+					exitEventEncounteredOnEmpty := emptyRenderEvents(c.controllerEvents)
+					// exit if an exit event was coming up
+					if exitEventEncounteredOnEmpty {
+						return
+					}
+					// render
+					c.rootPane.Draw()
 
-				// empty all further render events before rendering
-				exitEventEncounteredOnEmpty := emptyRenderEvents(c.controllerEvents)
-				// exit if an exit event was coming up
-				if exitEventEncounteredOnEmpty {
-					return
-				}
-				// render
-				c.rootPane.Draw()
+					end := time.Now()
+					c.data.RenderTimes.Add(uint64(end.Sub(start).Microseconds()))
 
-				end := time.Now()
-				c.data.RenderTimes.Add(uint64(end.Sub(start).Microseconds()))
+				case controllerEventTaskEditorExit:
+					if c.data.TaskEditor == nil {
+						log.Warn().Msgf("got task editor exit event, but no task editor active; likely logic error")
+					} else {
+						c.data.TaskEditor = nil
+						c.rootPane.PopSubpane()
+						log.Debug().Msgf("removed (presumed) task-editor subpane from root")
+						go func() { c.controllerEvents <- controllerEventRender }()
+					}
 
-			case controllerEventTaskEditorExit:
-				if c.data.TaskEditor == nil {
-					log.Warn().Msgf("got task editor exit event, but no task editor active; likely logic error")
-				} else {
-					c.data.TaskEditor = nil
-					c.rootPane.PopSubpane()
-					log.Debug().Msgf("removed (presumed) task-editor subpane from root")
+				case controllerEventEventEditorExit:
+					if c.data.EventEditor == nil {
+						log.Warn().Msgf("got event editor exit event, but no event editor active; likely logic error")
+					} else {
+						c.data.EventEditor = nil
+						// the event's times may have been edited
+						c.data.GetCurrentDay().UpdateEventOrder()
+						c.rootPane.PopSubpane()
+						log.Debug().Msgf("removed (presumed) event-editor subpane from root")
+						go func() { c.controllerEvents <- controllerEventRender }()
+					}
+
+				case controllerEventBalancesUpdate:
+					c.publishBalances()
 					go func() { c.controllerEvents <- controllerEventRender }()
-				}
 
-			case controllerEventEventEditorExit:
-				if c.data.EventEditor == nil {
-					log.Warn().Msgf("got event editor exit event, but no event editor active; likely logic error")
-				} else {
-					c.data.EventEditor = nil
-					c.rootPane.PopSubpane()
-					log.Debug().Msgf("removed (presumed) event-editor subpane from root")
+				case controllerEventTrackingUpdate:
+					c.updateTracking()
 					go func() { c.controllerEvents <- controllerEventRender }()
-				}
 
-			case controllerEventExit:
-				return
+				case controllerEventExit:
+					return
 
-			default:
-				log.Error().Interface("event", controllerEvent).Msgf("unhandled controller event")
+				default:
+					log.Error().Interface("event", controllerEvent).Msgf("unhandled controller event")
+				}
 			}
 		}
 	}()
 
-	// Run the time tracking loop, that updates at the start of every minute
+	// Run the time tracking loop, that prompts an update at the start of every
+	// minute
 	go func() {
 		for {
 			now := time.Now()
 			next := now.Round(1 * time.Minute).Add(1 * time.Minute)
 			time.Sleep(time.Until(next))
-			c.controllerEvents <- controllerEventRender
+			c.controllerEvents <- controllerEventTrackingUpdate
 		}
 	}()
 
-	// Run the event tracking loop, that waits for and processes events and pings
-	// for a redraw (or program exit) after each event.
+	// Run the screen event loop, that waits for events and passes them on to
+	// the main loop.
 	go func() {
 		for {
-			ev := c.screenEvents.PollEvent()
+			screenEvents <- c.screenEvents.PollEvent()
+		}
+	}()
 
-			start := time.Now()
+	wg.Wait()
+}
 
-			{
-				switch e := ev.(type) {
-				case *tcell.EventKey:
-					c.data.MouseMode = false
-					c.data.MouseEditState = edit.MouseEditStateNone
+// handleScreenEvent processes the given screen event, e.g. a key press.
+func (c *Controller) handleScreenEvent(ev tcell.Event) {
+	start := time.Now()
 
-					key := input.KeyFromTcellEvent(e)
-					inputApplied := c.rootPane.ProcessInput(key)
-					if !inputApplied {
-						log.Warn().Str("key", key.ToDebugString()).Msg("could not apply key input")
-					}
+	switch e := ev.(type) {
+	case *tcell.EventKey:
+		c.data.MouseMode = false
+		c.data.MouseEditState = edit.MouseEditStateNone
 
-				case *tcell.EventMouse:
-					c.data.MouseMode = true
+		c.data.Message = ""
 
-					// get new position
-					x, y := e.Position()
-					c.updateCursorPos(x, y)
+		key := input.KeyFromTcellEvent(e)
+		inputApplied := c.rootPane.ProcessInput(key)
+		if !inputApplied {
+			log.Warn().Str("key", key.ToDebugString()).Msg("could not apply key input")
+		}
 
-					switch c.data.MouseEditState {
-					case edit.MouseEditStateNone:
-						c.handleMouseNoneEditEvent(e)
-					case edit.MouseEditStateResizing:
-						c.handleMouseResizeEditEvent(ev)
-					case edit.MouseEditStateMoving:
-						c.handleMouseMoveEditEvent(ev)
-					}
+	case *tcell.EventMouse:
+		c.data.MouseMode = true
 
-				case *tcell.EventResize:
-					c.syncer.NeedsSync()
+		// get new position
+		x, y := e.Position()
+		c.updateCursorPos(x, y)
 
-				}
-			}
+		switch c.data.MouseEditState {
+		case edit.MouseEditStateNone:
+			c.handleMouseNoneEditEvent(e)
+		case edit.MouseEditStateResizing:
+			c.handleMouseResizeEditEvent(ev)
+		case edit.MouseEditStateMoving:
+			c.handleMouseMoveEditEvent(ev)
+		}
 
-			end := time.Now()
-			c.data.EventProcessingTimes.Add(uint64(end.Sub(start).Microseconds()))
+	case *tcell.EventResize:
+		c.syncer.NeedsSync()
 
-			c.controllerEvents <- controllerEventRender
-		}
-	}()
+	}
+
+	end := time.Now()
+	c.data.EventProcessingTimes.Add(uint64(end.Sub(start).Microseconds()))
 
-	wg.Wait()
 }
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/control"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/styling"
+	"github.com/ja-he/dayplan/internal/tui"
+)
+
+func TestCommandLineRunsAllActionsWithoutEvent(t *testing.T) {
+	newScreenHandler = func() *tui.ScreenHandler { return tui.NewTUIScreenHandlerFor(tcell.NewSimulationScreen("")) }
+	defer func() { newScreenHandler = tui.NewTUIScreenHandler }()
+
+	baseDirPath := t.TempDir()
+	err := os.MkdirAll(path.Join(baseDirPath, "days"), 0755)
+	if err != nil {
+		t.Fatalf("could not create days directory: %s", err.Error())
+	}
+	err = os.WriteFile(path.Join(baseDirPath, "days", "backlog.yml"), []byte{}, 0644)
+	if err != nil {
+		t.Fatalf("could not create backlog: %s", err.Error())
+	}
+
+	configData, err := config.ParseConfigAugmentDefaults(config.Dark, []byte{})
+	if err != nil {
+		t.Fatalf("could not parse default config: %s", err.Error())
+	}
+	categoryStyling, err := categoryStylingFromConfig(configData, true, nil)
+	if err != nil {
+		t.Fatalf("could not construct category styling: %s", err.Error())
+	}
+	stylesheet := styling.NewStylesheetFromConfig(configData.Stylesheet)
+
+	controller, err := NewController(
+		model.Date{Year: 2026, Month: 10, Day: 19},
+		control.EnvData{BaseDirPath: baseDirPath},
+		*categoryStyling,
+		*stylesheet,
+		nil,
+		model.NewAbsences(),
+		nil,
+		nil,
+		7,
+	)
+	if err != nil {
+		t.Fatalf("could not construct controller: %s", err.Error())
+	}
+	defer controller.initializedScreen.Fini()
+
+	// all commands and actions available on the command line, and some that are
+	// scoped to modes (and thus refused)
+	lines := append(controller.commands.Complete(""), "move.up", "move-pushing.to-now", "resize.lengthen", "move.exit")
+	for _, line := range lines {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("running ':%s' without a selected event panicked: %v", line, r)
+				}
+			}()
+			controller.data.GetCurrentDay().Current = nil
+			_ = controller.commands.Run(line)
+		}()
+	}
+}
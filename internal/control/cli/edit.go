@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/storage"
+)
+
+// EditCommand is the command `edit`, which modifies a single existing event.
+// The event is identified by its date plus the time it covers and/or a match
+// on its name; its subcommands define the modification.
+type EditCommand struct {
+	Date string `short:"d" long:"date" description:"the date of the event" value-name:"<yyyy-mm-dd>|today" default:"today"`
+	At   string `short:"a" long:"at" description:"select the event covering this time" value-name:"<HH:MM>"`
+	Name string `short:"n" long:"name" description:"select the event whose name matches this regular expression" value-name:"<regex>"`
+
+	MoveCommand         EditMoveCommand         `command:"move" description:"move the event to a new start time (keeping its duration)"`
+	ResizeCommand       EditResizeCommand       `command:"resize" description:"change the end time of the event"`
+	RenameCommand       EditRenameCommand       `command:"rename" description:"change the name of the event"`
+	RecategorizeCommand EditRecategorizeCommand `command:"recategorize" description:"change the category of the event"`
+	DeleteCommand       EditDeleteCommand       `command:"delete" description:"delete the event"`
+	SplitCommand        EditSplitCommand        `command:"split" description:"split the event in two at a given time"`
+}
+
+// EditMoveCommand is the command `edit move`.
+type EditMoveCommand struct {
+	To string `long:"to" description:"the new start time" value-name:"<HH:MM>" required:"true"`
+}
+
+// EditResizeCommand is the command `edit resize`.
+type EditResizeCommand struct {
+	To string `long:"to" description:"the new end time" value-name:"<HH:MM>" required:"true"`
+}
+
+// EditRenameCommand is the command `edit rename`.
+type EditRenameCommand struct {
+	To string `long:"to" description:"the new name" value-name:"<name>" required:"true"`
+}
+
+// EditRecategorizeCommand is the command `edit recategorize`.
+type EditRecategorizeCommand struct {
+	To string `long:"to" description:"the new category" value-name:"<category>" required:"true"`
+}
+
+// EditDeleteCommand is the command `edit delete`.
+type EditDeleteCommand struct{}
+
+// EditSplitCommand is the command `edit split`.
+type EditSplitCommand struct {
+	Time string `short:"T" long:"time" description:"the time at which to split the event (defaults to the time given with '--at')" value-name:"<HH:MM>"`
+}
+
+// Execute executes the move command.
+func (command *EditMoveCommand) Execute(args []string) error {
+	newStart, err := model.TimestampFromString(command.To)
+	if err != nil {
+		return fmt.Errorf("new start time invalid (%w)", err)
+	}
+	return editEvent(func(day *model.Day, event *model.Event) (string, error) {
+		err := day.MoveSingleEventTo(event, newStart)
+		return "moved", err
+	})
+}
+
+// Execute executes the resize command.
+func (command *EditResizeCommand) Execute(args []string) error {
+	newEnd, err := model.TimestampFromString(command.To)
+	if err != nil {
+		return fmt.Errorf("new end time invalid (%w)", err)
+	}
+	return editEvent(func(day *model.Day, event *model.Event) (string, error) {
+		err := day.ResizeTo(event, newEnd)
+		return "resized", err
+	})
+}
+
+// Execute executes the rename command.
+func (command *EditRenameCommand) Execute(args []string) error {
+	if strings.ContainsAny(command.To, "\n\r") {
+		return fmt.Errorf("name cannot contain line breaks")
+	}
+	return editEvent(func(day *model.Day, event *model.Event) (string, error) {
+		event.Name = command.To
+		return "renamed", nil
+	})
+}
+
+// Execute executes the recategorize command.
+func (command *EditRecategorizeCommand) Execute(args []string) error {
+	if strings.ContainsAny(command.To, "|\n\r") {
+		return fmt.Errorf("category name cannot contain '|' or line breaks")
+	}
+	return editEvent(func(day *model.Day, event *model.Event) (string, error) {
+		event.Cat = model.Category{Name: command.To}
+		return "recategorized", nil
+	})
+}
+
+// Execute executes the delete command.
+func (command *EditDeleteCommand) Execute(args []string) error {
+	return editEvent(func(day *model.Day, event *model.Event) (string, error) {
+		day.RemoveEvent(event)
+		return "deleted", nil
+	})
+}
+
+// Execute executes the split command.
+func (command *EditSplitCommand) Execute(args []string) error {
+	timeString := command.Time
+	if timeString == "" {
+		timeString = Opts.EditCommand.At
+	}
+	if timeString == "" {
+		return fmt.Errorf("a split time is required (via '--time' or '--at')")
+	}
+	splitTime, err := model.TimestampFromString(timeString)
+	if err != nil {
+		return fmt.Errorf("split time invalid (%w)", err)
+	}
+	return editEvent(func(day *model.Day, event *model.Event) (string, error) {
+		err := day.SplitEvent(event, splitTime)
+		return "split", err
+	})
+}
+
+// editEvent reads the day given to the edit command, selects the event
+// specified to the edit command, applies the given modification to it and
+// writes the day back, unless the modification fails.
+// The modification returns a verb describing it for the user.
+func editEvent(modify func(day *model.Day, event *model.Event) (string, error)) error {
+	date, err := parseDateOrToday(Opts.EditCommand.Date)
+	if err != nil {
+		return fmt.Errorf("date '%s' invalid (%w)", Opts.EditCommand.Date, err)
+	}
+
+	baseDirPath := getBaseDirPath()
+	configData, err := readConfig(baseDirPath, config.Light)
+	if err != nil {
+		return err
+	}
+	styledCategories, err := categoryStylingFromConfig(configData, false, nil)
+	if err != nil {
+		return err
+	}
+
+	dayFile := storage.NewFileHandler(baseDirPath + "/days/" + date.ToString())
+	day := dayFile.Read(getCategories(styledCategories))
+
+	event, err := selectEvent(day, Opts.EditCommand.At, Opts.EditCommand.Name)
+	if err != nil {
+		return err
+	}
+	before, beforeCategory := describeEvent(event), event.Cat.Name
+
+	verb, err := modify(day, event)
+	if err != nil {
+		return fmt.Errorf("could not modify event %s (%w)", before, err)
+	}
+
+	if _, err := styledCategories.GetStyle(event.Cat); err != nil && event.Cat.Name != beforeCategory {
+		fmt.Fprintf(os.Stderr, "WARNING: category '%s' not found in config data\n", event.Cat.Name)
+	}
+
+	dayFile.Write(day)
+	fmt.Printf("%s %s on %s\n", verb, before, date.ToString())
+	for _, e := range day.Events {
+		if e == event {
+			fmt.Printf("  now: %s\n", describeEvent(event))
+		}
+	}
+	return nil
+}
+
+// selectEvent selects the single event in the given day that covers the given
+// time (if not empty) and whose name matches the given regular expression (if
+// not empty).
+// It is an error if no or multiple events fit these criteria.
+func selectEvent(day *model.Day, atString, nameRegex string) (*model.Event, error) {
+	if atString == "" && nameRegex == "" {
+		return nil, fmt.Errorf("at least one of '--at' and '--name' is required to select an event")
+	}
+
+	matches := func(*model.Event) bool { return true }
+	if atString != "" {
+		at, err := model.TimestampFromString(atString)
+		if err != nil {
+			return nil, fmt.Errorf("time '%s' invalid (%w)", atString, err)
+		}
+		matches = func(e *model.Event) bool { return !e.Start.IsAfter(at) && e.End.IsAfter(at) }
+	}
+	if nameRegex != "" {
+		regex, err := regexp.Compile(nameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("name regex '%s' invalid (%w)", nameRegex, err)
+		}
+		matchesTime := matches
+		matches = func(e *model.Event) bool { return matchesTime(e) && regex.MatchString(e.Name) }
+	}
+
+	var candidates []*model.Event
+	for _, e := range day.Events {
+		if matches(e) {
+			candidates = append(candidates, e)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no matching event found")
+	case 1:
+		return candidates[0], nil
+	default:
+		descriptions := make([]string, len(candidates))
+		for i := range candidates {
+			descriptions[i] = "  " + describeEvent(candidates[i])
+		}
+		return nil, fmt.Errorf("multiple matching events found, please be more specific:\n%s", strings.Join(descriptions, "\n"))
+	}
+}
+
+// describeEvent returns a short description of the given event for the user.
+func describeEvent(e *model.Event) string {
+	return fmt.Sprintf("%s-%s '%s' (%s)", e.Start.ToString(), e.End.ToString(), e.Name, e.Cat.Name)
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/export"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// ExportCommand is the command `export`, which exports the events of a date
+// range for use in other programs.
+//
+// The account each event is booked on is determined by its category, per the
+// account mapping rules under `export` in the config file.
+type ExportCommand struct {
+	FromDay string `short:"f" long:"from" description:"the day from which to start exporting" value-name:"<yyyy-mm-dd>" required:"true"`
+	TilDay  string `short:"t" long:"til" description:"the day til which to export (inclusive)" value-name:"<yyyy-mm-dd>" required:"true"`
+
+	OutputFile string `short:"o" long:"output-file" description:"the file to write to (stdout if omitted)" value-name:"<file>"`
+
+	TimeclockCommand ExportTimeclockCommand `command:"timeclock" description:"export in the timeclock format (one clock-in and clock-out entry per event)"`
+	TimedotCommand   ExportTimedotCommand   `command:"timedot" description:"export in the (h)ledger timedot format (hours per account per day)"`
+}
+
+// ExportTimeclockCommand is the command `export timeclock`.
+type ExportTimeclockCommand struct{}
+
+// ExportTimedotCommand is the command `export timedot`.
+type ExportTimedotCommand struct{}
+
+// Execute executes the timeclock export.
+func (command *ExportTimeclockCommand) Execute(args []string) error {
+	return Opts.ExportCommand.run(export.WriteTimeclock)
+}
+
+// Execute executes the timedot export.
+func (command *ExportTimedotCommand) Execute(args []string) error {
+	return Opts.ExportCommand.run(export.WriteTimedot)
+}
+
+func (command *ExportCommand) run(write func(io.Writer, model.Date, *model.Day, *export.AccountMapper) error) error {
+	baseDirPath := getBaseDirPath()
+
+	configData, err := readConfig(baseDirPath, config.Light)
+	if err != nil {
+		return err
+	}
+	styledCategories, err := categoryStylingFromConfig(configData, false, nil)
+	if err != nil {
+		return err
+	}
+	accounts, err := export.NewAccountMapperFromConfig(configData.Export)
+	if err != nil {
+		return fmt.Errorf("invalid export config (%w)", err)
+	}
+
+	from, til, err := parseDateRange(command.FromDay, command.TilDay)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if command.OutputFile != "" {
+		file, err := os.OpenFile(command.OutputFile, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
+		if err != nil {
+			return fmt.Errorf("could not open output file '%s' (%w)", command.OutputFile, err)
+		}
+		defer file.Close()
+		out = file
+	}
+	writer := bufio.NewWriter(out)
+
+	for _, entry := range readDays(baseDirPath, getCategories(styledCategories), from, til) {
+		err := write(writer, entry.Date, entry.Day, accounts)
+		if err != nil {
+			return fmt.Errorf("could not write export for %s (%w)", entry.Date.ToString(), err)
+		}
+	}
+
+	return writer.Flush()
+}
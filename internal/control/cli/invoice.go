@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/invoice"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/storage"
+)
+
+// InvoiceCommand is the command `invoice`, which computes an invoice to a
+// client from the time spent on the categories billed to them (per the
+// `billing` of categories in the config file).
+//
+// Issued invoices are numbered sequentially and recorded in the invoice ledger
+// ('${DAYPLAN_HOME}/invoices'); a period already (partially) billed to a
+// client is refused unless forced.
+type InvoiceCommand struct {
+	Client  string `short:"c" long:"client" description:"the client to invoice" value-name:"<client>" required:"true"`
+	FromDay string `short:"f" long:"from" description:"the first day to bill" value-name:"<yyyy-mm-dd>" required:"true"`
+	TilDay  string `short:"t" long:"til" description:"the last day to bill (inclusive)" value-name:"<yyyy-mm-dd>" required:"true"`
+
+	GroupBy  string `short:"g" long:"group-by" choice:"day" choice:"name" description:"whether line items are per day (and category) or per event name (and category)" default:"day"`
+	Output   string `short:"o" long:"output" choice:"markdown" choice:"html" choice:"json" description:"the output format" default:"markdown"`
+	Template string `long:"template" description:"a Go text/template file to render with instead of the output format" value-name:"<file>"`
+
+	OutputFile string `long:"output-file" description:"the file to write to (stdout if omitted)" value-name:"<file>"`
+
+	Draft bool `long:"draft" description:"don't number the invoice or record it in the ledger"`
+	Force bool `long:"force" description:"issue the invoice even if the period was already billed to the client"`
+}
+
+// Execute executes the invoice command.
+func (command *InvoiceCommand) Execute(args []string) error {
+	baseDirPath := getBaseDirPath()
+
+	configData, err := readConfig(baseDirPath, config.Light)
+	if err != nil {
+		return err
+	}
+	styledCategories, err := categoryStylingFromConfig(configData, false, nil)
+	if err != nil {
+		return err
+	}
+	rates, err := invoice.RatesFromConfig(configData.Categories)
+	if err != nil {
+		return fmt.Errorf("invalid billing config (%w)", err)
+	}
+	numberFormat, err := invoice.NumberFormatFromConfig(configData.Invoice)
+	if err != nil {
+		return fmt.Errorf("invalid invoice config (%w)", err)
+	}
+	if len(rates) == 0 {
+		return fmt.Errorf("no categories are billable (configure 'billing' for categories in config.yaml)")
+	}
+
+	from, til, err := parseDateRange(command.FromDay, command.TilDay)
+	if err != nil {
+		return err
+	}
+	days := make([]*model.Day, 0)
+	for _, entry := range readDays(baseDirPath, getCategories(styledCategories), from, til) {
+		days = append(days, entry.Day)
+	}
+
+	inv, err := invoice.Compute(command.Client, from, days, rates, invoice.GroupBy(command.GroupBy), configData.Invoice.Tax)
+	if err != nil {
+		return fmt.Errorf("%w (known clients: %s)", err, strings.Join(invoice.Clients(rates), ", "))
+	}
+	if len(inv.Items) == 0 {
+		return fmt.Errorf("nothing to bill to '%s' from %s til %s", command.Client, from.ToString(), til.ToString())
+	}
+
+	ledgerPath := baseDirPath + "/" + storage.InvoiceLedgerFileName
+	ledger, err := storage.ReadInvoiceLedger(ledgerPath)
+	if err != nil {
+		return fmt.Errorf("can't read invoice ledger (%w)", err)
+	}
+	if !command.Draft {
+		overlapping := ledger.Overlapping(command.Client, from, til)
+		if len(overlapping) > 0 && !command.Force {
+			numbers := make([]string, 0, len(overlapping))
+			for _, entry := range overlapping {
+				numbers = append(numbers, fmt.Sprintf("%s (%s til %s)", entry.Number, entry.From.ToString(), entry.Til.ToString()))
+			}
+			return fmt.Errorf("period already billed to '%s' by %s (use --force to bill anyway)", command.Client, strings.Join(numbers, ", "))
+		}
+		ledger.Issue(inv, numberFormat, model.FromTime(time.Now()).Date)
+	}
+
+	// render before recording the invoice, so that failing to render doesn't
+	// use up a number
+	var rendered bytes.Buffer
+	switch {
+	case command.Template != "":
+		templateText, err := os.ReadFile(command.Template)
+		if err != nil {
+			return fmt.Errorf("can't read template (%w)", err)
+		}
+		err = invoice.WriteTemplate(&rendered, string(templateText), inv)
+		if err != nil {
+			return err
+		}
+	case command.Output == "html":
+		err = invoice.WriteHTML(&rendered, inv)
+	case command.Output == "json":
+		err = invoice.WriteJSON(&rendered, inv)
+	default:
+		err = invoice.WriteMarkdown(&rendered, inv)
+	}
+	if err != nil {
+		return fmt.Errorf("error rendering invoice (%w)", err)
+	}
+
+	if command.OutputFile != "" {
+		err = os.WriteFile(command.OutputFile, rendered.Bytes(), 0644)
+	} else {
+		_, err = os.Stdout.Write(rendered.Bytes())
+	}
+	if err != nil {
+		return fmt.Errorf("could not write invoice (%w)", err)
+	}
+
+	if !command.Draft {
+		err = storage.WriteInvoiceLedger(ledgerPath, ledger)
+		if err != nil {
+			return fmt.Errorf("can't write invoice ledger (%w)", err)
+		}
+		fmt.Fprintf(os.Stderr, "issued invoice %s to '%s' (total %.2f %s)\n", inv.Number, inv.Client, inv.Total, inv.Currency)
+	}
+
+	return nil
+}
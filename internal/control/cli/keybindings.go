@@ -0,0 +1,383 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ja-he/dayplan/internal/control/action"
+	"github.com/ja-he/dayplan/internal/input"
+)
+
+// keybindings are the keymaps of the TUI's panes, by pane name and mode name.
+// Panes without modes have only the mode "normal".
+type keybindings map[string]map[string]input.Keymap
+
+// defaultKeybindings returns the default keybindings.
+func defaultKeybindings() keybindings {
+	timeline := input.Keymap{
+		"<c-u>": "timeline.scroll-up",
+		"<c-d>": "timeline.scroll-down",
+		"gg":    "timeline.scroll-top",
+		"G":     "timeline.scroll-bottom",
+		"+":     "timeline.zoom-in",
+		"-":     "timeline.zoom-out",
+	}
+	day := input.Keymap{
+		"w": "day.write",
+		"h": "day.previous",
+		"l": "day.next",
+		"c": "day.clear",
+		"A": "day.cycle-absence",
+	}
+
+	// the events of a day, in day view and (per day) in week and month view
+	events := func() map[string]input.Keymap {
+		return map[string]input.Keymap{
+			"normal": day.WithOverrides(input.Keymap{
+				"j":    "events.next",
+				"k":    "events.previous",
+				"d":    "events.delete",
+				"<cr>": "events.edit",
+				"o":    "events.add-after",
+				"O":    "events.add-before",
+				"N":    "events.add-now",
+				"sn":   "events.split-now",
+				"sc":   "events.split-center",
+				"m":    "events.move-mode",
+				"M":    "events.move-pushing-mode",
+				"r":    "events.resize-mode",
+			}),
+			"move": {
+				"n":     "move.to-now",
+				"j":     "move.down",
+				"k":     "move.up",
+				"h":     "move.to-previous-day",
+				"l":     "move.to-next-day",
+				"m":     "move.exit",
+				"<esc>": "move.exit",
+			},
+			"move-pushing": {
+				"n":     "move-pushing.to-now",
+				"j":     "move-pushing.down",
+				"k":     "move-pushing.up",
+				"M":     "move-pushing.exit",
+				"<esc>": "move-pushing.exit",
+			},
+			"resize": {
+				"n":     "resize.to-now",
+				"j":     "resize.lengthen",
+				"k":     "resize.shorten",
+				"r":     "resize.exit",
+				"<esc>": "resize.exit",
+			},
+		}
+	}
+
+	return keybindings{
+		"root": {"normal": {
+			"q":     "quit",
+			"P":     "toggle-debug",
+			"S":     "summary.open",
+			"E":     "toggle-log",
+			"?":     "help.open",
+			"<esc>": "view.up",
+			"i":     "view.down",
+			":":     "command-line.open",
+			"D":     "date.prompt",
+			".":     "date.today",
+			"[w":    "date.previous-week",
+			"]w":    "date.next-week",
+			"[m":    "date.previous-month",
+			"]m":    "date.next-month",
+			"<c-o>": "jumplist.back",
+			"<c-i>": "jumplist.forward",
+			"a":     "view.agenda",
+		}},
+		"day-view": {"normal": {
+			"W":      "weather.update",
+			"t":      "tools.toggle",
+			"T":      "tasks.toggle",
+			"<c-w>h": "focus.previous",
+			"<c-w>l": "focus.next",
+		}},
+		"timeline":        {"normal": timeline},
+		"events":          events(),
+		"multiday-events": events(),
+		"multiday": {"normal": timeline.WithOverrides(input.Keymap{
+			"h": "day.previous",
+			"l": "day.next",
+		})},
+		"week-view": {"normal": {}},
+		"month-view": {"normal": timeline.WithOverrides(input.Keymap{
+			"v": "month-view.toggle-grid",
+		})},
+		"month-grid": {"normal": {
+			"h":    "day.previous",
+			"l":    "day.next",
+			"k":    "month-grid.up",
+			"j":    "month-grid.down",
+			"<cr>": "month-grid.open-day",
+			"w":    "day.write",
+		}},
+		"year-view": {"normal": {}},
+		"year": {"normal": {
+			"h":    "year.previous-week",
+			"l":    "year.next-week",
+			"k":    "day.previous",
+			"j":    "day.next",
+			"m":    "year.cycle-mode",
+			"<cr>": "year.open-day",
+		}},
+		"agenda-view": {"normal": {}},
+		"agenda": {"normal": {
+			"j":    "agenda.next",
+			"k":    "agenda.previous",
+			"}":    "agenda.next-day",
+			"{":    "agenda.previous-day",
+			"gg":   "agenda.first",
+			"G":    "agenda.last",
+			"<cr>": "agenda.open",
+		}},
+		"tasks": {"normal": {
+			"<c-u>": "tasks.scroll-up",
+			"<c-d>": "tasks.scroll-down",
+			"j":     "tasks.next",
+			"k":     "tasks.previous",
+			"gg":    "tasks.first",
+			"G":     "tasks.last",
+			"sn":    "tasks.schedule-now",
+			"d":     "tasks.delete",
+			"l":     "tasks.step-into",
+			"h":     "tasks.step-out",
+			"O":     "tasks.add-above",
+			"o":     "tasks.add-below",
+			"i":     "tasks.add-subtask",
+			"<cr>":  "tasks.edit",
+			"w":     "tasks.write",
+		}},
+		"tools": {"normal": {
+			"j": "tools.next-category",
+			"k": "tools.previous-category",
+		}},
+		"summary": {"normal": {
+			"S": "summary.close",
+			"h": "summary.previous",
+			"l": "summary.next",
+		}},
+		"help": {"normal": {
+			"?": "help.close",
+		}},
+		// (any other input is entered on the command line)
+		"command-line": {"insert": {
+			"<cr>":    "command-line.execute",
+			"<esc>":   "command-line.cancel",
+			"<c-c>":   "command-line.cancel",
+			"<tab>":   "command-line.complete",
+			"<s-tab>": "command-line.complete-previous",
+			"<up>":    "command-line.history-previous",
+			"<c-p>":   "command-line.history-previous",
+			"<down>":  "command-line.history-next",
+			"<c-n>":   "command-line.history-next",
+			"<left>":  "command-line.left",
+			"<right>": "command-line.right",
+			"<home>":  "command-line.start",
+			"<c-a>":   "command-line.start",
+			"<end>":   "command-line.end",
+			"<c-e>":   "command-line.end",
+			"<bs>":    "command-line.backspace",
+			"<c-bs>":  "command-line.backspace",
+			"<del>":   "command-line.delete",
+			"<c-w>":   "command-line.delete-word",
+			"<c-u>":   "command-line.clear",
+		}},
+
+		// the editors have their own actions (see editors.Composite and
+		// editors.StringEditor)
+		"editor": {"normal": {
+			"j":       "next-field",
+			"k":       "prev-field",
+			"i":       "enter-subeditor",
+			":w<CR>":  "write",
+			"<CR>":    "write-and-quit",
+			":wq<CR>": "write-and-quit",
+			":q!<CR>": "quit",
+			"<ESC>":   "quit",
+		}},
+		"string-editor": {
+			"normal": {
+				"h":       "move-cursor-rune-left",
+				"l":       "move-cursor-rune-right",
+				"<left>":  "move-cursor-rune-left",
+				"<right>": "move-cursor-rune-right",
+				"0":       "move-cursor-to-beginning",
+				"$":       "move-cursor-to-end",
+				"w":       "move-cursor-to-next-word-beginning",
+				"b":       "move-cursor-to-prev-word-beginning",
+				"e":       "move-cursor-to-next-word-end",
+				"<ESC>":   "quit",
+				"D":       "delete-to-end",
+				"d$":      "delete-to-end",
+				"d0":      "backspace-to-beginning",
+				"C":       "delete-to-end-and-insert",
+				"c$":      "delete-to-end-and-insert",
+				"c0":      "backspace-to-beginning-and-insert",
+				"S":       "delete-everything-and-insert",
+				"x":       "delete-rune",
+				"s":       "delete-rune-and-insert",
+				"i":       "swap-mode-insert",
+				"a":       "swap-mode-insert-append",
+			},
+			"insert": {
+				"<left>":  "move-cursor-rune-left",
+				"<right>": "move-cursor-rune-right",
+				"<ESC>":   "swap-mode-normal",
+				"<c-bs>":  "backspace",
+				"<bs>":    "backspace",
+				"<c-u>":   "backspace-to-beginning",
+			},
+		},
+		// (in addition to the string editor's normal mode)
+		"value-editor": {"normal": {
+			"<c-a>": "increment",
+			"<c-x>": "decrement",
+			"+":     "increment",
+			"-":     "decrement",
+			"X":     "clear",
+		}},
+		"choice-editor": {
+			"normal": {
+				"j":      "select-next",
+				"k":      "select-previous",
+				"<down>": "select-next",
+				"<up>":   "select-previous",
+			},
+			"insert": {
+				"<c-n>":  "select-next",
+				"<c-p>":  "select-previous",
+				"<down>": "select-next",
+				"<up>":   "select-previous",
+			},
+		},
+	}
+}
+
+// newKeybindings returns the default keybindings with the given overrides
+// (as in the config, see config.Config.Keybindings) applied.
+// Overrides for unknown panes or modes are errors.
+func newKeybindings(overrides map[string]map[string]map[string]string) (keybindings, error) {
+	result := defaultKeybindings()
+	for paneName, modes := range overrides {
+		paneKeymaps, ok := result[paneName]
+		if !ok {
+			return nil, fmt.Errorf("unknown pane '%s' (known: %s)", paneName, strings.Join(sortedNames(result), ", "))
+		}
+		for modeName, keymap := range modes {
+			defaults, ok := paneKeymaps[modeName]
+			if !ok {
+				return nil, fmt.Errorf("unknown mode '%s' for pane '%s' (known: %s)", modeName, paneName, strings.Join(sortedNames(paneKeymaps), ", "))
+			}
+			modeOverrides := make(input.Keymap, len(keymap))
+			for keyspec, actionspec := range keymap {
+				modeOverrides[input.Keyspec(keyspec)] = input.Actionspec(actionspec)
+			}
+			paneKeymaps[modeName] = defaults.WithOverrides(modeOverrides)
+		}
+	}
+	return result, nil
+}
+
+// editorInputConfig returns the input config for editors.
+func (k keybindings) editorInputConfig() input.InputConfig {
+	return input.InputConfig{
+		Editor: k["editor"]["normal"],
+		StringEditor: input.ModedSpec{
+			Normal: k["string-editor"]["normal"],
+			Insert: k["string-editor"]["insert"],
+		},
+		ValueEditor: k["value-editor"]["normal"],
+		ChoiceEditor: input.ModedSpec{
+			Normal: k["choice-editor"]["normal"],
+			Insert: k["choice-editor"]["insert"],
+		},
+	}
+}
+
+// tree constructs the input tree for the given pane and mode, with the
+// actions taken from the given registry (see actionRegistry.lazy).
+func (k keybindings) tree(paneName, modeName string, actions actionRegistry) (*input.Tree, error) {
+	keymap, ok := k[paneName][modeName]
+	if !ok {
+		return nil, fmt.Errorf("no keybindings for pane '%s' in mode '%s'", paneName, modeName)
+	}
+	tree, err := input.ConstructInputTreeFromKeymap(keymap, actions.lazy(keymap))
+	if err != nil {
+		return nil, fmt.Errorf("invalid keybindings for pane '%s' in mode '%s' (%w)", paneName, modeName, err)
+	}
+	return tree, nil
+}
+
+// mappings returns the mappings of input sequences to actions for the given
+// pane and mode, with the actions taken from the given registry (see
+// actionRegistry.lazy).
+func (k keybindings) mappings(paneName, modeName string, actions actionRegistry) (map[input.Keyspec]action.Action, error) {
+	keymap, ok := k[paneName][modeName]
+	if !ok {
+		return nil, fmt.Errorf("no keybindings for pane '%s' in mode '%s'", paneName, modeName)
+	}
+	return input.ResolveKeymap(keymap, actions.lazy(keymap))
+}
+
+// validate returns an error if any keybinding (other than for the editors,
+// which have their own actions, see editors.ValidateInputConfig) is to an
+// action not in the given registry.
+func (k keybindings) validate(actions actionRegistry) error {
+	for _, paneName := range sortedNames(k) {
+		if paneName == "editor" || paneName == "string-editor" || paneName == "value-editor" || paneName == "choice-editor" {
+			continue
+		}
+		for _, modeName := range sortedNames(k[paneName]) {
+			_, err := input.ResolveKeymap(k[paneName][modeName], actions)
+			if err != nil {
+				return fmt.Errorf("invalid keybindings for pane '%s' in mode '%s' (%w)", paneName, modeName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// actionRegistry holds the named actions of the TUI, by action ID, which
+// keybindings refer to.
+type actionRegistry map[input.Actionspec]action.Action
+
+// register adds the given actions to the registry.
+func (r actionRegistry) register(actions map[input.Actionspec]action.Action) {
+	for actionspec, a := range actions {
+		r[actionspec] = a
+	}
+}
+
+// lazy returns, for each action ID in the given keymap, an action that looks
+// up the registered action only when done or explained.
+// This allows constructing input trees before all actions they refer to are
+// registered; see keybindings.validate to ensure that all eventually are.
+func (r actionRegistry) lazy(keymap input.Keymap) map[input.Actionspec]action.Action {
+	result := make(map[input.Actionspec]action.Action, len(keymap))
+	for _, actionspec := range keymap {
+		actionspecCopy := actionspec
+		result[actionspec] = action.NewSimple(
+			func() string { return r[actionspecCopy].Explain() },
+			func() { r[actionspecCopy].Do() },
+		)
+	}
+	return result
+}
+
+func sortedNames[T any](m map[string]T) []string {
+	result := make([]string, 0, len(m))
+	for name := range m {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/render"
+)
+
+// RenderCommand is the command `render`, which renders days to formats outside
+// of the terminal.
+type RenderCommand struct {
+	SVGCommand RenderSVGCommand `command:"svg" description:"render a day or week to an SVG image"`
+}
+
+// RenderSVGCommand is the command `render svg`.
+type RenderSVGCommand struct {
+	Day  string `short:"d" long:"day" description:"the day to render" value-name:"<yyyy-mm-dd>"`
+	Week string `short:"w" long:"week" description:"render the week (Monday through Sunday) containing this day" value-name:"<yyyy-mm-dd>"`
+
+	OutputFile string `short:"o" long:"output-file" description:"the file to write to (stdout if omitted)" value-name:"<file>"`
+
+	FromHour   int     `long:"from-hour" description:"the first hour of the day to render" value-name:"<0-23>" default:"0"`
+	TilHour    int     `long:"til-hour" description:"the hour of the day to render until" value-name:"<1-24>" default:"24"`
+	HourHeight float64 `long:"hour-height" description:"the height of an hour in pixels" value-name:"<px>" default:"40"`
+	DayWidth   float64 `long:"day-width" description:"the width of a day in pixels" value-name:"<px>" default:"180"`
+
+	Sun bool `long:"sun" description:"shade the time before sunrise and after sunset (requires LATITUDE and LONGITUDE to be set)"`
+}
+
+// Execute executes the SVG rendering command.
+func (command *RenderSVGCommand) Execute(args []string) error {
+	var dates []model.Date
+	switch {
+	case command.Day != "" && command.Week != "":
+		return fmt.Errorf("only one of '--day' and '--week' may be specified")
+	case command.Day != "":
+		date, err := model.FromString(command.Day)
+		if err != nil {
+			return fmt.Errorf("day '%s' invalid (%w)", command.Day, err)
+		}
+		dates = []model.Date{date}
+	case command.Week != "":
+		date, err := model.FromString(command.Week)
+		if err != nil {
+			return fmt.Errorf("week '%s' invalid (%w)", command.Week, err)
+		}
+		monday, sunday := date.WeekBounds()
+		for current := monday; current != sunday.Next(); current = current.Next() {
+			dates = append(dates, current)
+		}
+	default:
+		return fmt.Errorf("one of '--day' and '--week' is required")
+	}
+
+	baseDirPath := getBaseDirPath()
+	configData, err := readConfig(baseDirPath, config.Light)
+	if err != nil {
+		return err
+	}
+	styledCategories, err := categoryStylingFromConfig(configData, false, nil)
+	if err != nil {
+		return err
+	}
+	colors := categoryColors(configData, styledCategories)
+
+	var latitude, longitude float64
+	if command.Sun {
+		var errLat, errLon error
+		latitude, errLat = strconv.ParseFloat(os.Getenv("LATITUDE"), 64)
+		longitude, errLon = strconv.ParseFloat(os.Getenv("LONGITUDE"), 64)
+		if errLat != nil || errLon != nil {
+			return fmt.Errorf("'--sun' requires valid LATITUDE and LONGITUDE environment variables")
+		}
+	}
+
+	days := make([]render.Day, 0, len(dates))
+	for _, entry := range readDays(baseDirPath, getCategories(styledCategories), dates[0], dates[len(dates)-1]) {
+		day := render.Day{Date: entry.Date, Day: entry.Day}
+		if command.Sun {
+			sunTimes := entry.Date.GetSunTimes(latitude, longitude)
+			day.SunTimes = &sunTimes
+		}
+		days = append(days, day)
+	}
+
+	opts := render.DefaultSVGOptions()
+	opts.FromHour = command.FromHour
+	opts.TilHour = command.TilHour
+	opts.HourHeight = command.HourHeight
+	opts.DayWidth = command.DayWidth
+
+	var out io.Writer = os.Stdout
+	if command.OutputFile != "" {
+		file, err := os.OpenFile(command.OutputFile, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
+		if err != nil {
+			return fmt.Errorf("could not open output file '%s' (%w)", command.OutputFile, err)
+		}
+		defer file.Close()
+		out = file
+	}
+	return render.WriteSVG(out, days, colors, opts)
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/report"
+)
+
+// ReportCommand is the command `report`, which produces a report on a range of
+// days.
+type ReportCommand struct {
+	FromDay string `short:"f" long:"from" description:"the day from which to start the report" value-name:"<yyyy-mm-dd>" required:"true"`
+	TilDay  string `short:"t" long:"til" description:"the day til which to report (inclusive)" value-name:"<yyyy-mm-dd>" required:"true"`
+
+	OutputFile string `short:"o" long:"output-file" description:"the file to write to (stdout if omitted)" value-name:"<file>"`
+
+	TimesheetIncludeFilter string `long:"timesheet-include-filter" short:"i" description:"the category filter include regex for the timesheet (empty value is ignored)" value-name:"<regex>"`
+	TimesheetExcludeFilter string `long:"timesheet-exclude-filter" short:"e" description:"the category filter exclude regex for the timesheet (empty value is ignored)" value-name:"<regex>"`
+
+	HTMLCommand ReportHTMLCommand `command:"html" description:"produce a self-contained HTML report (totals, goals, charts, timesheet)"`
+}
+
+// ReportHTMLCommand is the command `report html`.
+type ReportHTMLCommand struct{}
+
+// Execute executes the HTML report command.
+func (command *ReportHTMLCommand) Execute(args []string) error {
+	return Opts.ReportCommand.run(func(w io.Writer, r report.Report, colors map[string]string) error {
+		return report.WriteHTML(w, r, colors)
+	})
+}
+
+func (command *ReportCommand) run(write func(io.Writer, report.Report, map[string]string) error) error {
+	baseDirPath := getBaseDirPath()
+
+	configData, err := readConfig(baseDirPath, config.Light)
+	if err != nil {
+		return err
+	}
+	absences, err := readAbsences(baseDirPath)
+	if err != nil {
+		return err
+	}
+	styledCategories, err := categoryStylingFromConfig(configData, false, absences)
+	if err != nil {
+		return err
+	}
+	colors := make(map[string]string)
+	for _, category := range configData.Categories {
+		colors[category.Name] = category.Color
+	}
+
+	from, til, err := parseDateRange(command.FromDay, command.TilDay)
+	if err != nil {
+		return err
+	}
+
+	matcher, err := newCategoryMatcher(command.TimesheetIncludeFilter, command.TimesheetExcludeFilter)
+	if err != nil {
+		return err
+	}
+
+	categories := getCategories(styledCategories)
+	days := make([]*model.Day, 0)
+	for _, entry := range readDays(baseDirPath, categories, from, til) {
+		days = append(days, entry.Day)
+	}
+	r := report.Build(from, til, days, categories, matcher)
+
+	var out io.Writer = os.Stdout
+	if command.OutputFile != "" {
+		file, err := os.OpenFile(command.OutputFile, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
+		if err != nil {
+			return fmt.Errorf("could not open output file '%s' (%w)", command.OutputFile, err)
+		}
+		defer file.Close()
+		out = file
+	}
+	writer := bufio.NewWriter(out)
+	err = write(writer, r, colors)
+	if err != nil {
+		return fmt.Errorf("could not write report (%w)", err)
+	}
+	return writer.Flush()
+}
+
+// newCategoryMatcher returns a matcher for category names by the given include
+// and exclude regexes, either of which may be empty to be ignored.
+func newCategoryMatcher(include, exclude string) (func(string) bool, error) {
+	var includeRegex, excludeRegex *regexp.Regexp
+	var err error
+	if include != "" {
+		includeRegex, err = regexp.Compile(include)
+		if err != nil {
+			return nil, fmt.Errorf("category include filter regex is invalid (%s)", err.Error())
+		}
+	}
+	if exclude != "" {
+		excludeRegex, err = regexp.Compile(exclude)
+		if err != nil {
+			return nil, fmt.Errorf("category exclude filter regex is invalid (%s)", err.Error())
+		}
+	}
+	return func(catName string) bool {
+		if includeRegex != nil && !includeRegex.MatchString(catName) {
+			return false
+		}
+		if excludeRegex != nil && excludeRegex.MatchString(catName) {
+			return false
+		}
+		return true
+	}, nil
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/render"
+)
+
+// ShowCommand is the command `show`, which prints a plain-text agenda (or a
+// grid similar to the TUI's week view) of a day, week or month.
+type ShowCommand struct {
+	Date  string `short:"d" long:"date" description:"show this day" value-name:"<yyyy-mm-dd>|today" optional:"yes" optional-value:"today"`
+	Week  string `short:"w" long:"week" description:"show the week (Monday through Sunday) containing this day" value-name:"<yyyy-mm-dd>|today" optional:"yes" optional-value:"today"`
+	Month string `short:"m" long:"month" description:"show the month containing this day" value-name:"<yyyy-mm-dd>|today" optional:"yes" optional-value:"today"`
+
+	Grid        bool   `short:"g" long:"grid" description:"show a grid with a time axis instead of an agenda (not for months)"`
+	FromHour    int    `long:"from-hour" description:"the first hour of the day to show in the grid" value-name:"<0-23>" default:"6"`
+	TilHour     int    `long:"til-hour" description:"the hour of the day to show the grid until" value-name:"<1-24>" default:"22"`
+	RowsPerHour int    `long:"rows-per-hour" description:"the number of lines an hour takes up in the grid" value-name:"<n>" default:"2"`
+	ColumnWidth int    `long:"column-width" description:"the width of a day in the grid (default: 18, or 40 for a single day)" value-name:"<n>"`
+	Color       string `long:"color" choice:"auto" choice:"always" choice:"never" description:"whether to color output by category (auto: only if writing to a terminal and NO_COLOR is not set)" default:"auto"`
+	Theme       string `short:"t" long:"theme" choice:"light" choice:"dark" description:"the terminal's theme, for coloring" default:"dark"`
+}
+
+// Execute executes the show command.
+func (command *ShowCommand) Execute(args []string) error {
+	var from, til model.Date
+	specified := 0
+	for _, s := range []string{command.Date, command.Week, command.Month} {
+		if s != "" {
+			specified++
+		}
+	}
+	switch {
+	case specified > 1:
+		return fmt.Errorf("only one of '--date', '--week' and '--month' may be specified")
+	case command.Week != "":
+		date, err := parseDateOrToday(command.Week)
+		if err != nil {
+			return fmt.Errorf("week '%s' invalid (%w)", command.Week, err)
+		}
+		from, til = date.WeekBounds()
+	case command.Month != "":
+		if command.Grid {
+			return fmt.Errorf("'--grid' is not supported for months")
+		}
+		date, err := parseDateOrToday(command.Month)
+		if err != nil {
+			return fmt.Errorf("month '%s' invalid (%w)", command.Month, err)
+		}
+		from, til = date.MonthBounds()
+	default:
+		dateString := command.Date
+		if dateString == "" {
+			dateString = "today"
+		}
+		date, err := parseDateOrToday(dateString)
+		if err != nil {
+			return fmt.Errorf("date '%s' invalid (%w)", command.Date, err)
+		}
+		from, til = date, date
+	}
+
+	theme, darkBG := config.Dark, true
+	if command.Theme == "light" {
+		theme, darkBG = config.Light, false
+	}
+	baseDirPath := getBaseDirPath()
+	configData, err := readConfig(baseDirPath, theme)
+	if err != nil {
+		return err
+	}
+	styledCategories, err := categoryStylingFromConfig(configData, darkBG, nil)
+	if err != nil {
+		return err
+	}
+
+	var colors func(model.Category) (fg, bg string)
+	if command.Color == "always" || (command.Color == "auto" && isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == "") {
+		colors = categoryColors(configData, styledCategories)
+	}
+
+	days := make([]render.Day, 0)
+	for _, entry := range readDays(baseDirPath, getCategories(styledCategories), from, til) {
+		days = append(days, render.Day{Date: entry.Date, Day: entry.Day})
+	}
+
+	if !command.Grid {
+		return render.WriteAgenda(os.Stdout, days, colors)
+	}
+
+	opts := render.DefaultTextOptions()
+	opts.FromHour = command.FromHour
+	opts.TilHour = command.TilHour
+	opts.RowsPerHour = command.RowsPerHour
+	switch {
+	case command.ColumnWidth != 0:
+		opts.ColumnWidth = command.ColumnWidth
+	case len(days) == 1:
+		opts.ColumnWidth = 40
+	}
+	return render.WriteGrid(os.Stdout, days, colors, opts)
+}
+
+// isTerminal returns whether the given file is a terminal (character device),
+// as opposed to, e.g., a pipe or a regular file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
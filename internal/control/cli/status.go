@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/status"
+	"github.com/ja-he/dayplan/internal/storage"
+	"github.com/ja-he/dayplan/internal/tracking"
+)
+
+const defaultStatusFormat = `{{with .Current}}current: {{.Name}} (until {{.End}}){{else}}current: -{{end}}{{with .Next}}, next: {{.Name}} in {{$.UntilNext}}{{end}}`
+
+// StatusCommand is the command `status`, which prints a single-line status of
+// the current day, e.g., for shell prompts or status bars.
+// It only reads the config and the current day, so that it is cheap enough to
+// be run every few seconds.
+type StatusCommand struct {
+	Format string `short:"f" long:"format" description:"a Go text/template for the output (see README for the available fields)" value-name:"<template>"`
+	JSON   bool   `long:"json" description:"output JSON instead of formatted text"`
+}
+
+// Execute executes the status command.
+func (command *StatusCommand) Execute(args []string) error {
+	if command.JSON && command.Format != "" {
+		return fmt.Errorf("only one of '--format' and '--json' may be specified")
+	}
+	format := command.Format
+	if format == "" {
+		format = defaultStatusFormat
+	}
+	tmpl, err := template.New("status").Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid format (%w)", err)
+	}
+
+	baseDirPath := getBaseDirPath()
+	categories, err := readCategories(baseDirPath)
+	if err != nil {
+		return err
+	}
+
+	now := model.FromTime(time.Now())
+	day := storage.NewFileHandler(baseDirPath + "/days/" + now.Date.ToString()).Read(categories)
+
+	running, err := tracking.Read(baseDirPath + "/" + tracking.FileName)
+	if err != nil {
+		return err
+	}
+	result := status.BuildTracking(now.Date, now.Timestamp, day, categories, running)
+
+	if command.JSON {
+		return json.NewEncoder(os.Stdout).Encode(result)
+	}
+	err = tmpl.Execute(os.Stdout, result)
+	if err != nil {
+		return fmt.Errorf("could not format status (%w)", err)
+	}
+	fmt.Println()
+	return nil
+}
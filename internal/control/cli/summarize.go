@@ -2,17 +2,13 @@ package cli
 
 import (
 	"fmt"
-	"log"
+	"io"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/ja-he/dayplan/internal/config"
-	"github.com/ja-he/dayplan/internal/control"
 	"github.com/ja-he/dayplan/internal/model"
-	"github.com/ja-he/dayplan/internal/storage"
-	"github.com/ja-he/dayplan/internal/styling"
-	"github.com/ja-he/dayplan/internal/util"
+	"github.com/ja-he/dayplan/internal/summary"
 )
 
 // Flags for the `summarize` command line command, for `go-flags` to parse
@@ -21,9 +17,12 @@ type SummarizeCommand struct {
 	FromDay string `short:"f" long:"from" description:"the day from which to start summarizing" value-name:"<yyyy-mm-dd>" required:"true"`
 	TilDay  string `short:"t" long:"til" description:"the day til which to summarize (inclusive)" value-name:"<yyyy-mm-dd>" required:"true"`
 
-	HumanReadable        bool   `long:"human-readable" description:"format times as hours and minutes"`
+	HumanReadable        bool   `long:"human-readable" description:"format times as hours and minutes (for table output)"`
 	CategoryFilterString string `long:"category-filter" description:"a filter for categories; any named categories included; all included if omitted" value-name:"<cat1>,<cat2>,..."`
 
+	Output  string `short:"o" long:"output" choice:"table" choice:"json" choice:"csv" description:"the output format" default:"table"`
+	GroupBy string `short:"g" long:"group-by" choice:"category" choice:"day" choice:"week" choice:"month" description:"summarize the entire range per category, or each day, week or month separately" default:"category"`
+
 	Verbose bool `short:"v" long:"verbose" description:"provide verbose output"`
 }
 
@@ -31,56 +30,29 @@ type SummarizeCommand struct {
 // (This gets called by `go-flags` when `summarize` is provided on the command
 // line)
 func (command *SummarizeCommand) Execute(args []string) error {
-	var envData control.EnvData
-
-	// set up dir per option
-	dayplanHome := os.Getenv("DAYPLAN_HOME")
-	if dayplanHome == "" {
-		envData.BaseDirPath = os.Getenv("HOME") + "/.config/dayplan"
-	} else {
-		envData.BaseDirPath = strings.TrimRight(dayplanHome, "/")
-	}
+	baseDirPath := getBaseDirPath()
 
-	// read config from file (for the category priorities)
-	yamlData, err := os.ReadFile(envData.BaseDirPath + "/" + "config.yaml")
+	// read config from file (for the category priorities, goals and limits)
+	configData, err := readConfig(baseDirPath, config.Light)
 	if err != nil {
-		panic(fmt.Sprintf("can't read config file: '%s'", err))
+		return err
 	}
-	configData, err := config.ParseConfigAugmentDefaults(config.Light, yamlData)
+	absences, err := readAbsences(baseDirPath)
 	if err != nil {
-		panic(fmt.Sprintf("can't parse config data: '%s'", err))
+		return err
 	}
-	styledCategories := styling.EmptyCategoryStyling()
-	for _, category := range configData.Categories {
-		var goal model.Goal
-		var err error
-		switch {
-		case category.Goal.Ranged != nil:
-			goal, err = model.NewRangedGoalFromConfig(*category.Goal.Ranged)
-		case category.Goal.Workweek != nil:
-			goal, err = model.NewWorkweekGoalFromConfig(*category.Goal.Workweek)
-		}
-		if err != nil {
-			return err
-		}
-
-		cat := model.Category{
-			Name:     category.Name,
-			Priority: category.Priority,
-			Goal:     goal,
-		}
-		style := styling.StyleFromHexSingle(category.Color, false)
-		styledCategories.Add(cat, style)
+	styledCategories, err := categoryStylingFromConfig(configData, false, absences)
+	if err != nil {
+		return err
 	}
-
-	startDate, err := model.FromString(Opts.SummarizeCommand.FromDay)
+	patternGoals, err := patternGoalsFromConfig(configData, absences)
 	if err != nil {
-		log.Fatalf("from date '%s' invalid", Opts.SummarizeCommand.FromDay)
+		return err
 	}
-	currentDate := startDate
-	finalDate, err := model.FromString(Opts.SummarizeCommand.TilDay)
+
+	startDate, finalDate, err := parseDateRange(Opts.SummarizeCommand.FromDay, Opts.SummarizeCommand.TilDay)
 	if err != nil {
-		log.Fatalf("til date '%s' invalid", Opts.SummarizeCommand.TilDay)
+		return err
 	}
 
 	filterCategories := len(Opts.SummarizeCommand.CategoryFilterString) > 0
@@ -90,68 +62,54 @@ func (command *SummarizeCommand) Execute(args []string) error {
 			includeCategoriesByName[name] = struct{}{}
 		}
 	}
-
-	// TODO: can probably make this mostly async?
-	days := make([]model.Day, 0)
-	for currentDate != finalDate.Next() {
-		fh := storage.NewFileHandler(envData.BaseDirPath + "/days/" + currentDate.ToString())
-		categories := make([]model.Category, 0)
-		for _, cat := range styledCategories.GetAll() {
-			categories = append(categories, cat.Cat)
-		}
-		days = append(days, *fh.Read(categories))
-
-		currentDate = currentDate.Next()
-	}
-
 	categoryIncluded := func(cat model.Category) bool {
+		if !filterCategories {
+			return true
+		}
 		_, ok := includeCategoriesByName[cat.Name]
 		return ok
 	}
 
-	totalSummary := make(map[model.Category]int)
-	for _, day := range days {
-		daySummary := day.SumUpByCategory()
-		for category, duration := range daySummary {
-			totalSummary[category] += duration
-		}
+	days := make([]*model.Day, 0)
+	for _, entry := range readDays(baseDirPath, getCategories(styledCategories), startDate, finalDate) {
+		days = append(days, entry.Day)
 	}
 
-	if Opts.SummarizeCommand.Verbose {
-		fmt.Println("dayplan time summary:")
-
-		fmt.Println("from:            ", Opts.SummarizeCommand.FromDay)
-		fmt.Println("til:             ", Opts.SummarizeCommand.TilDay)
-		fmt.Println("category filter: ", Opts.SummarizeCommand.CategoryFilterString)
-
-		fmt.Println("read", len(days), "days")
-		fmt.Println("total summary:")
+	rows, err := summary.Summarize(startDate, days, summary.GroupBy(Opts.SummarizeCommand.GroupBy), categoryIncluded, patternGoals)
+	if err != nil {
+		return err
 	}
 
-	for category, duration := range totalSummary {
-		if filterCategories && !categoryIncluded(category) {
-			continue
+	if Opts.SummarizeCommand.Verbose {
+		// keep machine-readable output clean
+		var verboseOut io.Writer = os.Stdout
+		if Opts.SummarizeCommand.Output != "table" {
+			verboseOut = os.Stderr
 		}
+		fmt.Fprintln(verboseOut, "dayplan time summary:")
 
-		var durationStr string
-		if Opts.SummarizeCommand.HumanReadable {
-			durationStr = fmt.Sprint(util.DurationToString(duration))
-		} else {
-			durationStr = fmt.Sprint(duration, " min")
-		}
+		fmt.Fprintln(verboseOut, "from:            ", Opts.SummarizeCommand.FromDay)
+		fmt.Fprintln(verboseOut, "til:             ", Opts.SummarizeCommand.TilDay)
+		fmt.Fprintln(verboseOut, "category filter: ", Opts.SummarizeCommand.CategoryFilterString)
+		fmt.Fprintln(verboseOut, "grouped by:      ", Opts.SummarizeCommand.GroupBy)
 
-		var goalStr string = ""
-		if category.Goal != nil {
-			goal := model.GoalForRange(category.Goal, startDate, finalDate)
-			actual := time.Duration(duration) * time.Minute
-			deficit := goal - actual
-			deficitStr := fmt.Sprint(deficit - (deficit % time.Minute))
-			goalStr = fmt.Sprintf("(%.2f%% of goal, %s deficit)", (float64(actual)/float64(goal))*100.0, deficitStr)
-		}
+		fmt.Fprintln(verboseOut, "read", len(days), "days")
+		fmt.Fprintln(verboseOut, "summary:")
+	}
 
-		fmt.Print("  ")
-		fmt.Printf("% 20s (prio:% 3d): % 10s %s\n", category.Name, category.Priority, durationStr, goalStr)
+	// the table shows over-limit warnings inline
+	if Opts.SummarizeCommand.Output != "table" {
+		for _, warning := range summary.Warnings(rows) {
+			fmt.Fprintln(os.Stderr, "warning:", warning)
+		}
 	}
 
-	return nil
+	switch Opts.SummarizeCommand.Output {
+	case "json":
+		return summary.WriteJSON(os.Stdout, rows)
+	case "csv":
+		return summary.WriteCSV(os.Stdout, rows)
+	default:
+		return summary.WriteTable(os.Stdout, rows, Opts.SummarizeCommand.HumanReadable)
+	}
 }
@@ -2,30 +2,36 @@ package cli
 
 import (
 	"fmt"
-	"log"
 	"os"
-	"regexp"
-	"strings"
-	"time"
 
 	"github.com/ja-he/dayplan/internal/config"
-	"github.com/ja-he/dayplan/internal/control"
 	"github.com/ja-he/dayplan/internal/model"
-	"github.com/ja-he/dayplan/internal/storage"
-	"github.com/ja-he/dayplan/internal/styling"
-	"github.com/ja-he/dayplan/internal/util"
+	"github.com/ja-he/dayplan/internal/timesheet"
 )
 
 // TimesheetCommand is the command `timesheet`, which produces a timesheet for
 // a given category.
 //
-// A timesheet has entries per day, each of the form
+// A timesheet has entries per day; by default (template "csv"), each of the
+// form
 //
-//	<start-time>,<break-duration>,<end-time>
+//	<date>,<start-time>,<break-duration>,<end-time>
 //
 // e.g.
 //
-//	08:50,45min,16:20
+//	2022-12-05,08:50,45min,16:20
+//
+// The template "csv-absences" adds a column with the kind of absence, and
+// lists days of absence even without an entry, e.g.
+//
+//	2022-12-05,08:50,45min,16:20,
+//	2022-12-06,00:00,0s,00:00,vacation
+//
+// Other formats are available as built-in templates or can be defined as Go
+// templates (see timesheet.Data).
+//
+// Entries are rounded and checked for compliance (e.g. minimum breaks) as
+// configured; violations are reported on stderr.
 type TimesheetCommand struct {
 	FromDay string `short:"f" long:"from" description:"the day from which to start summarizing" value-name:"<yyyy-mm-dd>" required:"true"`
 	TilDay  string `short:"t" long:"til" description:"the day til which to summarize (inclusive)" value-name:"<yyyy-mm-dd>" required:"true"`
@@ -38,6 +44,11 @@ type TimesheetCommand struct {
 	Enquote        bool   `long:"enquote" description:"add quotes around field values"`
 	FieldSeparator string `long:"field-separator" value-name:"<CSV separator (default ',')>" default:","`
 	DurationFormat string `long:"duration-format" option:"golang" option:"colon-delimited" default:"golang"`
+	Template       string `long:"template" value-name:"<name|file>" description:"the output template, either a built-in one (csv, csv-absences, markdown, weekly-csv) or a Go text/template file" default:"csv"`
+	Project        string `long:"project" value-name:"<name>" description:"the project the timesheet is for (available to templates)"`
+
+	NoRounding bool   `long:"no-rounding" description:"don't round entries (ignoring the rounding configured in config.yaml)"`
+	Compliance string `long:"compliance" choice:"off" choice:"flag" choice:"adjust" description:"whether to check entries against the configured compliance rules and only flag violations or adjust them (default per config.yaml)"`
 }
 
 // Execute executes the timesheet command.
@@ -46,96 +57,52 @@ func (command *TimesheetCommand) Execute(args []string) error {
 		return fmt.Errorf("at least one of '--category-include-filter'/'-i' and '--category-exclude-filter'/'-e' is required")
 	}
 
-	var envData control.EnvData
-
-	// set up dir per option
-	dayplanHome := os.Getenv("DAYPLAN_HOME")
-	if dayplanHome == "" {
-		envData.BaseDirPath = os.Getenv("HOME") + "/.config/dayplan"
-	} else {
-		envData.BaseDirPath = strings.TrimRight(dayplanHome, "/")
-	}
+	baseDirPath := getBaseDirPath()
 
 	// read config from file (for the category priorities)
-	yamlData, err := os.ReadFile(envData.BaseDirPath + "/" + "config.yaml")
+	configData, err := readConfig(baseDirPath, config.Light)
 	if err != nil {
-		panic(fmt.Sprintf("can't read config file: '%s'", err))
+		return err
 	}
-	configData, err := config.ParseConfigAugmentDefaults(config.Light, yamlData)
+	styledCategories, err := categoryStylingFromConfig(configData, false, nil)
 	if err != nil {
-		panic(fmt.Sprintf("can't parse config data: '%s'", err))
+		return err
 	}
-	styledCategories := styling.EmptyCategoryStyling()
-	for _, category := range configData.Categories {
-		var goal model.Goal
-		var err error
-		switch {
-		case category.Goal.Ranged != nil:
-			goal, err = model.NewRangedGoalFromConfig(*category.Goal.Ranged)
-		case category.Goal.Workweek != nil:
-			goal, err = model.NewWorkweekGoalFromConfig(*category.Goal.Workweek)
-		}
-		if err != nil {
-			return err
-		}
-
-		cat := model.Category{
-			Name:     category.Name,
-			Priority: category.Priority,
-			Goal:     goal,
-		}
-		style := styling.StyleFromHexSingle(category.Color, false)
-		styledCategories.Add(cat, style)
-	}
-
-	startDate, err := model.FromString(command.FromDay)
+	absences, err := readAbsences(baseDirPath)
 	if err != nil {
-		log.Fatalf("from date '%s' invalid", command.FromDay)
+		return err
 	}
-	currentDate := startDate
-	finalDate, err := model.FromString(command.TilDay)
+	rules, err := timesheet.NewRulesFromConfig(configData.Timesheet)
 	if err != nil {
-		log.Fatalf("til date '%s' invalid", command.TilDay)
+		return fmt.Errorf("invalid timesheet config (%w)", err)
 	}
-
-	type dateAndDay struct {
-		model.Date
-		model.Day
+	if command.NoRounding {
+		rules.StartRounding, rules.EndRounding, rules.BreakRounding = timesheet.Rounding{}, timesheet.Rounding{}, timesheet.Rounding{}
 	}
-
-	data := make([]dateAndDay, 0)
-	for currentDate != finalDate.Next() {
-		fh := storage.NewFileHandler(envData.BaseDirPath + "/days/" + currentDate.ToString())
-		categories := make([]model.Category, 0)
-		for _, cat := range styledCategories.GetAll() {
-			categories = append(categories, cat.Cat)
-		}
-		data = append(data, dateAndDay{currentDate, *fh.Read(categories)})
-
-		currentDate = currentDate.Next()
+	switch command.Compliance {
+	case "off":
+		rules.Breaks, rules.MaxWork = nil, 0
+	case "flag":
+		rules.Adjust = false
+	case "adjust":
+		rules.Adjust = true
 	}
 
-	var includeRegex, excludeRegex *regexp.Regexp
-	if command.CategoryIncludeFilter != "" {
-		includeRegex, err = regexp.Compile(command.CategoryIncludeFilter)
-		if err != nil {
-			return fmt.Errorf("category include filter regex is invalid (%s)", err.Error())
-		}
+	templateText, err := timesheet.LoadTemplate(command.Template)
+	if err != nil {
+		return err
 	}
-	if command.CategoryExcludeFilter != "" {
-		excludeRegex, err = regexp.Compile(command.CategoryExcludeFilter)
-		if err != nil {
-			return fmt.Errorf("category exclude filter regex is invalid (%s)", err.Error())
-		}
+	listAbsences := timesheet.ShowsAbsences(templateText)
+
+	startDate, finalDate, err := parseDateRange(command.FromDay, command.TilDay)
+	if err != nil {
+		return err
 	}
-	matcher := func(catName string) bool {
-		if includeRegex != nil && !includeRegex.MatchString(catName) {
-			return false
-		}
-		if excludeRegex != nil && excludeRegex.MatchString(catName) {
-			return false
-		}
-		return true
+	data := readDays(baseDirPath, getCategories(styledCategories), startDate, finalDate)
+
+	matcher, err := newCategoryMatcher(command.CategoryIncludeFilter, command.CategoryExcludeFilter)
+	if err != nil {
+		return err
 	}
 
 	func() {
@@ -147,64 +114,51 @@ func (command *TimesheetCommand) Execute(args []string) error {
 		}
 	}()
 
+	type dayViolations struct {
+		date       model.Date
+		violations []timesheet.Violation
+	}
+	violatingDays := make([]dayViolations, 0)
+
+	sheet := timesheet.Data{From: startDate, Til: finalDate, Project: command.Project}
 	for _, dataEntry := range data {
-		timesheetEntry := dataEntry.Day.GetTimesheetEntry(matcher)
+		timesheetEntry := rules.Round(dataEntry.Day.GetTimesheetEntry(matcher))
+		timesheetEntry, violations := rules.Check(timesheetEntry)
+		if len(violations) > 0 {
+			violatingDays = append(violatingDays, dayViolations{dataEntry.Date, violations})
+		}
+		absence, isAbsence := absences.Get(dataEntry.Date)
 
-		if !command.IncludeEmpty && timesheetEntry.IsEmpty() {
+		if !command.IncludeEmpty && timesheetEntry.IsEmpty() && !(isAbsence && listAbsences) {
 			continue
 		}
 
-		maybeEnquote := func(s string) string {
-			if command.Enquote {
-				return util.Enquote(s)
-			} else {
-				return s
-			}
-		}
+		sheet.Days = append(sheet.Days, timesheet.Day{Date: dataEntry.Date, Entry: timesheetEntry, Absence: string(absence.Kind)})
+	}
 
-		stringifyTimestamp := func(ts model.Timestamp) string {
-			return ts.ToString()
-		}
+	err = timesheet.Write(os.Stdout, templateText, sheet, timesheet.FormatOptions{
+		DateFormat:     command.DateFormat,
+		DurationFormat: command.DurationFormat,
+		Enquote:        command.Enquote,
+		Separator:      command.FieldSeparator,
+	})
+	if err != nil {
+		return fmt.Errorf("error writing timesheet (%w)", err)
+	}
 
-		stringifyDuration := func(dur time.Duration) string {
-			switch command.DurationFormat {
-			case "golang":
-				str := dur.String()
-				if strings.HasSuffix(str, "m0s") {
-					str = strings.TrimSuffix(str, "0s")
+	if rules.HasCompliance() {
+		fmt.Fprintln(os.Stderr, "COMPLIANCE REPORT:")
+		for _, day := range violatingDays {
+			for _, violation := range day.violations {
+				adjustmentStr := ""
+				if violation.Adjustment != "" {
+					adjustmentStr = " (adjusted: " + violation.Adjustment + ")"
 				}
-				return str
-			case "colon-delimited":
-				durHours := dur.Truncate(time.Hour)
-				durMins := (dur - durHours)
-				return fmt.Sprintf("%d:%02d", int(durHours.Hours()), int(durMins.Minutes()))
-			default:
-				panic("unhandled case '" + command.DurationFormat + "'")
+				fmt.Fprintf(os.Stderr, "  %s: %s%s\n", day.date.ToString(), violation.Problem, adjustmentStr)
 			}
 		}
-
-		fmt.Println(
-			strings.Join(
-				[]string{
-					maybeEnquote(dataEntry.Date.ToGotime().Format(command.DateFormat)),
-					asCSVString(timesheetEntry, maybeEnquote, stringifyTimestamp, stringifyDuration, command.FieldSeparator),
-				},
-				command.FieldSeparator,
-			),
-		)
+		fmt.Fprintf(os.Stderr, "  %d of %d days in violation\n", len(violatingDays), len(data))
 	}
 
 	return nil
 }
-
-// asCSVString returns this TimesheetEntry in CSV format.
-func asCSVString(e model.TimesheetEntry, processFieldString func(string) string, stringifyTimestamp func(model.Timestamp) string, stringifyDuration func(time.Duration) string, separator string) string {
-	return strings.Join(
-		[]string{
-			processFieldString(stringifyTimestamp(e.Start)),
-			processFieldString(stringifyDuration(e.BreakDuration)),
-			processFieldString(stringifyTimestamp(e.End)),
-		},
-		separator,
-	)
-}
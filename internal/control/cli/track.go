@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/storage"
+	"github.com/ja-he/dayplan/internal/tracking"
+	"github.com/ja-he/dayplan/internal/util"
+)
+
+// StartCommand is the command `start`, which starts live tracking of an event
+// at the current time.
+type StartCommand struct {
+	Category string `short:"c" long:"category" description:"the category of the tracked event" value-name:"<category>" required:"true"`
+	Name     string `short:"n" long:"name" description:"the name of the tracked event" value-name:"<name>" required:"true"`
+}
+
+// StopCommand is the command `stop`, which stops live tracking of the running
+// event at the current time.
+type StopCommand struct{}
+
+// SwitchCommand is the command `switch`, which stops the running event and
+// starts tracking another one.
+type SwitchCommand struct {
+	Category string `short:"c" long:"category" description:"the category of the newly tracked event" value-name:"<category>" required:"true"`
+	Name     string `short:"n" long:"name" description:"the name of the newly tracked event" value-name:"<name>" required:"true"`
+}
+
+// Execute executes the start command.
+func (command *StartCommand) Execute(args []string) error {
+	return startTracking(command.Category, command.Name, time.Now())
+}
+
+// Execute executes the stop command.
+func (command *StopCommand) Execute(args []string) error {
+	return stopTracking(time.Now())
+}
+
+// Execute executes the switch command.
+func (command *SwitchCommand) Execute(args []string) error {
+	now := time.Now()
+	if err := stopTracking(now); err != nil {
+		return err
+	}
+	return startTracking(command.Category, command.Name, now)
+}
+
+// startTracking adds an event with the given category and name starting at the
+// given time to its day and persists it as the running event.
+func startTracking(category, name string, now time.Time) error {
+	if strings.ContainsAny(category, "|\n\r") {
+		return fmt.Errorf("category name cannot contain '|' or line breaks")
+	}
+	if strings.ContainsAny(name, "\n\r") {
+		return fmt.Errorf("name cannot contain line breaks")
+	}
+
+	baseDirPath := getBaseDirPath()
+	trackingPath := baseDirPath + "/" + tracking.FileName
+	running, err := tracking.Read(trackingPath)
+	if err != nil {
+		return err
+	}
+	if running != nil {
+		return fmt.Errorf("already tracking '%s' (%s) since %s %s; use 'stop' or 'switch'", running.Name, running.Category, running.Date.ToString(), running.Start.ToString())
+	}
+
+	categories, err := readCategories(baseDirPath)
+	if err != nil {
+		return err
+	}
+	known := false
+	for _, cat := range categories {
+		known = known || cat.Name == category
+	}
+	if !known {
+		fmt.Fprintf(os.Stderr, "WARNING: category '%s' not found in config data\n", category)
+	}
+
+	dateAndTime := model.FromTime(now)
+	if dateAndTime.Timestamp == (model.Timestamp{Hour: 23, Minute: 59}) {
+		return fmt.Errorf("cannot start tracking in the last minute of the day")
+	}
+	running = &tracking.Running{
+		Date:     dateAndTime.Date,
+		Start:    dateAndTime.Timestamp,
+		Category: category,
+		Name:     name,
+	}
+
+	dayFile := storage.NewFileHandler(baseDirPath + "/days/" + running.Date.ToString())
+	day := dayFile.Read(categories)
+	if running.Find(day) != nil {
+		return fmt.Errorf("an identical event starting at %s already exists", running.Start.ToString())
+	}
+	err = day.AddEvent(running.ToEvent(dateAndTime.Date, dateAndTime.Timestamp, categories))
+	if err != nil {
+		return err
+	}
+	dayFile.Write(day)
+
+	err = tracking.Write(trackingPath, *running)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("started '%s' (%s) at %s\n", name, category, running.Start.ToString())
+	return nil
+}
+
+// stopTracking ends the running event at the given time and clears the
+// running event.
+func stopTracking(now time.Time) error {
+	baseDirPath := getBaseDirPath()
+	trackingPath := baseDirPath + "/" + tracking.FileName
+	running, err := tracking.Read(trackingPath)
+	if err != nil {
+		return err
+	}
+	if running == nil {
+		return fmt.Errorf("no event is being tracked")
+	}
+
+	categories, err := readCategories(baseDirPath)
+	if err != nil {
+		return err
+	}
+
+	dateAndTime := model.FromTime(now)
+	dayFile := storage.NewFileHandler(baseDirPath + "/days/" + running.Date.ToString())
+	day := dayFile.Read(categories)
+	event := running.Find(day)
+	if event == nil {
+		fmt.Fprintf(os.Stderr, "WARNING: running event '%s' (%s) not found on %s, it may have been changed or removed; leaving the day as is\n", running.Name, running.Category, running.Date.ToString())
+		return tracking.Clear(trackingPath)
+	}
+	err = day.ResizeTo(event, running.EndAt(dateAndTime.Date, dateAndTime.Timestamp))
+	if err != nil {
+		return err
+	}
+	if dateAndTime.Date.IsAfter(running.Date) {
+		fmt.Fprintf(os.Stderr, "WARNING: event ran past midnight, ending it at %s on %s\n", event.End.ToString(), running.Date.ToString())
+	}
+	dayFile.Write(day)
+
+	err = tracking.Clear(trackingPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("stopped '%s' (%s), %s-%s (%s)\n", event.Name, event.Cat.Name, event.Start.ToString(), event.End.ToString(), util.DurationToString(event.Duration()))
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"github.com/ja-he/dayplan/internal/balance"
 	"github.com/ja-he/dayplan/internal/config"
 	"github.com/ja-he/dayplan/internal/control"
 	"github.com/ja-he/dayplan/internal/model"
@@ -23,6 +24,7 @@ type TUICommand struct {
 	Theme         string `short:"t" long:"theme" choice:"light" choice:"dark" description:"Select a 'dark' or a 'light' default theme (note: only sets defaults, which are individually overridden by settings in config.yaml"`
 	LogOutputFile string `short:"l" long:"log-output-file" description:"specify a log output file (otherwise logs dropped)"`
 	LogPretty     bool   `short:"p" long:"log-pretty" description:"prettify logs to file"`
+	AgendaDays    int    `short:"a" long:"agenda-days" default:"14" description:"the number of days (from today on) listed in the agenda view"`
 }
 
 // Execute runs the TUI command.
@@ -95,30 +97,25 @@ func (command *TUICommand) Execute(_ []string) error {
 		return fmt.Errorf("can't parse config data (%w)", err)
 	}
 
+	absences, err := readAbsences(envData.BaseDirPath)
+	if err != nil {
+		return err
+	}
+
 	// get categories from config
-	categoryStyling := *styling.EmptyCategoryStyling()
-	for _, category := range configData.Categories {
-
-		var goal model.Goal
-		var err error
-		switch {
-		case category.Goal.Ranged != nil:
-			goal, err = model.NewRangedGoalFromConfig(*category.Goal.Ranged)
-		case category.Goal.Workweek != nil:
-			goal, err = model.NewWorkweekGoalFromConfig(*category.Goal.Workweek)
-		}
-		if err != nil {
-			return err
-		}
+	categoryStyling, err := categoryStylingFromConfig(configData, theme == config.Dark, absences)
+	if err != nil {
+		return err
+	}
 
-		cat := model.Category{
-			Name:       category.Name,
-			Priority:   category.Priority,
-			Goal:       goal,
-			Deprecated: category.Deprecated,
-		}
-		style := styling.StyleFromHexSingle(category.Color, theme == config.Dark)
-		categoryStyling.Add(cat, style)
+	patternGoals, err := patternGoalsFromConfig(configData, absences)
+	if err != nil {
+		return err
+	}
+
+	balanceSettings, err := balance.NewSettingsFromConfig(configData.Balance)
+	if err != nil {
+		return fmt.Errorf("can't parse balance config (%w)", err)
 	}
 
 	stylesheet := styling.NewStylesheetFromConfig(configData.Stylesheet)
@@ -129,7 +126,11 @@ func (command *TUICommand) Execute(_ []string) error {
 	log.Logger = tuiLogger
 	log.Debug().Msg("set up logging to only TUI")
 
-	controller, err := NewController(initialDay, envData, categoryStyling, *stylesheet)
+	if command.AgendaDays < 1 {
+		return fmt.Errorf("invalid number of agenda days %d", command.AgendaDays)
+	}
+
+	controller, err := NewController(initialDay, envData, *categoryStyling, *stylesheet, balanceSettings, absences, patternGoals, configData.Keybindings, command.AgendaDays)
 	if err != nil {
 		log.Logger = previouslySetLogger
 		log.Error().Err(err).Msgf("something went wrong setting up the TUI, will check unpublished logs and return error")
@@ -0,0 +1,191 @@
+// Package command provides the commands of the TUI's command line (as in
+// ":goto 2026-10-01"), their completion, and the prompt they are entered in.
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ja-he/dayplan/internal/control/action"
+	"github.com/ja-he/dayplan/internal/input"
+)
+
+// Command is a command of the command line.
+type Command struct {
+	// Name is what the command is invoked by, e.g. "goto" for
+	// ":goto 2026-10-01".
+	Name string
+	// Args describes the arguments, e.g. "<date>", for help.
+	Args string
+
+	// Action is the ID of the (registered) action the command does, for
+	// commands without arguments; if it is given, Explain and Run are ignored.
+	Action input.Actionspec
+
+	// Explain describes what the command does.
+	Explain func() string
+	// Run runs the command with the given arguments.
+	Run func(args []string) error
+	// Complete, if given, returns the possible values for the last of the given
+	// arguments (which may be empty), considering the ones before it.
+	Complete func(args []string) []string
+}
+
+// Registry holds the commands of the command line.
+// Besides its commands, every action of the given actions is available as a
+// command (without arguments) by its ID, e.g. ":day.write", except for the
+// actions scoped to a mode (see ScopeToModes).
+type Registry struct {
+	commands map[string]Command
+	actions  map[input.Actionspec]action.Action
+
+	// modeScoped are the prefixes of the IDs of actions scoped to a mode
+	modeScoped []string
+}
+
+// NewRegistry returns a new registry for the given actions.
+// The actions are looked up when needed, so they can be registered after the
+// registry is constructed.
+func NewRegistry(actions map[input.Actionspec]action.Action) *Registry {
+	return &Registry{
+		commands: make(map[string]Command),
+		actions:  actions,
+	}
+}
+
+// Register adds the given commands to the registry.
+func (r *Registry) Register(commands ...Command) {
+	for _, c := range commands {
+		r.commands[c.Name] = c
+	}
+}
+
+// ScopeToModes marks the actions whose IDs start with any of the given
+// prefixes (e.g. "move.") as scoped to a mode, i.e., as only making sense in
+// the mode they are mapped in, so that they are not available as commands.
+func (r *Registry) ScopeToModes(prefixes ...string) {
+	r.modeScoped = append(r.modeScoped, prefixes...)
+}
+
+func (r *Registry) isModeScoped(actionspec input.Actionspec) bool {
+	for _, prefix := range r.modeScoped {
+		if strings.HasPrefix(string(actionspec), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run parses the given line (without the leading ':') into a command and its
+// whitespace-separated arguments and runs it.
+// An empty line does nothing.
+func (r *Registry) Run(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+	name, args := fields[0], fields[1:]
+
+	c, isCommand := r.commands[name]
+	actionspec := c.Action
+	if !isCommand {
+		actionspec = input.Actionspec(name)
+	}
+	if actionspec != "" {
+		a, ok := r.actions[actionspec]
+		if !ok {
+			if isCommand {
+				return fmt.Errorf("command '%s' is for unknown action '%s'", name, actionspec)
+			}
+			return fmt.Errorf("not a command: '%s'", name)
+		}
+		if r.isModeScoped(actionspec) {
+			return fmt.Errorf("action '%s' is only available in its mode", actionspec)
+		}
+		if len(args) > 0 {
+			return fmt.Errorf("command '%s' takes no arguments", name)
+		}
+		a.Do()
+		return nil
+	}
+
+	err := c.Run(args)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// Complete returns the completions of the given line, i.e., the lines with
+// its last word completed, in sorted order.
+// The first word is completed to the command names and action IDs, the others
+// per the command's Complete.
+func (r *Registry) Complete(line string) []string {
+	lastWordStart := strings.LastIndexAny(line, " \t") + 1
+	prefix, lastWord := line[:lastWordStart], line[lastWordStart:]
+
+	var candidates []string
+	fields := strings.Fields(prefix)
+	if len(fields) == 0 {
+		candidates = r.names()
+	} else {
+		c, ok := r.commands[fields[0]]
+		if !ok || c.Complete == nil {
+			return nil
+		}
+		candidates = c.Complete(append(fields[1:], lastWord))
+		sort.Strings(candidates)
+	}
+
+	result := make([]string, 0)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, lastWord) {
+			result = append(result, prefix+candidate)
+		}
+	}
+	return result
+}
+
+// Help returns the help for the commands, e.g. ":goto <date>" explained as
+// "go to date", plus an entry for running actions by their IDs.
+func (r *Registry) Help() input.Help {
+	result := input.Help{
+		":<action>": "do an action by its ID (e.g. ':day.write')",
+	}
+	for name, c := range r.commands {
+		usage := ":" + name
+		if c.Args != "" {
+			usage += " " + c.Args
+		}
+		result[usage] = r.explain(c)
+	}
+	return result
+}
+
+func (r *Registry) explain(c Command) string {
+	if c.Action != "" {
+		a, ok := r.actions[c.Action]
+		if !ok {
+			return fmt.Sprintf("(unknown action '%s')", c.Action)
+		}
+		return a.Explain()
+	}
+	return c.Explain()
+}
+
+// names returns the command names and (not mode scoped) action IDs in sorted
+// order.
+func (r *Registry) names() []string {
+	result := make([]string, 0, len(r.commands)+len(r.actions))
+	for name := range r.commands {
+		result = append(result, name)
+	}
+	for actionspec := range r.actions {
+		if _, shadowed := r.commands[string(actionspec)]; !shadowed && !r.isModeScoped(actionspec) {
+			result = append(result, string(actionspec))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,211 @@
+package command_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ja-he/dayplan/internal/control/action"
+	"github.com/ja-he/dayplan/internal/control/command"
+	"github.com/ja-he/dayplan/internal/input"
+)
+
+func newTestRegistry(written *bool, gone *[]string) *command.Registry {
+	actions := map[input.Actionspec]action.Action{
+		"day.write": action.NewSimple(func() string { return "write day" }, func() { *written = true }),
+		"move.up":   action.NewSimple(func() string { return "move up" }, func() { panic("mode scoped action done") }),
+	}
+	r := command.NewRegistry(actions)
+	r.ScopeToModes("move.")
+	r.Register(
+		command.Command{Name: "w", Action: "day.write"},
+		command.Command{Name: "broken", Action: "no.such-action"},
+		command.Command{
+			Name:    "goto",
+			Args:    "<date>",
+			Explain: func() string { return "go to date" },
+			Run: func(args []string) error {
+				if len(args) != 1 {
+					return fmt.Errorf("expected a date")
+				}
+				*gone = append(*gone, args[0])
+				return nil
+			},
+			Complete: func(args []string) []string {
+				if len(args) == 1 {
+					return []string{"tomorrow", "today", "yesterday"}
+				}
+				return nil
+			},
+		},
+	)
+	return r
+}
+
+func TestRegistryRun(t *testing.T) {
+	written, gone := false, []string{}
+	r := newTestRegistry(&written, &gone)
+
+	if err := r.Run("  "); err != nil {
+		t.Error("empty line gave error:", err)
+	}
+
+	if err := r.Run("w"); err != nil || !written {
+		t.Errorf("command for action not done (err: %v)", err)
+	}
+	written = false
+	if err := r.Run("day.write"); err != nil || !written {
+		t.Errorf("action by ID not done (err: %v)", err)
+	}
+	if err := r.Run("w now"); err == nil {
+		t.Error("no error on arguments to command for action")
+	}
+
+	if err := r.Run("goto  2026-10-01 "); err != nil || !reflect.DeepEqual(gone, []string{"2026-10-01"}) {
+		t.Errorf("command not run with its arguments (err: %v, got %v)", err, gone)
+	}
+	if err := r.Run("goto"); err == nil || err.Error() != "goto: expected a date" {
+		t.Errorf("unexpected error from command: %v", err)
+	}
+
+	for _, line := range []string{"nope", "broken", "move.up"} {
+		if err := r.Run(line); err == nil {
+			t.Errorf("no error on unknown command or unavailable action for '%s'", line)
+		}
+	}
+}
+
+func TestRegistryComplete(t *testing.T) {
+	written, gone := false, []string{}
+	r := newTestRegistry(&written, &gone)
+
+	for line, expected := range map[string][]string{
+		"":        {"broken", "day.write", "goto", "w"},
+		"g":       {"goto"},
+		"goto ":   {"goto today", "goto tomorrow", "goto yesterday"},
+		"goto to": {"goto today", "goto tomorrow"},
+		"goto x":  {},
+		"goto a ": {},
+		"w ":      {},
+	} {
+		got := r.Complete(line)
+		if len(got) != len(expected) || (len(got) > 0 && !reflect.DeepEqual(got, expected)) {
+			t.Errorf("expected completions %v for '%s', got %v", expected, line, got)
+		}
+	}
+}
+
+func TestRegistryHelp(t *testing.T) {
+	written, gone := false, []string{}
+	help := newTestRegistry(&written, &gone).Help()
+	if help[":w"] != "write day" || help[":goto <date>"] != "go to date" || help[":<action>"] == "" {
+		t.Error("unexpected help:", help)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	h := command.History{Max: 2}
+	for _, line := range []string{"a", "", "b", "b", "c"} {
+		h.Add(line)
+	}
+	if !reflect.DeepEqual(h.Entries, []string{"b", "c"}) {
+		t.Error("unexpected history:", h.Entries)
+	}
+}
+
+func TestPromptEditing(t *testing.T) {
+	p := command.NewPrompt(&command.History{}, func(string) []string { return nil })
+	for _, r := range "goto today" {
+		p.Insert(r)
+	}
+	p.DeleteWordBackward()
+	if p.Content() != "goto " || p.Cursor() != 5 {
+		t.Errorf("unexpected content '%s' (cursor %d) after deleting word", p.Content(), p.Cursor())
+	}
+	p.MoveToStart()
+	p.Delete()
+	p.MoveRight()
+	p.Insert('x')
+	if p.Content() != "oxto " || p.Cursor() != 2 {
+		t.Errorf("unexpected content '%s' (cursor %d) after editing", p.Content(), p.Cursor())
+	}
+	p.Clear()
+	if p.Content() != "to " || p.Cursor() != 0 {
+		t.Errorf("unexpected content '%s' (cursor %d) after clearing", p.Content(), p.Cursor())
+	}
+	p.MoveToEnd()
+	for p.Backspace() {
+	}
+	if p.Content() != "" {
+		t.Errorf("unexpected content '%s' after backspacing", p.Content())
+	}
+}
+
+func TestPromptHistory(t *testing.T) {
+	history := &command.History{Entries: []string{"goto today", "w", "goto tomorrow"}}
+	p := command.NewPrompt(history, func(string) []string { return nil })
+
+	p.Insert('g')
+	p.HistoryPrevious()
+	if p.Content() != "goto tomorrow" {
+		t.Errorf("expected latest matching entry, got '%s'", p.Content())
+	}
+	p.HistoryPrevious()
+	if p.Content() != "goto today" {
+		t.Errorf("expected earlier matching entry, got '%s'", p.Content())
+	}
+	p.HistoryPrevious()
+	if p.Content() != "goto today" {
+		t.Errorf("expected to remain at earliest matching entry, got '%s'", p.Content())
+	}
+	p.HistoryNext()
+	p.HistoryNext()
+	if p.Content() != "g" {
+		t.Errorf("expected draft after last entry, got '%s'", p.Content())
+	}
+
+	if p.Submit() != "g" || history.Entries[len(history.Entries)-1] != "g" {
+		t.Error("submitted line not added to history:", history.Entries)
+	}
+}
+
+func TestPromptComplete(t *testing.T) {
+	p := command.NewPrompt(&command.History{}, func(line string) []string {
+		switch line {
+		case "goto t":
+			return []string{"goto today", "goto tomorrow"}
+		case "w":
+			return []string{"wa"}
+		}
+		return nil
+	})
+	for _, r := range "goto t" {
+		p.Insert(r)
+	}
+
+	for _, expected := range []string{"goto today", "goto tomorrow", "goto t", "goto today"} {
+		p.Complete(false)
+		if p.Content() != expected {
+			t.Errorf("expected '%s' when cycling forward, got '%s'", expected, p.Content())
+		}
+	}
+	p.Complete(true)
+	if p.Content() != "goto t" {
+		t.Errorf("expected original content when cycling backward, got '%s'", p.Content())
+	}
+	if completions, i := p.Completions(); len(completions) != 2 || i != 2 {
+		t.Errorf("unexpected completions %v (at %d)", completions, i)
+	}
+
+	p.Insert(' ')
+	if completions, _ := p.Completions(); completions != nil {
+		t.Error("completions not reset on editing:", completions)
+	}
+
+	p.Clear()
+	p.Insert('w')
+	p.Complete(false)
+	if completions, _ := p.Completions(); p.Content() != "wa" || completions != nil {
+		t.Errorf("single completion not final (content '%s', completions %v)", p.Content(), completions)
+	}
+}
@@ -0,0 +1,237 @@
+package command
+
+import (
+	"strings"
+	"unicode"
+)
+
+// History is the history of entered command lines, oldest first.
+type History struct {
+	Entries []string
+	// Max is the maximum number of entries kept (0 meaning no limit).
+	Max int
+}
+
+// Add adds the given line to the history, unless it is empty or the same as
+// the latest entry, dropping the oldest entries beyond the maximum.
+func (h *History) Add(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if len(h.Entries) > 0 && h.Entries[len(h.Entries)-1] == line {
+		return
+	}
+	h.Entries = append(h.Entries, line)
+	if h.Max > 0 && len(h.Entries) > h.Max {
+		h.Entries = h.Entries[len(h.Entries)-h.Max:]
+	}
+}
+
+// Prompt is the state of a command line being entered, i.e., its content and
+// cursor, the position in the history, and the completions being cycled
+// through.
+type Prompt struct {
+	content []rune
+	cursor  int
+
+	history *History
+	// historyIndex is the index of the history entry shown, or the number of
+	// entries while not browsing the history.
+	historyIndex int
+	// draft is the content entered before browsing the history, which entries
+	// are matched against as a prefix.
+	draft string
+
+	complete        func(line string) []string
+	completions     []string
+	completionIndex int
+	// completionBase is the content before completing, which cycling through
+	// the completions returns to after the last one.
+	completionBase string
+}
+
+// NewPrompt returns a new, empty prompt using the given history and
+// completion function (see Registry.Complete).
+func NewPrompt(history *History, complete func(line string) []string) *Prompt {
+	return &Prompt{
+		content:      []rune{},
+		history:      history,
+		historyIndex: len(history.Entries),
+		complete:     complete,
+	}
+}
+
+// Content returns the content of the prompt.
+func (p *Prompt) Content() string { return string(p.content) }
+
+// Cursor returns the cursor position (in runes).
+func (p *Prompt) Cursor() int { return p.cursor }
+
+// Completions returns the completions being cycled through and the index of
+// the current one (which is len(completions) if at the original content).
+func (p *Prompt) Completions() ([]string, int) { return p.completions, p.completionIndex }
+
+// Insert inserts the given rune at the cursor.
+func (p *Prompt) Insert(r rune) {
+	p.edited()
+	p.content = append(p.content[:p.cursor], append([]rune{r}, p.content[p.cursor:]...)...)
+	p.cursor++
+}
+
+// Backspace removes the rune before the cursor.
+// It returns false if the prompt was empty already.
+func (p *Prompt) Backspace() bool {
+	if len(p.content) == 0 {
+		return false
+	}
+	p.edited()
+	if p.cursor > 0 {
+		p.content = append(p.content[:p.cursor-1], p.content[p.cursor:]...)
+		p.cursor--
+	}
+	return true
+}
+
+// Delete removes the rune under the cursor.
+func (p *Prompt) Delete() {
+	if p.cursor < len(p.content) {
+		p.edited()
+		p.content = append(p.content[:p.cursor], p.content[p.cursor+1:]...)
+	}
+}
+
+// DeleteWordBackward removes the word before the cursor (and any whitespace
+// between it and the cursor).
+func (p *Prompt) DeleteWordBackward() {
+	p.edited()
+	start := p.cursor
+	for start > 0 && unicode.IsSpace(p.content[start-1]) {
+		start--
+	}
+	for start > 0 && !unicode.IsSpace(p.content[start-1]) {
+		start--
+	}
+	p.content = append(p.content[:start], p.content[p.cursor:]...)
+	p.cursor = start
+}
+
+// Clear removes everything before the cursor.
+func (p *Prompt) Clear() {
+	p.edited()
+	p.content = p.content[p.cursor:]
+	p.cursor = 0
+}
+
+// MoveLeft moves the cursor left by one rune.
+func (p *Prompt) MoveLeft() {
+	if p.cursor > 0 {
+		p.cursor--
+	}
+}
+
+// MoveRight moves the cursor right by one rune.
+func (p *Prompt) MoveRight() {
+	if p.cursor < len(p.content) {
+		p.cursor++
+	}
+}
+
+// MoveToStart moves the cursor to the start of the content.
+func (p *Prompt) MoveToStart() { p.cursor = 0 }
+
+// MoveToEnd moves the cursor to the end of the content.
+func (p *Prompt) MoveToEnd() { p.cursor = len(p.content) }
+
+// HistoryPrevious shows the previous history entry starting with what was
+// entered before browsing the history, if any.
+func (p *Prompt) HistoryPrevious() {
+	if p.historyIndex == len(p.history.Entries) {
+		p.draft = p.Content()
+	}
+	for i := p.historyIndex - 1; i >= 0; i-- {
+		if strings.HasPrefix(p.history.Entries[i], p.draft) {
+			p.showHistoryEntry(i)
+			return
+		}
+	}
+}
+
+// HistoryNext shows the next history entry starting with what was entered
+// before browsing the history, or, after the last one, what was entered.
+func (p *Prompt) HistoryNext() {
+	if p.historyIndex == len(p.history.Entries) {
+		return
+	}
+	for i := p.historyIndex + 1; i < len(p.history.Entries); i++ {
+		if strings.HasPrefix(p.history.Entries[i], p.draft) {
+			p.showHistoryEntry(i)
+			return
+		}
+	}
+	p.showHistoryEntry(len(p.history.Entries))
+}
+
+func (p *Prompt) showHistoryEntry(i int) {
+	p.historyIndex = i
+	p.completions = nil
+	if i == len(p.history.Entries) {
+		p.setContent(p.draft)
+	} else {
+		p.setContent(p.history.Entries[i])
+	}
+}
+
+// Complete completes the content to the next completion (or, if backward, the
+// previous one), cycling through the completions and the original content.
+func (p *Prompt) Complete(backward bool) {
+	if p.completions == nil {
+		p.completionBase = p.Content()
+		p.completions = p.complete(p.completionBase)
+		p.completionIndex = len(p.completions)
+		if len(p.completions) == 0 {
+			p.completions = nil
+			return
+		}
+	}
+	n := len(p.completions) + 1
+	if backward {
+		p.completionIndex = (p.completionIndex + n - 1) % n
+	} else {
+		p.completionIndex = (p.completionIndex + 1) % n
+	}
+	if p.completionIndex == len(p.completions) {
+		p.setContent(p.completionBase)
+	} else {
+		p.setContent(p.completions[p.completionIndex])
+	}
+	// a single completion is final, so a further completion can start from it
+	if len(p.completions) == 1 {
+		p.completions = nil
+	}
+}
+
+// Submit adds the content to the history and returns it.
+func (p *Prompt) Submit() string {
+	line := p.Content()
+	p.history.Add(line)
+	return line
+}
+
+// edited resets the history browsing and the completions, as the content was
+// edited.
+func (p *Prompt) edited() {
+	p.historyIndex = len(p.history.Entries)
+	p.completions = nil
+}
+
+// SetContent replaces the content of the prompt, e.g. to start it with a
+// command, and moves the cursor to its end.
+func (p *Prompt) SetContent(s string) {
+	p.edited()
+	p.setContent(s)
+}
+
+func (p *Prompt) setContent(s string) {
+	p.content = []rune(s)
+	p.cursor = len(p.content)
+}
@@ -1,12 +1,16 @@
 package control
 
 import (
+	"sort"
 	"sync"
 
+	"github.com/ja-he/dayplan/internal/balance"
+	"github.com/ja-he/dayplan/internal/control/command"
 	"github.com/ja-he/dayplan/internal/control/edit"
 	"github.com/ja-he/dayplan/internal/control/edit/editors"
 	"github.com/ja-he/dayplan/internal/model"
 	"github.com/ja-he/dayplan/internal/styling"
+	"github.com/ja-he/dayplan/internal/tracking"
 	"github.com/ja-he/dayplan/internal/ui"
 	"github.com/ja-he/dayplan/internal/util"
 	"github.com/ja-he/dayplan/internal/weather"
@@ -22,7 +26,7 @@ type EnvData struct {
 
 // PrevView returns the 'previous' view for a given active view, i.E. 'stepping
 // out' from an inner view to an outer one.
-// E.g.: Day -> Week -> Month
+// E.g.: Day -> Week -> Month -> Year
 func PrevView(current ui.ActiveView) ui.ActiveView {
 	switch current {
 	case ui.ViewDay:
@@ -30,7 +34,11 @@ func PrevView(current ui.ActiveView) ui.ActiveView {
 	case ui.ViewWeek:
 		return ui.ViewMonth
 	case ui.ViewMonth:
-		return ui.ViewMonth
+		return ui.ViewYear
+	case ui.ViewYear:
+		return ui.ViewYear
+	case ui.ViewAgenda:
+		return ui.ViewDay
 	default:
 		panic("unknown view!")
 	}
@@ -38,7 +46,7 @@ func PrevView(current ui.ActiveView) ui.ActiveView {
 
 // Next view, for a given active view, returns the 'next', i.E. 'stepping into'
 // from an outer view to an inner one.
-// E.g.: Month -> Week -> Day
+// E.g.: Year -> Month -> Week -> Day
 func NextView(current ui.ActiveView) ui.ActiveView {
 	switch current {
 	case ui.ViewDay:
@@ -47,6 +55,10 @@ func NextView(current ui.ActiveView) ui.ActiveView {
 		return ui.ViewDay
 	case ui.ViewMonth:
 		return ui.ViewWeek
+	case ui.ViewYear:
+		return ui.ViewMonth
+	case ui.ViewAgenda:
+		return ui.ViewDay
 	default:
 		panic("unknown view!")
 	}
@@ -71,9 +83,18 @@ type ControlData struct {
 	CurrentDate model.Date
 	Weather     weather.Handler
 
+	// Jumps are the dates jumped away from, to go back and forth through.
+	Jumps JumpList
+
 	EventEditor *editors.Composite
 	TaskEditor  *editors.Composite
 
+	// CommandLine is the command line being entered, if any.
+	CommandLine *command.Prompt
+	// Message is a message to the user (e.g. the error of a command), shown in
+	// the status bar until the next input.
+	Message string
+
 	ShowLog     bool
 	ShowHelp    bool
 	ShowSummary bool
@@ -92,6 +113,16 @@ type ControlData struct {
 	MouseEditState                   edit.MouseEditState
 	MouseEditedEvent                 *model.Event
 	CurrentMoveStartingOffsetMinutes int
+
+	// Tracking is the event currently being tracked live, if any.
+	Tracking *tracking.Running
+
+	// Balances are the balances of the categories with goals, if configured.
+	Balances []balance.Account
+
+	// Absences are the days of absence (vacation, holidays, ...), which the
+	// categories' goals are adjusted to.
+	Absences *model.Absences
 }
 
 type DaysData struct {
@@ -111,6 +142,8 @@ func NewControlData(cs styling.CategoryStyling) *ControlData {
 		t.Categories = append(t.Categories, style.Cat)
 	}
 
+	t.Jumps.Max = 100
+
 	t.MainTimelineViewParams.NRowsPerHour = 6
 	t.MainTimelineViewParams.ScrollOffset = 8 * t.MainTimelineViewParams.NRowsPerHour
 
@@ -147,6 +180,18 @@ func (d *DaysData) GetDay(date model.Date) *model.Day {
 	return d.days[date].Day
 }
 
+// Dates returns the (sorted) dates of the loaded days.
+func (d *DaysData) Dates() []model.Date {
+	d.daysMutex.RLock()
+	defer d.daysMutex.RUnlock()
+	result := make([]model.Date, 0, len(d.days))
+	for date := range d.days {
+		result = append(result, date)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].IsBefore(result[j]) })
+	return result
+}
+
 func (d *DaysData) AddDay(date model.Date, day *model.Day, suntimes *model.SunTimes) {
 	if day == nil {
 		panic("will not add a nil model")
@@ -0,0 +1,209 @@
+package editors
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/ja-he/dayplan/internal/input"
+	"github.com/ja-he/dayplan/internal/util"
+)
+
+// Choice is one of the values a ChoiceEditor offers to choose from.
+type Choice struct {
+	Name  string
+	Value any
+
+	// Deprecated choices are offered, but choosing them has to be confirmed.
+	Deprecated bool
+
+	unknown bool
+}
+
+// IsUnknown informs on whether the choice is not one of the known choices,
+// i.e., whether it is the value being edited (which was not known) or a new
+// value constructed from the filter.
+func (c Choice) IsUnknown() bool { return c.unknown }
+
+// Choices specifies the known values of a field, for it to be edited by a
+// ChoiceEditor (see ConstructEditor).
+type Choices struct {
+	Known []Choice
+
+	// NameOf returns the name of the given value, e.g. to find the edited
+	// value among the known choices.
+	NameOf func(value any) string
+	// FromName constructs a new (unknown) value from the given name.
+	FromName func(name string) any
+}
+
+// ChoiceEditor edits a value by choosing it from a set of known values, which
+// are filtered (fuzzily) by the contents of the editor.
+//
+// Choosing a deprecated value or an unknown one (i.e., a new one named by the
+// filter) has to be confirmed, by writing it a second time.
+type ChoiceEditor struct {
+	StringEditor
+
+	choices  Choices
+	pool     []Choice
+	original string
+
+	// selected is the name of the selected candidate, which is selected for
+	// the filter selectedFor; when the filter has changed since, the best
+	// candidate is selected instead
+	selected    string
+	selectedFor string
+
+	// confirming is the name of the choice that has to be written again to be
+	// confirmed
+	confirming string
+
+	// CommitValueFn is called with the chosen value on successful writes.
+	CommitValueFn func(reflect.Value)
+
+	err error
+}
+
+// newChoiceEditor returns a choice editor for the given field, offering the
+// given choices.
+func newChoiceEditor(id string, field reflect.Value, choices Choices, parent *Composite) (*ChoiceEditor, error) {
+	for _, choice := range choices.Known {
+		if !reflect.TypeOf(choice.Value).AssignableTo(field.Type()) {
+			return nil, fmt.Errorf("choice '%s' of type '%T' is not assignable to field of type '%s'", choice.Name, choice.Value, field.Type().String())
+		}
+	}
+
+	original := choices.NameOf(field.Interface())
+	pool := append([]Choice{}, choices.Known...)
+	if !containsChoice(pool, original) {
+		pool = append([]Choice{{Name: original, Value: field.Interface(), unknown: true}}, pool...)
+	}
+
+	e := &ChoiceEditor{
+		StringEditor: StringEditor{
+			ID:     id,
+			Mode:   input.TextEditModeNormal,
+			parent: parent,
+		},
+		choices:       choices,
+		pool:          pool,
+		original:      original,
+		selected:      original,
+		CommitValueFn: field.Set,
+	}
+	e.QuitCallback = func() {
+		if parent != nil && parent.activeFieldID == id {
+			parent.inField = false
+		}
+	}
+	return e, nil
+}
+
+// GetType asserts that this is a choice editor.
+func (e *ChoiceEditor) GetType() string { return "choice" }
+
+// GetError returns the error of the last write, if any.
+func (e *ChoiceEditor) GetError() error { return e.err }
+
+// Candidates returns the choices matching the filter, best match first, and
+// the index of the selected one.
+// If the filter does not name a choice exactly, a new choice constructed from
+// it is the last candidate.
+func (e *ChoiceEditor) Candidates() ([]Choice, int) {
+	type scoredChoice struct {
+		choice Choice
+		score  int
+	}
+	scored := make([]scoredChoice, 0, len(e.pool))
+	for _, choice := range e.pool {
+		if score, ok := util.FuzzyMatch(e.Content, choice.Name); ok {
+			scored = append(scored, scoredChoice{choice, score})
+		}
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].score > scored[j].score })
+
+	candidates := make([]Choice, 0, len(scored)+1)
+	for _, s := range scored {
+		candidates = append(candidates, s.choice)
+	}
+	if e.Content != "" && !containsChoice(e.pool, e.Content) {
+		candidates = append(candidates, Choice{Name: e.Content, Value: e.choices.FromName(e.Content), unknown: true})
+	}
+
+	selected := 0
+	if e.selectedFor == e.Content {
+		for i, candidate := range candidates {
+			if candidate.Name == e.selected {
+				selected = i
+			}
+		}
+	}
+	return candidates, selected
+}
+
+// SelectNext selects the next candidate.
+func (e *ChoiceEditor) SelectNext() { e.selectOffset(+1) }
+
+// SelectPrevious selects the previous candidate.
+func (e *ChoiceEditor) SelectPrevious() { e.selectOffset(-1) }
+
+func (e *ChoiceEditor) selectOffset(n int) {
+	candidates, selected := e.Candidates()
+	if selected+n < 0 || selected+n >= len(candidates) {
+		return
+	}
+	e.selected = candidates[selected+n].Name
+	e.selectedFor = e.Content
+}
+
+// Write commits the selected candidate, unless it is deprecated or unknown
+// (and not the edited value) and not yet confirmed.
+func (e *ChoiceEditor) Write() {
+	candidates, selected := e.Candidates()
+	if len(candidates) == 0 {
+		e.err = fmt.Errorf("nothing to choose")
+		return
+	}
+	choice := candidates[selected]
+
+	if choice.Name != e.original && e.confirming != choice.Name {
+		switch {
+		case choice.unknown:
+			e.confirming = choice.Name
+			e.err = fmt.Errorf("'%s' is unknown (write again to confirm)", choice.Name)
+			return
+		case choice.Deprecated:
+			e.confirming = choice.Name
+			e.err = fmt.Errorf("'%s' is deprecated (write again to confirm)", choice.Name)
+			return
+		}
+	}
+
+	e.confirming = ""
+	e.err = nil
+	e.CommitValueFn(reflect.ValueOf(choice.Value))
+}
+
+// CreateInputProcessor creates an input processor for the editor.
+func (e *ChoiceEditor) CreateInputProcessor(cfg input.InputConfig) (input.ModalInputProcessor, error) {
+	return createTextInputProcessor(
+		&e.StringEditor,
+		cfg.StringEditor.Normal.WithOverrides(cfg.ChoiceEditor.Normal),
+		cfg.StringEditor.Insert.WithOverrides(cfg.ChoiceEditor.Insert),
+		map[input.Actionspec]func(){
+			"write":           e.Write,
+			"select-next":     e.SelectNext,
+			"select-previous": e.SelectPrevious,
+		},
+	)
+}
+
+func containsChoice(choices []Choice, name string) bool {
+	for _, choice := range choices {
+		if choice.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package editors_test
+
+import (
+	"testing"
+
+	"github.com/ja-he/dayplan/internal/control/edit/editors"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func constructCategoryChoiceEditor(t *testing.T, event *model.Event) (*editors.Composite, *editors.ChoiceEditor) {
+	choices := editors.Choices{
+		NameOf:   func(value any) string { return value.(model.Category).Name },
+		FromName: func(name string) any { return model.Category{Name: name} },
+	}
+	for _, cat := range []model.Category{
+		{Name: "work", Priority: 2},
+		{Name: "client-acme", Priority: 3},
+		{Name: "meeting", Priority: 4},
+		{Name: "old-project", Priority: 1, Deprecated: true},
+	} {
+		choices.Known = append(choices.Known, editors.Choice{Name: cat.Name, Value: cat, Deprecated: cat.Deprecated})
+	}
+	e, err := editors.ConstructEditor("event", event, map[string]any{"category": choices}, nil)
+	if err != nil {
+		t.Fatalf("could not construct editor: %s", err.Error())
+	}
+	composite := e.(*editors.Composite)
+	return composite, composite.GetFields()["category"].(*editors.ChoiceEditor)
+}
+
+func TestChoiceEditorCandidates(t *testing.T) {
+	event := model.NewEvent("09:00|12:00|work|Coding", nil)
+	_, e := constructCategoryChoiceEditor(t, event)
+
+	candidates, selected := e.Candidates()
+	if len(candidates) != 4 || candidates[selected].Name != "work" {
+		t.Fatalf("expected all 4 choices with the edited 'work' selected, got %d with '%s' selected", len(candidates), candidates[selected].Name)
+	}
+
+	e.Content = "me"
+	candidates, selected = e.Candidates()
+	if selected != 0 || candidates[0].Name != "meeting" {
+		t.Fatalf("expected best match 'meeting' selected for 'me', got '%s' (of %v)", candidates[selected].Name, candidates)
+	}
+	if last := candidates[len(candidates)-1]; last.Name != "me" || !last.IsUnknown() {
+		t.Fatalf("expected new choice 'me' last, got '%s'", last.Name)
+	}
+
+	e.SelectNext()
+	candidates, selected = e.Candidates()
+	if candidates[selected].Name != "client-acme" {
+		t.Fatalf("expected 'client-acme' selected after selecting next, got '%s'", candidates[selected].Name)
+	}
+}
+
+func TestChoiceEditorWrite(t *testing.T) {
+	{
+		testcase := "known category"
+		event := model.NewEvent("09:00|12:00|work|Coding", nil)
+		c, e := constructCategoryChoiceEditor(t, event)
+		e.Content = "acme"
+		c.Write()
+		if c.GetError() != nil {
+			t.Fatalf("test case '%s' failed with error: %s", testcase, c.GetError().Error())
+		}
+		if event.Cat.Name != "client-acme" || event.Cat.Priority != 3 {
+			t.Fatalf("test case '%s' failed: category is %v", testcase, event.Cat)
+		}
+	}
+	for _, testcase := range []string{"unknown category", "deprecated category"} {
+		event := model.NewEvent("09:00|12:00|work|Coding", nil)
+		c, e := constructCategoryChoiceEditor(t, event)
+		expected := "wrk"
+		if testcase == "deprecated category" {
+			expected = "old-project"
+		}
+		e.Content = expected
+		if testcase == "unknown category" {
+			e.SelectNext() // past the match 'work', to the new 'wrk'
+		}
+		c.Write()
+		if c.GetError() == nil || event.Cat.Name != "work" {
+			t.Fatalf("test case '%s' failed: expected error and unchanged category, got %v", testcase, event.Cat)
+		}
+		c.Write()
+		if c.GetError() != nil {
+			t.Fatalf("test case '%s' failed: expected confirmation, got error: %s", testcase, c.GetError().Error())
+		}
+		if event.Cat.Name != expected {
+			t.Fatalf("test case '%s' failed: category is %v", testcase, event.Cat)
+		}
+	}
+	{
+		testcase := "unchanged unknown category"
+		event := model.NewEvent("09:00|12:00|hobby|Reading", nil)
+		c, _ := constructCategoryChoiceEditor(t, event)
+		c.Write()
+		if c.GetError() != nil {
+			t.Fatalf("test case '%s' failed with error: %s", testcase, c.GetError().Error())
+		}
+		if event.Cat.Name != "hobby" {
+			t.Fatalf("test case '%s' failed: category is %v", testcase, event.Cat)
+		}
+	}
+}
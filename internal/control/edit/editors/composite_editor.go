@@ -26,10 +26,20 @@ type Composite struct {
 
 	parent *Composite
 
+	// obj is the edited struct, which is restored when a write fails
+	obj reflect.Value
+	err error
+
 	id           EditorID
 	quitCallback func()
 }
 
+// A Validator can validate itself, e.g. an edited struct whose fields are
+// valid individually but not in combination.
+type Validator interface {
+	Validate() error
+}
+
 func (e *Composite) getCurrentFieldIndex() int {
 	for i, id := range e.fieldOrder {
 		if id == e.activeFieldID {
@@ -73,6 +83,9 @@ func (e *Composite) EnterField() {
 }
 
 // ConstructEditor constructs a new editor...
+//
+// The extra spec may contain Choices under the ID of a field, for the field to
+// be edited by a ChoiceEditor, choosing from the known values.
 func ConstructEditor(id string, obj any, extraSpec map[string]any, parentEditor *Composite) (edit.Editor, error) {
 	structPtr := reflect.ValueOf(obj)
 
@@ -94,6 +107,7 @@ func ConstructEditor(id string, obj any, extraSpec map[string]any, parentEditor
 		fieldOrder:    nil,             // NOTE: this must be done in the following
 		id:            id,
 		parent:        parentEditor,
+		obj:           structValue,
 	}
 
 	// go through all tags
@@ -129,9 +143,19 @@ func ConstructEditor(id string, obj any, extraSpec map[string]any, parentEditor
 				}
 				constructedCompositeEditor.fieldOrder = append(constructedCompositeEditor.fieldOrder, editspec.ID)
 
-				switch field.Type.Kind() {
-				case reflect.String:
-					f := structValue.Field(i)
+				f := structValue.Field(i)
+				choices, hasChoices := extraSpec[editspec.ID].(Choices)
+				valueEditor, isValue := newValueEditor(editspec.ID, f, constructedCompositeEditor)
+				switch {
+				case hasChoices:
+					choiceEditor, err := newChoiceEditor(editspec.ID, f, choices, constructedCompositeEditor)
+					if err != nil {
+						return nil, fmt.Errorf("unable to construct choice editor for field '%s' (tagged '%s') (%s)", field.Name, editspec.ID, err.Error())
+					}
+					constructedCompositeEditor.fields[editspec.ID] = choiceEditor
+				case isValue:
+					constructedCompositeEditor.fields[editspec.ID] = valueEditor
+				case field.Type.Kind() == reflect.String:
 					constructedCompositeEditor.fields[editspec.ID] = &StringEditor{
 						ID:        editspec.ID,
 						Content:   f.String(),
@@ -145,13 +169,12 @@ func ConstructEditor(id string, obj any, extraSpec map[string]any, parentEditor
 						CommitFn: func(v string) { f.SetString(v) },
 						parent:   constructedCompositeEditor,
 					}
-				case reflect.Struct:
+				case field.Type.Kind() == reflect.Struct:
 
 					if editspec.Ignore {
 						log.Debug().Msgf("ignoring struct '%s' tagged '%s' (ignore:%t)", field.Name, editspec.ID, editspec.Ignore)
 					} else {
 						// construct the sub-editor for the struct
-						f := structValue.Field(i)
 						var fAsPtr any
 						if f.Kind() == reflect.Ptr {
 							fAsPtr = f.Interface()
@@ -168,9 +191,6 @@ func ConstructEditor(id string, obj any, extraSpec map[string]any, parentEditor
 						constructedCompositeEditor.fields[editspec.ID] = sube
 					}
 
-				case reflect.Ptr:
-					// TODO
-					log.Warn().Msgf("ignoring PTR    '%s' tagged '%s' (ignore:%t) of type '%s'", field.Name, editspec.ID, editspec.Ignore, field.Type.String())
 				default:
 					return nil, fmt.Errorf("unable to edit non-ignored field '%s' (tagged '%s') of type '%s'", field.Name, editspec.ID, field.Type.Kind())
 				}
@@ -246,12 +266,36 @@ func (e *Composite) GetFieldOrder() []EditorID { return e.fieldOrder }
 
 // Write writes the content of the editor back to the underlying data structure
 // by calling the write functions of all subeditors.
+// If any subeditor fails to write or the written struct is not valid (see
+// Validator), the struct is restored and the error is kept (see GetError).
 func (e *Composite) Write() {
-	for _, subeditor := range e.fields {
+	previous := reflect.New(e.obj.Type()).Elem()
+	previous.Set(e.obj)
+
+	e.err = nil
+	for _, id := range e.fieldOrder {
+		subeditor, ok := e.fields[id]
+		if !ok {
+			continue
+		}
 		subeditor.Write()
+		if erring, ok := subeditor.(interface{ GetError() error }); ok && erring.GetError() != nil && e.err == nil {
+			e.err = fmt.Errorf("invalid %s (%w)", id, erring.GetError())
+		}
+	}
+	if validator, ok := e.obj.Addr().Interface().(Validator); ok && e.err == nil {
+		e.err = validator.Validate()
+	}
+
+	if e.err != nil {
+		log.Debug().Err(e.err).Msgf("restoring '%s' after failed write", e.id)
+		e.obj.Set(previous)
 	}
 }
 
+// GetError returns the error of the last write, if any.
+func (e *Composite) GetError() error { return e.err }
+
 // AddQuitCallback adds a callback that is called when the editor is quit.
 func (e *Composite) AddQuitCallback(f func()) {
 	if e.quitCallback != nil {
@@ -284,17 +328,16 @@ func (e *Composite) CreateInputProcessor(cfg input.InputConfig) (input.ModalInpu
 		"prev-field":      e.SwitchToPrevField,
 		"enter-subeditor": e.EnterField,
 		"write":           e.Write,
-		"write-and-quit":  func() { e.Write(); e.Quit() },
-		"quit":            e.Quit,
+		"write-and-quit": func() {
+			e.Write()
+			if e.err == nil {
+				e.Quit()
+			}
+		},
+		"quit": e.Quit,
 	}
 
-	mappings := map[input.Keyspec]action.Action{}
-	for keyspec, actionspec := range cfg.Editor {
-		log.Debug().Msgf("adding mapping '%s' -> '%s'", keyspec, actionspec)
-		actionspecCopy := actionspec
-		mappings[keyspec] = action.NewSimple(func() string { return string(actionspecCopy) }, actionspecToFunc[actionspecCopy])
-	}
-	inputTree, err := input.ConstructInputTree(mappings)
+	inputTree, err := input.ConstructInputTreeFromKeymap(cfg.Editor, namedActions(actionspecToFunc))
 	if err != nil {
 		return nil, fmt.Errorf("could not construct normal mode input tree: %w", err)
 	}
@@ -312,3 +355,36 @@ func (e *Composite) IsInField() bool { return e.inField }
 func (e *Composite) GetFields() map[EditorID]edit.Editor {
 	return e.fields
 }
+
+// namedActions returns simple actions for the given functions, each explained
+// by its action ID.
+func namedActions(funcs map[input.Actionspec]func()) map[input.Actionspec]action.Action {
+	result := make(map[input.Actionspec]action.Action, len(funcs))
+	for actionspec, f := range funcs {
+		actionspecCopy := actionspec
+		result[actionspec] = action.NewSimple(func() string { return string(actionspecCopy) }, f)
+	}
+	return result
+}
+
+// ValidateInputConfig returns an error if the given input config is not valid
+// for the editors, e.g. because it maps to actions they do not have, so that
+// this is noticed before an editor is opened.
+func ValidateInputConfig(cfg input.InputConfig) error {
+	for _, e := range []struct {
+		name   string
+		editor interface {
+			CreateInputProcessor(input.InputConfig) (input.ModalInputProcessor, error)
+		}
+	}{
+		{"editor", &Composite{}},
+		{"string-editor", &StringEditor{}},
+		{"value-editor", &ValueEditor{}},
+		{"choice-editor", &ChoiceEditor{}},
+	} {
+		if _, err := e.editor.CreateInputProcessor(cfg); err != nil {
+			return fmt.Errorf("invalid keybindings for '%s' (%w)", e.name, err)
+		}
+	}
+	return nil
+}
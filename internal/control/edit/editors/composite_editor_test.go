@@ -0,0 +1,165 @@
+package editors_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/control/edit/editors"
+	"github.com/ja-he/dayplan/internal/input"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func constructEventEditor(t *testing.T, event *model.Event) *editors.Composite {
+	e, err := editors.ConstructEditor("event", event, nil, nil)
+	if err != nil {
+		t.Fatalf("could not construct editor: %s", err.Error())
+	}
+	return e.(*editors.Composite)
+}
+
+func TestCompositeWriteTimestamps(t *testing.T) {
+	{
+		testcase := "valid times"
+		event := model.NewEvent("09:00|12:00|work|Coding", nil)
+		e := constructEventEditor(t, event)
+		e.GetFields()["start"].(*editors.ValueEditor).Content = "10:15"
+		e.GetFields()["end"].(*editors.ValueEditor).Content = "12:30"
+		e.Write()
+		if e.GetError() != nil {
+			t.Fatalf("test case '%s' failed with error: %s", testcase, e.GetError().Error())
+		}
+		if (event.Start != model.Timestamp{Hour: 10, Minute: 15} || event.End != model.Timestamp{Hour: 12, Minute: 30}) {
+			t.Fatalf("test case '%s' failed: event is %s-%s", testcase, event.Start.ToString(), event.End.ToString())
+		}
+	}
+	{
+		testcase := "inverted event"
+		event := model.NewEvent("09:00|12:00|work|Coding", nil)
+		e := constructEventEditor(t, event)
+		e.GetFields()["name"].(*editors.StringEditor).Content = "Renamed"
+		e.GetFields()["start"].(*editors.ValueEditor).Content = "13:00"
+		e.Write()
+		if e.GetError() == nil {
+			t.Fatalf("test case '%s' failed: expected error", testcase)
+		}
+		if (event.Name != "Coding" || event.Start != model.Timestamp{Hour: 9, Minute: 0}) {
+			t.Fatalf("test case '%s' failed: event was modified to '%s' %s-%s", testcase, event.Name, event.Start.ToString(), event.End.ToString())
+		}
+	}
+	{
+		testcase := "malformed time"
+		event := model.NewEvent("09:00|12:00|work|Coding", nil)
+		e := constructEventEditor(t, event)
+		end := e.GetFields()["end"].(*editors.ValueEditor)
+		end.Content = "25:00"
+		e.Write()
+		if e.GetError() == nil || end.GetError() == nil {
+			t.Fatalf("test case '%s' failed: expected errors", testcase)
+		}
+		if (event.End != model.Timestamp{Hour: 12, Minute: 0}) {
+			t.Fatalf("test case '%s' failed: end was modified to %s", testcase, event.End.ToString())
+		}
+	}
+}
+
+func TestCompositeWriteOptionalValues(t *testing.T) {
+	construct := func(task *model.Task) *editors.Composite {
+		e, err := editors.ConstructEditor("task", task, nil, nil)
+		if err != nil {
+			t.Fatalf("could not construct editor: %s", err.Error())
+		}
+		return e.(*editors.Composite)
+	}
+	{
+		testcase := "set unset values"
+		task := &model.Task{Name: "Ship release"}
+		e := construct(task)
+		duration := e.GetFields()["duration"].(*editors.ValueEditor)
+		if duration.Content != "" {
+			t.Fatalf("test case '%s' failed: expected empty contents for unset duration, got '%s'", testcase, duration.Content)
+		}
+		duration.Content = "1h30m"
+		e.GetFields()["deadline"].(*editors.ValueEditor).Content = "2026-10-20 17:00"
+		e.Write()
+		if e.GetError() != nil {
+			t.Fatalf("test case '%s' failed with error: %s", testcase, e.GetError().Error())
+		}
+		if task.Duration == nil || *task.Duration != 90*time.Minute {
+			t.Fatalf("test case '%s' failed: duration is %v", testcase, task.Duration)
+		}
+		if task.Deadline == nil || !task.Deadline.Equal(time.Date(2026, 10, 20, 17, 0, 0, 0, time.Local)) {
+			t.Fatalf("test case '%s' failed: deadline is %v", testcase, task.Deadline)
+		}
+	}
+	{
+		testcase := "clear set value"
+		duration := time.Hour
+		task := &model.Task{Name: "Ship release", Duration: &duration}
+		e := construct(task)
+		durationEditor := e.GetFields()["duration"].(*editors.ValueEditor)
+		if durationEditor.Content != "1h" {
+			t.Fatalf("test case '%s' failed: expected contents '1h', got '%s'", testcase, durationEditor.Content)
+		}
+		durationEditor.Clear()
+		e.Write()
+		if e.GetError() != nil {
+			t.Fatalf("test case '%s' failed with error: %s", testcase, e.GetError().Error())
+		}
+		if task.Duration != nil {
+			t.Fatalf("test case '%s' failed: duration is still %s", testcase, task.Duration.String())
+		}
+	}
+	{
+		testcase := "integer"
+		task := &model.Task{Name: "Ship release", Category: model.Category{Name: "work", Priority: 2}}
+		e := construct(task)
+		priority := e.GetFields()["category"].(*editors.Composite).GetFields()["priority"].(*editors.ValueEditor)
+		if priority.Content != "2" {
+			t.Fatalf("test case '%s' failed: expected contents '2', got '%s'", testcase, priority.Content)
+		}
+		priority.Increment()
+		e.Write()
+		if e.GetError() != nil {
+			t.Fatalf("test case '%s' failed with error: %s", testcase, e.GetError().Error())
+		}
+		if task.Category.Priority != 3 {
+			t.Fatalf("test case '%s' failed: priority is %d", testcase, task.Category.Priority)
+		}
+	}
+	{
+		testcase := "invalid integer"
+		task := &model.Task{Name: "Ship release", Category: model.Category{Name: "work", Priority: 2}}
+		e := construct(task)
+		priority := e.GetFields()["category"].(*editors.Composite).GetFields()["priority"].(*editors.ValueEditor)
+		priority.Content = "high"
+		e.Write()
+		if e.GetError() == nil || priority.GetError() == nil {
+			t.Fatalf("test case '%s' failed: expected errors", testcase)
+		}
+		if task.Category.Priority != 2 {
+			t.Fatalf("test case '%s' failed: priority was modified to %d", testcase, task.Category.Priority)
+		}
+	}
+}
+
+func TestValidateInputConfig(t *testing.T) {
+	cfg := input.InputConfig{
+		Editor:       input.Keymap{"j": "next-field", "<CR>": "write-and-quit"},
+		StringEditor: input.ModedSpec{Normal: input.Keymap{"x": "delete-rune"}, Insert: input.Keymap{"<bs>": "backspace"}},
+		ValueEditor:  input.Keymap{"+": "increment"},
+		ChoiceEditor: input.ModedSpec{Normal: input.Keymap{"j": "select-next"}, Insert: input.Keymap{"<c-n>": "select-next"}},
+	}
+	if err := editors.ValidateInputConfig(cfg); err != nil {
+		t.Fatalf("expected valid config, got error: %s", err.Error())
+	}
+
+	cfg.Editor = input.Keymap{"x": "wirte"}
+	if err := editors.ValidateInputConfig(cfg); err == nil {
+		t.Fatalf("expected error for unknown editor action")
+	}
+	cfg.Editor = input.Keymap{"j": "next-field"}
+	cfg.ValueEditor = input.Keymap{"+": "select-next"}
+	if err := editors.ValidateInputConfig(cfg); err == nil {
+		t.Fatalf("expected error for choice editor action in value editor")
+	}
+}
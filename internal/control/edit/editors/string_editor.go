@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strconv"
 
-	"github.com/ja-he/dayplan/internal/control/action"
 	"github.com/ja-he/dayplan/internal/control/edit"
 	"github.com/ja-he/dayplan/internal/input"
 	"github.com/ja-he/dayplan/internal/input/processors"
@@ -271,8 +270,18 @@ func (e *StringEditor) AddQuitCallback(f func()) {
 	}
 }
 
+// GetError returns nil, as any string is valid.
+func (e *StringEditor) GetError() error { return nil }
+
 // CreateInputProcessor creates an input processor for the editor.
 func (e *StringEditor) CreateInputProcessor(cfg input.InputConfig) (input.ModalInputProcessor, error) {
+	return createTextInputProcessor(e, cfg.StringEditor.Normal, cfg.StringEditor.Insert, nil)
+}
+
+// createTextInputProcessor creates an input processor for editing the given
+// string editor's contents with the given keymaps, which, in normal mode, may
+// also map to the given extra actions.
+func createTextInputProcessor(e *StringEditor, normal, insert input.Keymap, extraActions map[input.Actionspec]func()) (input.ModalInputProcessor, error) {
 
 	var enterInsertMode func()
 	var exitInsertMode func()
@@ -300,21 +309,19 @@ func (e *StringEditor) CreateInputProcessor(cfg input.InputConfig) (input.ModalI
 		"swap-mode-insert-append":            func() { enterInsertMode(); e.MoveCursorRight() },
 		"swap-mode-normal":                   func() { exitInsertMode(); e.MoveCursorLeft() },
 	}
-
-	normalModeMappings := map[input.Keyspec]action.Action{}
-	for keyspec, actionspec := range cfg.StringEditor.Normal {
-		actionspecCopy := actionspec
-		normalModeMappings[keyspec] = action.NewSimple(func() string { return string(actionspecCopy) }, actionspecToFunc[actionspecCopy])
+	for actionspec, f := range extraActions {
+		actionspecToFunc[actionspec] = f
 	}
-	normalModeInputTree, err := input.ConstructInputTree(normalModeMappings)
+
+	actions := namedActions(actionspecToFunc)
+	normalModeInputTree, err := input.ConstructInputTreeFromKeymap(normal, actions)
 	if err != nil {
 		return nil, fmt.Errorf("could not construct normal mode input tree: %w", err)
 	}
 
-	insertModeMappings := map[input.Keyspec]action.Action{}
-	for keyspec, actionspec := range cfg.StringEditor.Insert {
-		actionspecCopy := actionspec
-		insertModeMappings[keyspec] = action.NewSimple(func() string { return string(actionspecCopy) }, actionspecToFunc[actionspecCopy])
+	insertModeMappings, err := input.ResolveKeymap(insert, actions)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve insert mode mappings: %w", err)
 	}
 	insertModeInputTree, err := processors.NewTextInputProcessor(insertModeMappings, e.AddRune)
 	if err != nil {
@@ -0,0 +1,373 @@
+package editors
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/control/edit"
+	"github.com/ja-he/dayplan/internal/input"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// TextEditor is an editor whose value is edited as text, like a StringEditor.
+type TextEditor interface {
+	edit.Editor
+
+	GetContent() string
+	GetCursorPos() int
+	GetMode() input.TextEditMode
+
+	// GetError returns the error with the contents at the last write, if any.
+	GetError() error
+
+	CreateInputProcessor(input.InputConfig) (input.ModalInputProcessor, error)
+}
+
+// ValueEditor edits a value (such as a number, a duration or a timestamp) as
+// text, which is parsed on writes.
+// Its contents can also be incremented and decremented by a step appropriate
+// for the value, which may depend on the cursor position (e.g. for a
+// timestamp, by an hour when on the hours and by MinuteStep minutes
+// otherwise).
+//
+// If the value is optional (i.e., the edited field is a pointer), empty
+// contents stand for nil.
+type ValueEditor struct {
+	StringEditor
+
+	kind     valueKind
+	optional bool
+	typ      reflect.Type
+
+	// CommitValueFn is called with the value (of the edited field's type) on
+	// writes of valid contents.
+	CommitValueFn func(reflect.Value)
+
+	err error
+}
+
+// MinuteStep is the number of minutes by which the minutes of values like
+// timestamps are incremented or decremented.
+const MinuteStep = 5
+
+// valueKind defines how values of a type are edited.
+type valueKind struct {
+	name   string
+	format func(v reflect.Value) string
+	parse  func(s string) (reflect.Value, error)
+	// step returns the value changed by n steps, with the cursor at the given
+	// position of its formatted string, or false, if it can't be changed.
+	step func(v reflect.Value, cursorPos int, n int) (reflect.Value, bool)
+}
+
+// newValueEditor returns a value editor for the given field, if its type (or,
+// for pointers, the type pointed to) is one of those edited by value editors.
+func newValueEditor(id string, field reflect.Value, parent *Composite) (*ValueEditor, bool) {
+	typ := field.Type()
+	optional := typ.Kind() == reflect.Ptr
+	valueType := typ
+	if optional {
+		valueType = typ.Elem()
+	}
+	kind, ok := valueKindFor(valueType)
+	if !ok {
+		return nil, false
+	}
+
+	content := ""
+	switch {
+	case !optional:
+		content = kind.format(field)
+	case !field.IsNil():
+		content = kind.format(field.Elem())
+	}
+
+	e := &ValueEditor{
+		StringEditor: StringEditor{
+			ID:      id,
+			Content: content,
+			Mode:    input.TextEditModeNormal,
+			parent:  parent,
+		},
+		kind:          kind,
+		optional:      optional,
+		typ:           typ,
+		CommitValueFn: field.Set,
+	}
+	e.QuitCallback = func() {
+		if parent != nil && parent.activeFieldID == id {
+			parent.inField = false
+		}
+	}
+	return e, true
+}
+
+// GetType informs on the type of the value edited, e.g. "duration".
+func (e *ValueEditor) GetType() string {
+	if e.optional {
+		return "optional " + e.kind.name
+	}
+	return e.kind.name
+}
+
+// GetError returns the error parsing the contents at the last write, if any.
+func (e *ValueEditor) GetError() error { return e.err }
+
+// IsOptional informs on whether the value is optional, i.e., may be cleared.
+func (e *ValueEditor) IsOptional() bool { return e.optional }
+
+// parseContent parses the contents to a value of the edited field's type.
+func (e *ValueEditor) parseContent() (reflect.Value, error) {
+	if e.optional && strings.TrimSpace(e.Content) == "" {
+		return reflect.Zero(e.typ), nil
+	}
+	v, err := e.kind.parse(strings.TrimSpace(e.Content))
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if e.optional {
+		ptr := reflect.New(e.typ.Elem())
+		ptr.Elem().Set(v)
+		return ptr, nil
+	}
+	return v, nil
+}
+
+// Increment increments the value (setting an unset optional value to the
+// zero value, first).
+func (e *ValueEditor) Increment() { e.step(+1) }
+
+// Decrement decrements the value (setting an unset optional value to the
+// zero value, first).
+func (e *ValueEditor) Decrement() { e.step(-1) }
+
+// step changes the value by the given number of steps; invalid contents are
+// left unchanged.
+func (e *ValueEditor) step(n int) {
+	var v reflect.Value
+	if e.optional && strings.TrimSpace(e.Content) == "" {
+		v = reflect.Zero(e.typ.Elem())
+	} else {
+		var err error
+		v, err = e.kind.parse(strings.TrimSpace(e.Content))
+		if err != nil {
+			e.err = err
+			return
+		}
+	}
+	if stepped, ok := e.kind.step(v, e.CursorPos, n); ok {
+		e.Content = e.kind.format(stepped)
+		e.err = nil
+	}
+	if e.CursorPos > len([]rune(e.Content)) {
+		e.MoveCursorPastEnd()
+	}
+}
+
+// Clear clears the value, if it is optional.
+func (e *ValueEditor) Clear() {
+	if e.optional {
+		e.StringEditor.Clear()
+		e.err = nil
+	}
+}
+
+// Write commits the value, if the contents are valid.
+func (e *ValueEditor) Write() {
+	v, err := e.parseContent()
+	e.err = err
+	if err != nil {
+		return
+	}
+	e.CommitValueFn(v)
+}
+
+// CreateInputProcessor creates an input processor for the editor.
+func (e *ValueEditor) CreateInputProcessor(cfg input.InputConfig) (input.ModalInputProcessor, error) {
+	return createTextInputProcessor(
+		&e.StringEditor,
+		cfg.StringEditor.Normal.WithOverrides(cfg.ValueEditor),
+		cfg.StringEditor.Insert,
+		map[input.Actionspec]func(){
+			"write":     e.Write,
+			"increment": e.Increment,
+			"decrement": e.Decrement,
+			"clear":     e.Clear,
+		},
+	)
+}
+
+func valueKindFor(t reflect.Type) (valueKind, bool) {
+	switch {
+	case t == reflect.TypeOf(model.Timestamp{}):
+		return timestampKind, true
+	case t == reflect.TypeOf(model.Date{}):
+		return dateKind, true
+	case t == reflect.TypeOf(time.Duration(0)):
+		return durationKind, true
+	case t == reflect.TypeOf(time.Time{}):
+		return timeKind, true
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intKind(t), true
+	case reflect.Float32, reflect.Float64:
+		return floatKind(t), true
+	case reflect.Bool:
+		return boolKind, true
+	}
+	return valueKind{}, false
+}
+
+// offsetMinutesWithinDay offsets the given minutes of the day by the given
+// number of minutes, or returns false if that leaves the day.
+func offsetMinutesWithinDay(minutes, delta int) (int, bool) {
+	result := minutes + delta
+	return result, result >= 0 && result < 24*60
+}
+
+var timestampKind = valueKind{
+	name:   "timestamp",
+	format: func(v reflect.Value) string { return v.Interface().(model.Timestamp).ToString() },
+	parse: func(s string) (reflect.Value, error) {
+		t, err := model.TimestampFromString(s)
+		return reflect.ValueOf(t), err
+	},
+	step: func(v reflect.Value, cursorPos int, n int) (reflect.Value, bool) {
+		t := v.Interface().(model.Timestamp)
+		delta := n * MinuteStep
+		if cursorPos < len("HH") {
+			delta = n * 60
+		}
+		minutes, ok := offsetMinutesWithinDay(t.Hour*60+t.Minute, delta)
+		return reflect.ValueOf(model.Timestamp{Hour: minutes / 60, Minute: minutes % 60}), ok
+	},
+}
+
+var dateKind = valueKind{
+	name:   "date",
+	format: func(v reflect.Value) string { return v.Interface().(model.Date).ToString() },
+	parse: func(s string) (reflect.Value, error) {
+		d, err := model.FromString(s)
+		return reflect.ValueOf(d), err
+	},
+	step: func(v reflect.Value, _ int, n int) (reflect.Value, bool) {
+		d := v.Interface().(model.Date)
+		if d == (model.Date{}) {
+			d = model.FromTime(time.Now()).Date
+		}
+		return reflect.ValueOf(d.Forward(n)), true
+	},
+}
+
+var durationKind = valueKind{
+	name: "duration",
+	format: func(v reflect.Value) string {
+		// e.g. "1h30m" or "1h" rather than "1h30m0s" or "1h0m0s"
+		s := v.Interface().(time.Duration).String()
+		if strings.HasSuffix(s, "m0s") {
+			s = strings.TrimSuffix(s, "0s")
+		}
+		if strings.HasSuffix(s, "h0m") {
+			s = strings.TrimSuffix(s, "0m")
+		}
+		return s
+	},
+	parse: func(s string) (reflect.Value, error) {
+		d, err := time.ParseDuration(s)
+		return reflect.ValueOf(d), err
+	},
+	step: func(v reflect.Value, _ int, n int) (reflect.Value, bool) {
+		return reflect.ValueOf(v.Interface().(time.Duration) + time.Duration(n*MinuteStep)*time.Minute), true
+	},
+}
+
+// timeLayout is the layout in which times (with dates) are edited.
+const timeLayout = "2006-01-02 15:04"
+
+var timeKind = valueKind{
+	name:   "time",
+	format: func(v reflect.Value) string { return v.Interface().(time.Time).Format(timeLayout) },
+	parse: func(s string) (reflect.Value, error) {
+		t, err := time.ParseInLocation(timeLayout, s, time.Local)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("'%s' does not fit the format YYYY-MM-DD HH:MM", s)
+		}
+		return reflect.ValueOf(t), nil
+	},
+	step: func(v reflect.Value, cursorPos int, n int) (reflect.Value, bool) {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			t = time.Now().Truncate(time.Hour)
+		}
+		switch {
+		case cursorPos < len("YYYY-MM-DD"):
+			return reflect.ValueOf(t.AddDate(0, 0, n)), true
+		case cursorPos < len("YYYY-MM-DD HH"):
+			return reflect.ValueOf(t.Add(time.Duration(n) * time.Hour)), true
+		default:
+			return reflect.ValueOf(t.Add(time.Duration(n*MinuteStep) * time.Minute)), true
+		}
+	},
+}
+
+func intKind(t reflect.Type) valueKind {
+	return valueKind{
+		name:   "integer",
+		format: func(v reflect.Value) string { return strconv.FormatInt(v.Int(), 10) },
+		parse: func(s string) (reflect.Value, error) {
+			i, err := strconv.ParseInt(s, 10, t.Bits())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("'%s' is not an integer", s)
+			}
+			return reflect.ValueOf(i).Convert(t), nil
+		},
+		step: func(v reflect.Value, _ int, n int) (reflect.Value, bool) {
+			i := v.Int() + int64(n)
+			if v.OverflowInt(i) {
+				return v, false
+			}
+			result := reflect.New(t).Elem()
+			result.SetInt(i)
+			return result, true
+		},
+	}
+}
+
+func floatKind(t reflect.Type) valueKind {
+	return valueKind{
+		name:   "number",
+		format: func(v reflect.Value) string { return strconv.FormatFloat(v.Float(), 'f', -1, t.Bits()) },
+		parse: func(s string) (reflect.Value, error) {
+			f, err := strconv.ParseFloat(s, t.Bits())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("'%s' is not a number", s)
+			}
+			return reflect.ValueOf(f).Convert(t), nil
+		},
+		step: func(v reflect.Value, _ int, n int) (reflect.Value, bool) {
+			return reflect.ValueOf(v.Float() + float64(n)).Convert(t), true
+		},
+	}
+}
+
+var boolKind = valueKind{
+	name:   "boolean",
+	format: func(v reflect.Value) string { return strconv.FormatBool(v.Bool()) },
+	parse: func(s string) (reflect.Value, error) {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("'%s' is neither true nor false", s)
+		}
+		return reflect.ValueOf(b), nil
+	},
+	step: func(v reflect.Value, _ int, n int) (reflect.Value, bool) {
+		if n%2 == 0 {
+			return v, true
+		}
+		return reflect.ValueOf(!v.Bool()), true
+	},
+}
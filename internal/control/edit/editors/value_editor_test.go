@@ -0,0 +1,37 @@
+package editors_test
+
+import (
+	"testing"
+
+	"github.com/ja-he/dayplan/internal/control/edit/editors"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func TestValueEditorStepTimestamp(t *testing.T) {
+	for _, tc := range []struct {
+		content   string
+		cursorPos int
+		n         int
+		expected  string
+	}{
+		{"09:00", 0, +1, "10:00"},
+		{"09:00", 1, -1, "08:00"},
+		{"09:00", 3, +1, "09:05"},
+		{"09:00", 4, -1, "08:55"},
+		{"23:30", 0, +1, "23:30"},
+		{"00:00", 4, -1, "00:00"},
+		{"9:00", 0, +1, "9:00"},
+	} {
+		e := constructEventEditor(t, model.NewEvent("09:00|12:00|work|Coding", nil)).GetFields()["start"].(*editors.ValueEditor)
+		e.Content, e.CursorPos = tc.content, tc.cursorPos
+		for i := 0; i < tc.n; i++ {
+			e.Increment()
+		}
+		for i := 0; i > tc.n; i-- {
+			e.Decrement()
+		}
+		if e.Content != tc.expected {
+			t.Errorf("expected stepping '%s' (cursor at %d) by %d to give '%s', got '%s'", tc.content, tc.cursorPos, tc.n, tc.expected, e.Content)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package control
+
+import "github.com/ja-he/dayplan/internal/model"
+
+// JumpList is the list of dates jumped away from (e.g. by going to a date or
+// by a week), which can be gone back and forth through, similar to Vim's
+// jumplist.
+type JumpList struct {
+	// Entries are the dates jumped from, oldest first.
+	Entries []model.Date
+	// Index is the index of the entry the current date was gone to from the
+	// jumplist, or the number of entries while not going through the jumplist.
+	Index int
+	// Max is the maximum number of entries kept (0 meaning no limit).
+	Max int
+}
+
+// Jump records a jump away from the given date, dropping any entries that
+// were gone back from.
+func (j *JumpList) Jump(from model.Date) {
+	j.Entries = j.Entries[:j.Index]
+	if len(j.Entries) == 0 || j.Entries[len(j.Entries)-1] != from {
+		j.Entries = append(j.Entries, from)
+	}
+	if j.Max > 0 && len(j.Entries) > j.Max {
+		j.Entries = j.Entries[len(j.Entries)-j.Max:]
+	}
+	j.Index = len(j.Entries)
+}
+
+// Back returns the date to go back to from the given current date, if any.
+// Going back from the newest entry, the current date is recorded, so that it
+// can be gone forward to again.
+func (j *JumpList) Back(current model.Date) (model.Date, bool) {
+	if j.Index == len(j.Entries) {
+		j.Jump(current)
+		j.Index--
+	}
+	for j.Index > 0 {
+		j.Index--
+		if j.Entries[j.Index] != current {
+			return j.Entries[j.Index], true
+		}
+	}
+	return model.Date{}, false
+}
+
+// Forward returns the date to go forward to again, if any.
+func (j *JumpList) Forward() (model.Date, bool) {
+	if j.Index+1 >= len(j.Entries) {
+		return model.Date{}, false
+	}
+	j.Index++
+	return j.Entries[j.Index], true
+}
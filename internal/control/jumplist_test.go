@@ -0,0 +1,61 @@
+package control_test
+
+import (
+	"testing"
+
+	"github.com/ja-he/dayplan/internal/control"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func TestJumpList(t *testing.T) {
+	date := func(month int) model.Date { return model.Date{Year: 2026, Month: month, Day: 1} }
+	a, b, c, d := date(1), date(2), date(3), date(4)
+	j := control.JumpList{}
+
+	if _, ok := j.Back(a); ok {
+		t.Fatal("went back on empty jumplist")
+	}
+	j = control.JumpList{}
+	j.Jump(a)
+	j.Jump(b)
+	j.Jump(b)
+	if len(j.Entries) != 2 {
+		t.Fatalf("consecutive duplicate jump recorded: %v", j.Entries)
+	}
+
+	current := c
+	for _, expected := range []model.Date{b, a} {
+		if date, ok := j.Back(current); !ok || date != expected {
+			t.Fatalf("expected to go back to %s, got %s (%t)", expected.ToString(), date.ToString(), ok)
+		}
+		current = expected
+	}
+	if _, ok := j.Back(a); ok {
+		t.Fatal("went back past the oldest entry")
+	}
+	for _, expected := range []model.Date{b, c} {
+		if date, ok := j.Forward(); !ok || date != expected {
+			t.Fatalf("expected to go forward to %s, got %s (%t)", expected.ToString(), date.ToString(), ok)
+		}
+	}
+	if _, ok := j.Forward(); ok {
+		t.Fatal("went forward past the newest entry")
+	}
+
+	j.Back(c)
+	j.Jump(b)
+	if len(j.Entries) != 2 || j.Entries[1] != b {
+		t.Fatalf("entries gone back from not dropped on jump: %v", j.Entries)
+	}
+	if _, ok := j.Forward(); ok {
+		t.Fatal("went forward after jump")
+	}
+
+	j = control.JumpList{Max: 2}
+	for _, from := range []model.Date{a, b, c, d} {
+		j.Jump(from)
+	}
+	if len(j.Entries) != 2 || j.Entries[0] != c {
+		t.Fatalf("jumplist not limited to max: %v", j.Entries)
+	}
+}
@@ -0,0 +1,64 @@
+// Package export provides exporting of dayplan data to formats understood by
+// other programs, e.g., plain-text accounting tools like ledger or hledger.
+package export
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// AccountMapper maps categories to accounts.
+type AccountMapper struct {
+	rules          []accountRule
+	defaultAccount string
+}
+
+type accountRule struct {
+	categoryRegex *regexp.Regexp
+	account       string
+}
+
+// NewAccountMapperFromConfig constructs a new AccountMapper from config data.
+func NewAccountMapperFromConfig(cfg config.Export) (*AccountMapper, error) {
+	result := AccountMapper{defaultAccount: cfg.DefaultAccount}
+
+	for i, mapping := range cfg.Accounts {
+		regex, err := regexp.Compile(mapping.Category)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling category regex of account mapping no. %d: %w", i, err)
+		}
+		if mapping.Account == "" {
+			return nil, fmt.Errorf("account mapping no. %d has no account", i)
+		}
+		result.rules = append(result.rules, accountRule{
+			categoryRegex: regex,
+			account:       mapping.Account,
+		})
+	}
+
+	return &result, nil
+}
+
+// Account returns the account for the given category and whether the category
+// maps to any account at all.
+//
+// The first matching rule determines the account. If no rule matches, the
+// default account is used, if there is one.
+func (m *AccountMapper) Account(cat model.Category) (string, bool) {
+	for _, rule := range m.rules {
+		submatches := rule.categoryRegex.FindStringSubmatchIndex(cat.Name)
+		if submatches == nil {
+			continue
+		}
+		account := rule.categoryRegex.ExpandString(nil, rule.account, cat.Name, submatches)
+		return string(account), true
+	}
+
+	if m.defaultAccount != "" {
+		return m.defaultAccount, true
+	}
+	return "", false
+}
@@ -0,0 +1,126 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func TestAccount(t *testing.T) {
+	mapper, err := NewAccountMapperFromConfig(config.Export{
+		Accounts: []config.AccountMapping{
+			{Category: "^client-(.*)$", Account: "clients:$1"},
+			{Category: "^admin$", Account: "internal:admin"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error constructing mapper: %s", err.Error())
+	}
+
+	{
+		testcase := "submatch expansion"
+		account, ok := mapper.Account(model.Category{Name: "client-acme"})
+		if !ok || account != "clients:acme" {
+			t.Fatalf("testcase '%s' failed: got '%s' (%t)", testcase, account, ok)
+		}
+	}
+	{
+		testcase := "plain match"
+		account, ok := mapper.Account(model.Category{Name: "admin"})
+		if !ok || account != "internal:admin" {
+			t.Fatalf("testcase '%s' failed: got '%s' (%t)", testcase, account, ok)
+		}
+	}
+	{
+		testcase := "no match, no default"
+		account, ok := mapper.Account(model.Category{Name: "eating"})
+		if ok {
+			t.Fatalf("testcase '%s' failed: got '%s' (%t)", testcase, account, ok)
+		}
+	}
+	{
+		testcase := "no match, default"
+		withDefault, err := NewAccountMapperFromConfig(config.Export{DefaultAccount: "misc"})
+		if err != nil {
+			t.Fatalf("unexpected error constructing mapper: %s", err.Error())
+		}
+		account, ok := withDefault.Account(model.Category{Name: "eating"})
+		if !ok || account != "misc" {
+			t.Fatalf("testcase '%s' failed: got '%s' (%t)", testcase, account, ok)
+		}
+	}
+}
+
+func TestWriteTimeclockAndTimedot(t *testing.T) {
+	mapper, err := NewAccountMapperFromConfig(config.Export{
+		Accounts: []config.AccountMapping{
+			{Category: "^work$", Account: "work"},
+			{Category: "^meeting$", Account: "work:meetings"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error constructing mapper: %s", err.Error())
+	}
+
+	categories := []model.Category{
+		{Name: "work", Priority: 1},
+		{Name: "meeting", Priority: 2},
+		{Name: "eating", Priority: 3},
+	}
+	day := model.NewDayWithEvents([]*model.Event{
+		model.NewEvent("09:00|12:00|work|Coding", categories),
+		model.NewEvent("10:00|10:30|meeting|Standup", categories),
+		model.NewEvent("12:00|12:30|eating|Lunch", categories),
+	})
+	date := model.Date{Year: 2022, Month: 12, Day: 1}
+
+	{
+		testcase := "timeclock (flattened, unmapped skipped)"
+		expected := "" +
+			"i 2022-12-01 09:00:00 work  Coding\n" +
+			"o 2022-12-01 10:00:00\n" +
+			"i 2022-12-01 10:00:00 work:meetings  Standup\n" +
+			"o 2022-12-01 10:30:00\n" +
+			"i 2022-12-01 10:30:00 work  Coding\n" +
+			"o 2022-12-01 12:00:00\n"
+
+		var buf bytes.Buffer
+		err := WriteTimeclock(&buf, date, day, mapper)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if buf.String() != expected {
+			t.Fatalf("testcase '%s' failed:\n  expected:\n%s\n  got:\n%s", testcase, expected, buf.String())
+		}
+	}
+	{
+		testcase := "timedot (flattened, unmapped skipped)"
+		expected := "" +
+			"2022-12-01\n" +
+			"work           2.50\n" +
+			"work:meetings  0.50\n" +
+			"\n"
+
+		var buf bytes.Buffer
+		err := WriteTimedot(&buf, date, day, mapper)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if buf.String() != expected {
+			t.Fatalf("testcase '%s' failed:\n  expected:\n%s\n  got:\n%s", testcase, expected, buf.String())
+		}
+	}
+	{
+		testcase := "timedot (empty day)"
+		var buf bytes.Buffer
+		err := WriteTimedot(&buf, date, model.NewDay(), mapper)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if buf.Len() != 0 {
+			t.Fatalf("testcase '%s' failed, got output:\n%s", testcase, buf.String())
+		}
+	}
+}
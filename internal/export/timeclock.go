@@ -0,0 +1,98 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// WriteTimeclock writes the events of the given day as timeclock entries, i.e.
+// a clock-in line and a clock-out line per event, e.g.:
+//
+//	i 2022-12-01 08:45:00 clients:acme  Review
+//	o 2022-12-01 10:30:00
+//
+// Events are flattened before being written (the same as for summaries), so
+// that no time is counted twice. Events whose categories map to no account are
+// skipped.
+func WriteTimeclock(w io.Writer, date model.Date, day *model.Day, accounts *AccountMapper) error {
+	if day == nil {
+		return nil
+	}
+
+	flattened := day.Clone()
+	flattened.Flatten()
+
+	for _, event := range flattened.Events {
+		account, ok := accounts.Account(event.Cat)
+		if !ok {
+			continue
+		}
+
+		clockIn := fmt.Sprintf("i %s %s:00 %s", date.ToString(), event.Start.ToString(), account)
+		if event.Name != "" {
+			// two spaces separate account and description
+			clockIn += "  " + event.Name
+		}
+		_, err := fmt.Fprintf(w, "%s\no %s %s:00\n", clockIn, date.ToString(), event.End.ToString())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteTimedot writes the events of the given day in the timedot format, i.e.
+// a date line followed by the number of hours spent per account, e.g.:
+//
+//	2022-12-01
+//	clients:acme      6.25
+//	internal:admin    0.50
+//
+// Events are flattened before being summed up (the same as for summaries), so
+// that no time is counted twice. Events whose categories map to no account are
+// skipped, and if no event on the day maps to an account, nothing is written.
+func WriteTimedot(w io.Writer, date model.Date, day *model.Day, accounts *AccountMapper) error {
+	if day == nil {
+		return nil
+	}
+
+	byAccount := make(map[string]time.Duration)
+	for category, minutes := range day.SumUpByCategory() {
+		account, ok := accounts.Account(category)
+		if !ok {
+			continue
+		}
+		byAccount[account] += time.Duration(minutes) * time.Minute
+	}
+	if len(byAccount) == 0 {
+		return nil
+	}
+
+	accountNames := make([]string, 0, len(byAccount))
+	accountNameWidth := 0
+	for account := range byAccount {
+		accountNames = append(accountNames, account)
+		if len(account) > accountNameWidth {
+			accountNameWidth = len(account)
+		}
+	}
+	sort.Strings(accountNames)
+
+	_, err := fmt.Fprintln(w, date.ToString())
+	if err != nil {
+		return err
+	}
+	for _, account := range accountNames {
+		_, err := fmt.Fprintf(w, "%-*s  %.2f\n", accountNameWidth, account, byAccount[account].Hours())
+		if err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
@@ -0,0 +1,116 @@
+// Package ics provides minimal reading of iCalendar (RFC 5545) files, as
+// needed to import e.g. public holidays.
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// DayEvent is an event occupying a single, whole day.
+type DayEvent struct {
+	Date    model.Date
+	Summary string
+}
+
+// ReadDayEvents reads the VEVENTs in the given iCalendar data as whole-day
+// events. Events spanning multiple days (by their exclusive DTEND) are
+// expanded to one DayEvent per day; times of day are ignored.
+func ReadDayEvents(r io.Reader) ([]DayEvent, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]DayEvent, 0)
+	inEvent := false
+	var start, end *model.Date
+	var summary string
+	for i, line := range lines {
+		name, value, ok := splitContentLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent, start, end, summary = true, nil, nil, ""
+		case name == "END" && value == "VEVENT":
+			if !inEvent {
+				return nil, fmt.Errorf("line %d: END:VEVENT without BEGIN:VEVENT", i+1)
+			}
+			if start == nil {
+				return nil, fmt.Errorf("line %d: event '%s' has no DTSTART", i+1, summary)
+			}
+			result = append(result, DayEvent{Date: *start, Summary: summary})
+			if end != nil {
+				for date := start.Next(); date.IsBefore(*end); date = date.Next() {
+					result = append(result, DayEvent{Date: date, Summary: summary})
+				}
+			}
+			inEvent = false
+		case !inEvent:
+			continue
+		case name == "DTSTART" || name == "DTEND":
+			date, err := parseDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if name == "DTSTART" {
+				start = &date
+			} else {
+				end = &date
+			}
+		case name == "SUMMARY":
+			summary = unescapeText(value)
+		}
+	}
+	return result, nil
+}
+
+// unfoldLines reads the lines of the given data, joining folded lines (i.e.,
+// lines continued on the next line starting with whitespace).
+func unfoldLines(r io.Reader) ([]string, error) {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// splitContentLine splits a content line (e.g. "DTSTART;VALUE=DATE:20221226")
+// into its name (without parameters, e.g. "DTSTART") and value.
+func splitContentLine(line string) (name, value string, ok bool) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return "", "", false
+	}
+	name = line[:colon]
+	if semicolon := strings.Index(name, ";"); semicolon >= 0 {
+		name = name[:semicolon]
+	}
+	return strings.ToUpper(name), line[colon+1:], true
+}
+
+// parseDate parses the date part of a DATE or DATE-TIME value, e.g.
+// "20221226" or "20221226T090000Z".
+func parseDate(value string) (model.Date, error) {
+	if len(value) < 8 {
+		return model.Date{}, fmt.Errorf("malformed date '%s'", value)
+	}
+	return model.FromString(value[0:4] + "-" + value[4:6] + "-" + value[6:8])
+}
+
+// unescapeText unescapes a TEXT value.
+func unescapeText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,60 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func TestReadDayEvents(t *testing.T) {
+	{
+		testcase := "holidays with folding, escapes and multi-day event"
+		data := "BEGIN:VCALENDAR\r\n" +
+			"VERSION:2.0\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"DTSTART;VALUE=DATE:20221226\r\n" +
+			"DTEND;VALUE=DATE:20221227\r\n" +
+			"SUMMARY:Boxing \r\n" +
+			" Day\r\n" +
+			"END:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"DTSTART:20221230T000000Z\r\n" +
+			"DTEND:20230102T000000Z\r\n" +
+			"SUMMARY:Break\\, long\r\n" +
+			"END:VEVENT\r\n" +
+			"END:VCALENDAR\r\n"
+		events, err := ReadDayEvents(strings.NewReader(data))
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		expected := []DayEvent{
+			{Date: model.Date{Year: 2022, Month: 12, Day: 26}, Summary: "Boxing Day"},
+			{Date: model.Date{Year: 2022, Month: 12, Day: 30}, Summary: "Break, long"},
+			{Date: model.Date{Year: 2022, Month: 12, Day: 31}, Summary: "Break, long"},
+			{Date: model.Date{Year: 2023, Month: 1, Day: 1}, Summary: "Break, long"},
+		}
+		if len(events) != len(expected) {
+			t.Fatalf("testcase '%s' failed: expected %v, got %v", testcase, expected, events)
+		}
+		for i := range expected {
+			if events[i] != expected[i] {
+				t.Fatalf("testcase '%s' failed: expected %v, got %v", testcase, expected, events)
+			}
+		}
+	}
+	{
+		testcase := "event without start"
+		_, err := ReadDayEvents(strings.NewReader("BEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT\n"))
+		if err == nil {
+			t.Fatalf("testcase '%s' failed: expected error", testcase)
+		}
+	}
+	{
+		testcase := "malformed date"
+		_, err := ReadDayEvents(strings.NewReader("BEGIN:VEVENT\nDTSTART:2022\nEND:VEVENT\n"))
+		if err == nil {
+			t.Fatalf("testcase '%s' failed: expected error", testcase)
+		}
+	}
+}
@@ -4,12 +4,40 @@ type Keyspec string
 type Actionspec string
 type Modename string
 
+// Keymap maps input sequences to the IDs of the actions they trigger.
+type Keymap map[Keyspec]Actionspec
+
+// WithOverrides returns a copy of the keymap with the given overrides applied,
+// i.e., with input sequences (re-)mapped to the overriding action IDs, except
+// for those overridden by the empty action ID, which are unmapped.
+func (k Keymap) WithOverrides(overrides Keymap) Keymap {
+	result := make(Keymap, len(k)+len(overrides))
+	for keyspec, actionspec := range k {
+		result[keyspec] = actionspec
+	}
+	for keyspec, actionspec := range overrides {
+		if actionspec == "" {
+			delete(result, keyspec)
+		} else {
+			result[keyspec] = actionspec
+		}
+	}
+	return result
+}
+
 type InputConfig struct {
-	Editor       map[Keyspec]Actionspec `yaml:"editor"`
-	StringEditor ModedSpec              `yaml:"string-editor"`
+	Editor       Keymap    `yaml:"editor"`
+	StringEditor ModedSpec `yaml:"string-editor"`
+	// ValueEditor are the mappings for editors of values like numbers or
+	// timestamps in normal mode, in addition to (or overriding) the string
+	// editors'.
+	ValueEditor Keymap `yaml:"value-editor"`
+	// ChoiceEditor are the mappings for editors choosing from known values, in
+	// addition to (or overriding) the string editors'.
+	ChoiceEditor ModedSpec `yaml:"choice-editor"`
 }
 
 type ModedSpec struct {
-	Normal map[Keyspec]Actionspec `yaml:"normal"`
-	Insert map[Keyspec]Actionspec `yaml:"insert"`
+	Normal Keymap `yaml:"normal"`
+	Insert Keymap `yaml:"insert"`
 }
@@ -59,6 +59,24 @@ func TestConfigKeyspecToKey(t *testing.T) {
 					t.Error("expected single key to be <space>")
 				}
 			})
+			t.Run("<tab>", func(t *testing.T) {
+				keys := expectValid("<tab>")
+				if len(keys) != 1 {
+					t.Error("expected single key")
+				}
+				if (keys[0] != input.Key{Key: tcell.KeyTab}) || (keys[0] != expectValid("<c-i>")[0]) {
+					t.Error("expected single key to be <tab> (same as <c-i>)")
+				}
+			})
+			t.Run("<up>", func(t *testing.T) {
+				keys := expectValid("<up>")
+				if len(keys) != 1 {
+					t.Error("expected single key")
+				}
+				if input.ToConfigIdentifierString(keys[0]) != "<up>" {
+					t.Error("expected single key to be <up>, not", input.ToConfigIdentifierString(keys[0]))
+				}
+			})
 		})
 
 		t.Run("sequence", func(t *testing.T) {
@@ -283,6 +301,69 @@ func TestConstructInputTree(t *testing.T) {
 		}
 	})
 
+	t.Run("conflicting mappings error", func(t *testing.T) {
+		for name, spec := range map[string]map[input.Keyspec]action.Action{
+			"prefix":        {"g": &DummyAction{}, "gg": &DummyAction{}},
+			"same sequence": {"<cr>": &DummyAction{}, "<CR>": &DummyAction{}},
+			"empty":         {"": &DummyAction{}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				tree, err := input.ConstructInputTree(spec)
+				if err == nil {
+					t.Error("nil error despite conflicting mappings")
+				}
+				if tree != nil {
+					t.Error("non-nil tree despite conflicting mappings")
+				}
+			})
+		}
+	})
+
+}
+
+func TestConstructInputTreeFromKeymap(t *testing.T) {
+	applied := false
+	actions := map[input.Actionspec]action.Action{
+		"do-it": &DummyAction{F: func() { applied = true }},
+	}
+
+	t.Run("known action", func(t *testing.T) {
+		tree, err := input.ConstructInputTreeFromKeymap(input.Keymap{"x": "do-it"}, actions)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if !tree.ProcessInput(input.Key{Key: tcell.KeyRune, Ch: 'x'}) || !applied {
+			t.Error("mapped action not applied")
+		}
+	})
+
+	t.Run("unknown action errors", func(t *testing.T) {
+		tree, err := input.ConstructInputTreeFromKeymap(input.Keymap{"x": "do-it", "y": "do-something-else"}, actions)
+		if err == nil {
+			t.Error("nil error despite unknown action")
+		}
+		if tree != nil {
+			t.Error("non-nil tree despite unknown action")
+		}
+	})
+}
+
+func TestKeymapWithOverrides(t *testing.T) {
+	defaults := input.Keymap{"a": "one", "b": "two"}
+	result := defaults.WithOverrides(input.Keymap{"a": "three", "b": "", "c": "four"})
+
+	expected := input.Keymap{"a": "three", "c": "four"}
+	if len(result) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	for keyspec, actionspec := range expected {
+		if result[keyspec] != actionspec {
+			t.Errorf("expected '%s' to map to '%s', got '%s'", keyspec, actionspec, result[keyspec])
+		}
+	}
+	if defaults["a"] != "one" || defaults["b"] != "two" {
+		t.Error("overriding modified the original keymap:", defaults)
+	}
 }
 
 func TestEmptyTree(t *testing.T) {
@@ -75,6 +75,12 @@ func KeyIdentifierToKey(identifier string) (Key, error) {
 		"bs":    {Key: tcell.KeyBackspace2},
 		"left":  {Key: tcell.KeyLeft},
 		"right": {Key: tcell.KeyRight},
+		"up":    {Key: tcell.KeyUp},
+		"down":  {Key: tcell.KeyDown},
+		"home":  {Key: tcell.KeyHome},
+		"end":   {Key: tcell.KeyEnd},
+		"tab":   {Key: tcell.KeyTab}, // the same as <c-i>
+		"s-tab": {Key: tcell.KeyBacktab},
 
 		"c-space": {Key: tcell.KeyCtrlSpace},
 		"c-bs":    {Key: tcell.KeyBackspace},
@@ -126,6 +132,11 @@ func ToConfigIdentifierString(k Key) string {
 		{Key: tcell.KeyBackspace2}:    "bs",
 		{Key: tcell.KeyLeft}:          "left",
 		{Key: tcell.KeyRight}:         "right",
+		{Key: tcell.KeyUp}:            "up",
+		{Key: tcell.KeyDown}:          "down",
+		{Key: tcell.KeyHome}:          "home",
+		{Key: tcell.KeyEnd}:           "end",
+		{Key: tcell.KeyBacktab}:       "s-tab",
 
 		{Key: tcell.KeyCtrlSpace}: "c-space",
 		{Key: tcell.KeyBackspace}: "c-bs",
@@ -2,6 +2,7 @@ package input
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/ja-he/dayplan/internal/control/action"
 )
@@ -53,17 +54,26 @@ func (t *Tree) CapturesInput() bool {
 
 // ConstructInputTree construct a Tree for the given mappings of input
 // sequence strings to actions.
-// If the given mapping is invalid, this returns an error.
+// If the given mapping is invalid, this returns an error; this includes
+// conflicting mappings, i.e., input sequences that are prefixes of others
+// (e.g. "g" and "gg") or that are the same (e.g. "<cr>" and "<CR>").
 func ConstructInputTree(
 	spec map[Keyspec]action.Action,
 ) (*Tree, error) {
 	root := NewNode()
 
-	for keyspec, a := range spec {
+	// remember for which keyspec each leaf was added, for reporting conflicts
+	leafKeyspecs := make(map[*Node]Keyspec)
+
+	for _, keyspec := range sortedKeyspecs(spec) {
+		a := spec[keyspec]
 		keySequence, err := ConfigKeyspecToKeys(keyspec)
 		if err != nil {
 			return nil, fmt.Errorf("error converting config keyspec: '%s'", err.Error())
 		}
+		if len(keySequence) == 0 {
+			return nil, fmt.Errorf("empty keyspec")
+		}
 
 		// we start at the root (no keys) and through the key sequence we traverse
 		// the tree, adding nodes as necessary
@@ -74,10 +84,20 @@ func ConstructInputTree(
 				// if we're at the end of the key sequence, we need to add the action, else a new node representing the this key
 				if i == len(keySequence)-1 {
 					sequenceNext = NewLeaf(a)
+					leafKeyspecs[sequenceNext] = keyspec
 				} else {
 					sequenceNext = NewNode()
 				}
 				sequenceCurrent.Children[key] = sequenceNext
+			} else {
+				switch {
+				case sequenceNext.Action != nil && i == len(keySequence)-1:
+					return nil, fmt.Errorf("conflicting mappings: '%s' and '%s' are the same input sequence", leafKeyspecs[sequenceNext], keyspec)
+				case sequenceNext.Action != nil:
+					return nil, fmt.Errorf("conflicting mappings: '%s' is a prefix of '%s'", leafKeyspecs[sequenceNext], keyspec)
+				case i == len(keySequence)-1:
+					return nil, fmt.Errorf("conflicting mappings: '%s' is a prefix of other mappings", keyspec)
+				}
 			}
 			// now sequenceNext is guaranteed to be sequenceCurrent.Children[key]
 			sequenceCurrent = sequenceNext
@@ -90,6 +110,44 @@ func ConstructInputTree(
 	}, nil
 }
 
+// ConstructInputTreeFromKeymap constructs a Tree for the given keymap, with
+// the actions for the action IDs taken from the given actions.
+// Besides the errors of ConstructInputTree, this returns an error if an action
+// ID is unknown.
+func ConstructInputTreeFromKeymap(keymap Keymap, actions map[Actionspec]action.Action) (*Tree, error) {
+	spec, err := ResolveKeymap(keymap, actions)
+	if err != nil {
+		return nil, err
+	}
+	return ConstructInputTree(spec)
+}
+
+// ResolveKeymap returns the mapping of the given keymap's input sequences to
+// the actions for their action IDs, taken from the given actions.
+// If an action ID is unknown, this returns an error.
+func ResolveKeymap(keymap Keymap, actions map[Actionspec]action.Action) (map[Keyspec]action.Action, error) {
+	result := make(map[Keyspec]action.Action, len(keymap))
+	for keyspec, actionspec := range keymap {
+		a, ok := actions[actionspec]
+		if !ok {
+			return nil, fmt.Errorf("unknown action '%s' (mapped to '%s')", actionspec, keyspec)
+		}
+		result[keyspec] = a
+	}
+	return result, nil
+}
+
+// sortedKeyspecs returns the keyspecs of the given mapping in sorted order,
+// so that trees (and errors) are constructed deterministically.
+func sortedKeyspecs(spec map[Keyspec]action.Action) []Keyspec {
+	result := make([]Keyspec, 0, len(spec))
+	for keyspec := range spec {
+		result = append(result, keyspec)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
 // EmptyTree returns a pointer to an empty tree.
 func EmptyTree() *Tree {
 	root := NewNode()
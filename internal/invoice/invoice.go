@@ -0,0 +1,243 @@
+// Package invoice provides invoices computed from the time spent on billable
+// categories, their output, and the ledger of issued invoices.
+package invoice
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/timesheet"
+)
+
+// Rate is the billing rate of a category.
+type Rate struct {
+	Client string
+	// Hourly is the hourly rate.
+	Hourly   float64
+	Currency string
+	// Rounding is applied to the duration of each line item.
+	Rounding timesheet.Rounding
+}
+
+// RatesFromConfig constructs the rates of the billable categories among the
+// given ones, by category name.
+func RatesFromConfig(categories []config.Category) (map[string]Rate, error) {
+	result := make(map[string]Rate)
+	for _, cat := range categories {
+		if cat.Billing == nil {
+			continue
+		}
+		if cat.Billing.Client == "" || strings.Contains(cat.Billing.Client, "|") {
+			return nil, fmt.Errorf("billing of category '%s' has no or an invalid client ('%s')", cat.Name, cat.Billing.Client)
+		}
+		if strings.Contains(cat.Billing.Currency, "|") {
+			return nil, fmt.Errorf("billing of category '%s' has an invalid currency ('%s')", cat.Name, cat.Billing.Currency)
+		}
+		if cat.Billing.Rate < 0 {
+			return nil, fmt.Errorf("billing of category '%s' has negative rate %.2f", cat.Name, cat.Billing.Rate)
+		}
+		rounding, err := timesheet.NewRoundingFromConfig(cat.Billing.Rounding)
+		if err != nil {
+			return nil, fmt.Errorf("invalid billing rounding of category '%s': %w", cat.Name, err)
+		}
+		result[cat.Name] = Rate{
+			Client:   cat.Billing.Client,
+			Hourly:   cat.Billing.Rate,
+			Currency: cat.Billing.Currency,
+			Rounding: rounding,
+		}
+	}
+	return result, nil
+}
+
+// Clients returns the (sorted, distinct) clients of the given rates.
+func Clients(rates map[string]Rate) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0)
+	for _, rate := range rates {
+		if !seen[rate.Client] {
+			seen[rate.Client] = true
+			result = append(result, rate.Client)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// GroupBy defines what the line items of an invoice are.
+type GroupBy string
+
+const (
+	// ByDay gives a line item per day and category.
+	ByDay GroupBy = "day"
+	// ByName gives a line item per category and event name.
+	ByName GroupBy = "name"
+)
+
+// LineItem is an item of an invoice.
+type LineItem struct {
+	// Date is the day of the item when grouping by day, otherwise the first day
+	// with time on the item.
+	Date     model.Date
+	Category string
+	// Description is the category name when grouping by day, otherwise the
+	// event name.
+	Description string
+	// Duration is the (rounded) duration billed.
+	Duration time.Duration
+	Rate     float64
+	Amount   float64
+}
+
+// Subtotal is the sum of the line items of a category.
+type Subtotal struct {
+	Category string
+	Duration time.Duration
+	Amount   float64
+}
+
+// Invoice is an invoice to a client over a date range.
+type Invoice struct {
+	// Number is the invoice number, empty for drafts.
+	Number    string
+	Client    string
+	Currency  string
+	From, Til model.Date
+	Issued    model.Date
+	Items     []LineItem
+	// Subtotals are the subtotals per category, sorted by category.
+	Subtotals []Subtotal
+
+	Subtotal float64
+	// TaxRate is the tax rate in percent.
+	TaxRate float64
+	Tax     float64
+	Total   float64
+}
+
+// Duration returns the total duration billed.
+func (i *Invoice) Duration() time.Duration {
+	sum := time.Duration(0)
+	for _, item := range i.Items {
+		sum += item.Duration
+	}
+	return sum
+}
+
+// Compute computes the invoice to the given client over the given days (the
+// consecutive days starting at from, nil days are treated as empty).
+//
+// Durations are those of the flattened events (see model.Day.Flatten), i.e.,
+// time is never billed twice; amounts are rounded to cents.
+func Compute(client string, from model.Date, days []*model.Day, rates map[string]Rate, groupBy GroupBy, taxRate float64) (*Invoice, error) {
+	result := &Invoice{
+		Client:  client,
+		From:    from,
+		Til:     from.Forward(len(days) - 1),
+		TaxRate: taxRate,
+		Items:   make([]LineItem, 0),
+	}
+
+	currencySet := false
+	for name, rate := range rates {
+		if rate.Client != client {
+			continue
+		}
+		if currencySet && rate.Currency != result.Currency {
+			return nil, fmt.Errorf("categories of client '%s' are billed in different currencies ('%s' and '%s', the latter for '%s')", client, result.Currency, rate.Currency, name)
+		}
+		result.Currency, currencySet = rate.Currency, true
+	}
+	if !currencySet {
+		return nil, fmt.Errorf("no categories are billed to client '%s'", client)
+	}
+
+	type itemKey struct {
+		date        model.Date
+		category    string
+		description string
+	}
+	durations := make(map[itemKey]time.Duration)
+	firstDates := make(map[itemKey]model.Date)
+	for i, day := range days {
+		if day == nil {
+			continue
+		}
+		date := from.Forward(i)
+		flattened := day.Clone()
+		flattened.Flatten()
+		for _, event := range flattened.Events {
+			rate, billable := rates[event.Cat.Name]
+			if !billable || rate.Client != client {
+				continue
+			}
+			key := itemKey{category: event.Cat.Name}
+			switch groupBy {
+			case ByName:
+				key.description = event.Name
+			default:
+				key.date, key.description = date, event.Cat.Name
+			}
+			if _, ok := firstDates[key]; !ok {
+				firstDates[key] = date
+			}
+			durations[key] += time.Duration(event.Duration()) * time.Minute
+		}
+	}
+
+	for key, duration := range durations {
+		rate := rates[key.category]
+		duration = rate.Rounding.RoundDuration(duration)
+		result.Items = append(result.Items, LineItem{
+			Date:        firstDates[key],
+			Category:    key.category,
+			Description: key.description,
+			Duration:    duration,
+			Rate:        rate.Hourly,
+			Amount:      cents(duration.Hours() * rate.Hourly),
+		})
+	}
+	sort.Slice(result.Items, func(i, j int) bool {
+		a, b := result.Items[i], result.Items[j]
+		if a.Date != b.Date {
+			return a.Date.IsBefore(b.Date)
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Description < b.Description
+	})
+
+	subtotals := make(map[string]*Subtotal)
+	for _, item := range result.Items {
+		subtotal, ok := subtotals[item.Category]
+		if !ok {
+			subtotal = &Subtotal{Category: item.Category}
+			subtotals[item.Category] = subtotal
+		}
+		subtotal.Duration += item.Duration
+		subtotal.Amount += item.Amount
+		result.Subtotal += item.Amount
+	}
+	result.Subtotals = make([]Subtotal, 0, len(subtotals))
+	for _, subtotal := range subtotals {
+		subtotal.Amount = cents(subtotal.Amount)
+		result.Subtotals = append(result.Subtotals, *subtotal)
+	}
+	sort.Slice(result.Subtotals, func(i, j int) bool { return result.Subtotals[i].Category < result.Subtotals[j].Category })
+	result.Subtotal = cents(result.Subtotal)
+	result.Tax = cents(result.Subtotal * taxRate / 100)
+	result.Total = cents(result.Subtotal + result.Tax)
+
+	return result, nil
+}
+
+// cents rounds the given amount to cents.
+func cents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
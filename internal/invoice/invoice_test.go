@@ -0,0 +1,216 @@
+package invoice
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/timesheet"
+)
+
+func TestRatesFromConfig(t *testing.T) {
+	rates, err := RatesFromConfig([]config.Category{
+		{Name: "acme", Billing: &config.Billing{Client: "ACME", Rate: 100, Currency: "EUR", Rounding: config.Rounding{Mode: "up", To: "15m"}}},
+		{Name: "acme-meetings", Billing: &config.Billing{Client: "ACME", Rate: 80, Currency: "EUR"}},
+		{Name: "initech", Billing: &config.Billing{Client: "Initech", Rate: 50, Currency: "USD"}},
+		{Name: "eating"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(rates) != 3 || rates["acme"].Rounding != (timesheet.Rounding{Mode: timesheet.RoundUp, To: 15 * time.Minute}) {
+		t.Fatalf("unexpected rates: %v", rates)
+	}
+	if clients := Clients(rates); strings.Join(clients, ",") != "ACME,Initech" {
+		t.Fatalf("unexpected clients: %v", clients)
+	}
+
+	for _, invalid := range []config.Billing{
+		{Rate: 100},
+		{Client: "a|b", Rate: 100},
+		{Client: "ACME", Rate: 100, Currency: "E|R"},
+		{Client: "ACME", Rate: -1},
+		{Client: "ACME", Rate: 100, Rounding: config.Rounding{Mode: "sideways", To: "15m"}},
+	} {
+		invalid := invalid
+		_, err := RatesFromConfig([]config.Category{{Name: "x", Billing: &invalid}})
+		if err == nil {
+			t.Fatalf("expected error for invalid billing %v", invalid)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	categories := []model.Category{
+		{Name: "acme", Priority: 1},
+		{Name: "acme-meetings", Priority: 2},
+		{Name: "initech", Priority: 1},
+	}
+	rates := map[string]Rate{
+		"acme":          {Client: "ACME", Hourly: 100, Currency: "EUR", Rounding: timesheet.Rounding{Mode: timesheet.RoundUp, To: 15 * time.Minute}},
+		"acme-meetings": {Client: "ACME", Hourly: 80, Currency: "EUR"},
+		"initech":       {Client: "Initech", Hourly: 50, Currency: "USD"},
+	}
+	monday := model.Date{Year: 2022, Month: 12, Day: 5}
+	days := []*model.Day{
+		model.NewDayWithEvents([]*model.Event{
+			model.NewEvent("09:00|12:00|acme|API", categories),
+			model.NewEvent("10:00|10:30|acme-meetings|Sync", categories),
+			model.NewEvent("13:00|14:00|initech|Support", categories),
+		}),
+		nil,
+		model.NewDayWithEvents([]*model.Event{
+			model.NewEvent("09:00|09:50|acme|API", categories),
+		}),
+	}
+
+	{
+		testcase := "by day"
+		invoice, err := Compute("ACME", monday, days, rates, ByDay, 19)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		// the meeting takes precedence over (and is not billed twice as) acme
+		if len(invoice.Items) != 3 ||
+			invoice.Items[0] != (LineItem{Date: monday, Category: "acme", Description: "acme", Duration: 150 * time.Minute, Rate: 100, Amount: 250}) ||
+			invoice.Items[1] != (LineItem{Date: monday, Category: "acme-meetings", Description: "acme-meetings", Duration: 30 * time.Minute, Rate: 80, Amount: 40}) ||
+			invoice.Items[2] != (LineItem{Date: monday.Forward(2), Category: "acme", Description: "acme", Duration: time.Hour, Rate: 100, Amount: 100}) {
+			t.Fatalf("testcase '%s' failed: got items %v", testcase, invoice.Items)
+		}
+		if invoice.Currency != "EUR" || invoice.Til != monday.Forward(2) || invoice.Duration() != 4*time.Hour ||
+			invoice.Subtotal != 390 || invoice.Tax != 74.1 || invoice.Total != 464.1 {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, invoice)
+		}
+		if len(invoice.Subtotals) != 2 ||
+			invoice.Subtotals[0] != (Subtotal{Category: "acme", Duration: 210 * time.Minute, Amount: 350}) ||
+			invoice.Subtotals[1] != (Subtotal{Category: "acme-meetings", Duration: 30 * time.Minute, Amount: 40}) {
+			t.Fatalf("testcase '%s' failed: got subtotals %v", testcase, invoice.Subtotals)
+		}
+	}
+	{
+		testcase := "by name"
+		invoice, err := Compute("ACME", monday, days, rates, ByName, 0)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		// rounding applies to the aggregated item (150m + 50m = 200m -> 210m)
+		if len(invoice.Items) != 2 ||
+			invoice.Items[0] != (LineItem{Date: monday, Category: "acme", Description: "API", Duration: 210 * time.Minute, Rate: 100, Amount: 350}) ||
+			invoice.Items[1].Description != "Sync" || invoice.Total != 390 {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, invoice)
+		}
+	}
+	{
+		testcase := "unknown client"
+		_, err := Compute("Umbrella", monday, days, rates, ByDay, 0)
+		if err == nil {
+			t.Fatalf("testcase '%s' failed: expected error", testcase)
+		}
+	}
+	{
+		testcase := "mixed currencies"
+		mixed := map[string]Rate{"acme": rates["acme"], "acme-meetings": {Client: "ACME", Hourly: 80, Currency: "USD"}}
+		_, err := Compute("ACME", monday, days, mixed, ByDay, 0)
+		if err == nil {
+			t.Fatalf("testcase '%s' failed: expected error", testcase)
+		}
+	}
+}
+
+func TestLedger(t *testing.T) {
+	december := model.Date{Year: 2022, Month: 12, Day: 1}
+	ledger := Ledger{}
+	if ledger.NextSequence() != 1 {
+		t.Fatalf("expected first sequence number 1, got %d", ledger.NextSequence())
+	}
+
+	invoice := &Invoice{Client: "ACME", Currency: "EUR", From: december, Til: december.GetLastOfMonth(), Total: 1234.5}
+	ledger.Issue(invoice, "INV-%04d", model.Date{Year: 2023, Month: 1, Day: 2})
+	if invoice.Number != "INV-0001" || len(ledger.Entries) != 1 || ledger.NextSequence() != 2 {
+		t.Fatalf("unexpected ledger %v after issuing %v", ledger, invoice)
+	}
+
+	str := ledger.Entries[0].ToString()
+	if str != "1|INV-0001|ACME|2022-12-01|2022-12-31|2023-01-02|1234.50|EUR" {
+		t.Fatalf("unexpected string representation '%s'", str)
+	}
+	parsed, err := NewLedgerEntryFromString(str)
+	if err != nil || parsed != ledger.Entries[0] {
+		t.Fatalf("expected round trip of '%s', got %v (err: %v)", str, parsed, err)
+	}
+	for _, malformed := range []string{"", "1|INV|ACME", "x|INV|ACME|2022-12-01|2022-12-31|2023-01-02|1.00|EUR", "1|INV|ACME|2022-12-01|2022-12-32|2023-01-02|1.00|EUR"} {
+		if _, err := NewLedgerEntryFromString(malformed); err == nil {
+			t.Fatalf("expected error for malformed entry '%s'", malformed)
+		}
+	}
+
+	if len(ledger.Overlapping("ACME", model.Date{Year: 2022, Month: 12, Day: 31}, model.Date{Year: 2023, Month: 1, Day: 31})) != 1 {
+		t.Fatalf("expected overlap")
+	}
+	if len(ledger.Overlapping("ACME", model.Date{Year: 2023, Month: 1, Day: 1}, model.Date{Year: 2023, Month: 1, Day: 31})) != 0 ||
+		len(ledger.Overlapping("Initech", december, december)) != 0 {
+		t.Fatalf("expected no overlap")
+	}
+}
+
+func TestNumberFormatFromConfig(t *testing.T) {
+	for configured, expected := range map[string]string{"": "%d", "INV-%04d": "INV-%04d"} {
+		format, err := NumberFormatFromConfig(config.Invoice{NumberFormat: configured})
+		if err != nil || format != expected {
+			t.Fatalf("expected format '%s' for '%s', got '%s' (err: %v)", expected, configured, format, err)
+		}
+	}
+	for _, invalid := range []string{"INV", "INV-%s", "INV-%d-%d", "INV|%d"} {
+		if _, err := NumberFormatFromConfig(config.Invoice{NumberFormat: invalid}); err == nil {
+			t.Fatalf("expected error for invalid format '%s'", invalid)
+		}
+	}
+}
+
+func TestOutput(t *testing.T) {
+	monday := model.Date{Year: 2022, Month: 12, Day: 5}
+	invoice := &Invoice{
+		Client: "ACME <Corp>", Currency: "EUR", From: monday, Til: monday.Forward(4),
+		Items: []LineItem{
+			{Date: monday, Category: "acme", Description: "acme", Duration: 90 * time.Minute, Rate: 100, Amount: 150},
+			{Date: monday, Category: "acme-meetings", Description: "acme-meetings", Duration: 30 * time.Minute, Rate: 80, Amount: 40},
+		},
+		Subtotals: []Subtotal{{Category: "acme", Duration: 90 * time.Minute, Amount: 150}, {Category: "acme-meetings", Duration: 30 * time.Minute, Amount: 40}},
+		Subtotal:  190, TaxRate: 19, Tax: 36.1, Total: 226.1,
+	}
+
+	var b strings.Builder
+	if err := WriteMarkdown(&b, invoice); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !strings.HasPrefix(b.String(), "# Invoice DRAFT\n") || !strings.Contains(b.String(), "| 2022-12-05 | acme | 1.50 | 100.00 | 150.00 |") ||
+		!strings.Contains(b.String(), "| | Subtotal acme-meetings | 0.50 | | 40.00 |\n| | **Subtotal** | 2.00 | | 190.00 |") ||
+		!strings.Contains(b.String(), "| | Tax (19%) | | | 36.10 |") || !strings.Contains(b.String(), "**226.10 EUR**") {
+		t.Fatalf("unexpected markdown:\n%s", b.String())
+	}
+
+	b.Reset()
+	if err := WriteHTML(&b, invoice); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !strings.Contains(b.String(), "ACME &lt;Corp&gt;") || !strings.Contains(b.String(), "<td>Subtotal acme</td><td class=\"num\">1.50</td>") {
+		t.Fatalf("expected escaped client and subtotals in html:\n%s", b.String())
+	}
+
+	b.Reset()
+	invoice.Number = "INV-0001"
+	if err := WriteJSON(&b, invoice); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !strings.Contains(b.String(), `"number": "INV-0001"`) || !strings.Contains(b.String(), `"minutes": 90`) || !strings.Contains(b.String(), `"total": 226.1`) ||
+		!strings.Contains(b.String(), `"subtotals": [`) {
+		t.Fatalf("unexpected json:\n%s", b.String())
+	}
+
+	b.Reset()
+	if err := WriteTemplate(&b, `{{.Number}}: {{money .Total}} {{.Currency}}`, invoice); err != nil || b.String() != "INV-0001: 226.10 EUR" {
+		t.Fatalf("unexpected template output '%s' (err: %v)", b.String(), err)
+	}
+}
@@ -0,0 +1,138 @@
+package invoice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// LedgerEntry is the record of an issued invoice.
+type LedgerEntry struct {
+	// Sequence is the sequence number the invoice number is formatted from.
+	Sequence  int
+	Number    string
+	Client    string
+	From, Til model.Date
+	Issued    model.Date
+	Total     float64
+	Currency  string
+}
+
+// NewLedgerEntryFromString parses a ledger entry from its string
+// representation, as produced by ToString.
+func NewLedgerEntryFromString(s string) (LedgerEntry, error) {
+	fields := strings.Split(s, "|")
+	if len(fields) != 8 {
+		return LedgerEntry{}, fmt.Errorf("malformed ledger entry '%s' (expected '<seq>|<number>|<client>|<from>|<til>|<issued>|<total>|<currency>')", s)
+	}
+	sequence, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return LedgerEntry{}, fmt.Errorf("malformed sequence number '%s' (%w)", fields[0], err)
+	}
+	var dates [3]model.Date
+	for i := range dates {
+		dates[i], err = model.FromString(fields[3+i])
+		if err != nil {
+			return LedgerEntry{}, fmt.Errorf("malformed date '%s' (%w)", fields[3+i], err)
+		}
+	}
+	total, err := strconv.ParseFloat(fields[6], 64)
+	if err != nil {
+		return LedgerEntry{}, fmt.Errorf("malformed total '%s' (%w)", fields[6], err)
+	}
+	return LedgerEntry{
+		Sequence: sequence,
+		Number:   fields[1],
+		Client:   fields[2],
+		From:     dates[0],
+		Til:      dates[1],
+		Issued:   dates[2],
+		Total:    total,
+		Currency: fields[7],
+	}, nil
+}
+
+// ToString returns the string representation of the ledger entry, e.g.
+// "7|INV-0007|ACME|2022-12-01|2022-12-31|2023-01-02|1234.50|EUR".
+func (e LedgerEntry) ToString() string {
+	return strings.Join([]string{
+		strconv.Itoa(e.Sequence),
+		e.Number,
+		e.Client,
+		e.From.ToString(),
+		e.Til.ToString(),
+		e.Issued.ToString(),
+		fmt.Sprintf("%.2f", e.Total),
+		e.Currency,
+	}, "|")
+}
+
+// Ledger is the record of issued invoices, in order of issuing.
+type Ledger struct {
+	Entries []LedgerEntry
+}
+
+// NextSequence returns the sequence number of the next invoice.
+func (l *Ledger) NextSequence() int {
+	next := 1
+	for _, entry := range l.Entries {
+		if entry.Sequence >= next {
+			next = entry.Sequence + 1
+		}
+	}
+	return next
+}
+
+// Overlapping returns the entries of invoices to the given client whose date
+// range overlaps the given one, i.e., which already billed part of it.
+func (l *Ledger) Overlapping(client string, from, til model.Date) []LedgerEntry {
+	result := make([]LedgerEntry, 0)
+	for _, entry := range l.Entries {
+		if entry.Client == client && !entry.From.IsAfter(til) && !entry.Til.IsBefore(from) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// NumberFormatFromConfig returns the format of invoice numbers per the given
+// config ("%d" if none is configured), which has to format a sequence number
+// to a number that can be recorded in the ledger.
+func NumberFormatFromConfig(cfg config.Invoice) (string, error) {
+	if cfg.NumberFormat == "" {
+		return "%d", nil
+	}
+	number := fmt.Sprintf(cfg.NumberFormat, 1)
+	switch {
+	case strings.Contains(number, "%!"):
+		return "", fmt.Errorf("invalid number format '%s' (formats 1 as '%s')", cfg.NumberFormat, number)
+	case strings.Contains(number, "|"):
+		return "", fmt.Errorf("invalid number format '%s' (must not contain '|')", cfg.NumberFormat)
+	}
+	return cfg.NumberFormat, nil
+}
+
+// Issue numbers the given invoice with the next sequence number (formatted per
+// the given format, e.g. "INV-%04d"; "%d" if empty; see NumberFormatFromConfig)
+// and records it in the ledger.
+func (l *Ledger) Issue(invoice *Invoice, numberFormat string, issued model.Date) {
+	if numberFormat == "" {
+		numberFormat = "%d"
+	}
+	sequence := l.NextSequence()
+	invoice.Number = fmt.Sprintf(numberFormat, sequence)
+	invoice.Issued = issued
+	l.Entries = append(l.Entries, LedgerEntry{
+		Sequence: sequence,
+		Number:   invoice.Number,
+		Client:   invoice.Client,
+		From:     invoice.From,
+		Til:      invoice.Til,
+		Issued:   issued,
+		Total:    invoice.Total,
+		Currency: invoice.Currency,
+	})
+}
@@ -0,0 +1,125 @@
+package invoice
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+//go:embed templates/markdown.tmpl
+var markdownTemplateSource string
+
+//go:embed templates/html.tmpl
+var htmlTemplateSource string
+
+// templateFuncs are the functions available to invoice templates.
+var templateFuncs = map[string]any{
+	"date":    func(d model.Date) string { return d.ToString() },
+	"hours":   func(d time.Duration) string { return fmt.Sprintf("%.2f", d.Hours()) },
+	"money":   func(amount float64) string { return fmt.Sprintf("%.2f", amount) },
+	"percent": func(p float64) string { return strconv.FormatFloat(p, 'f', -1, 64) + "%" },
+}
+
+var markdownTemplate = template.Must(template.New("invoice").Funcs(templateFuncs).Parse(markdownTemplateSource))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("invoice").Funcs(templateFuncs).Parse(htmlTemplateSource))
+
+// WriteMarkdown writes the given invoice as a Markdown document.
+func WriteMarkdown(w io.Writer, invoice *Invoice) error {
+	return markdownTemplate.Execute(w, invoice)
+}
+
+// WriteHTML writes the given invoice as a self-contained HTML document.
+func WriteHTML(w io.Writer, invoice *Invoice) error {
+	return htmlTemplate.Execute(w, invoice)
+}
+
+// WriteTemplate writes the given invoice per the given Go template (see
+// text/template), which is executed on the Invoice and can use the functions
+//   - date: formats a date as "YYYY-MM-DD"
+//   - hours: formats a duration as decimal hours, e.g. "7.50"
+//   - money: formats an amount with two decimals
+//   - percent: formats a percentage, e.g. "19%"
+func WriteTemplate(w io.Writer, templateText string, invoice *Invoice) error {
+	tmpl, err := template.New("invoice").Funcs(templateFuncs).Parse(templateText)
+	if err != nil {
+		return fmt.Errorf("invalid template (%w)", err)
+	}
+	return tmpl.Execute(w, invoice)
+}
+
+// WriteJSON writes the given invoice as a JSON object.
+// Dates are given as "YYYY-MM-DD", durations in minutes; the number is empty
+// for drafts.
+func WriteJSON(w io.Writer, invoice *Invoice) error {
+	type jsonItem struct {
+		Date        string  `json:"date"`
+		Category    string  `json:"category"`
+		Description string  `json:"description"`
+		Minutes     int     `json:"minutes"`
+		Rate        float64 `json:"rate"`
+		Amount      float64 `json:"amount"`
+	}
+	type jsonSubtotal struct {
+		Category string  `json:"category"`
+		Minutes  int     `json:"minutes"`
+		Amount   float64 `json:"amount"`
+	}
+	type jsonInvoice struct {
+		Number    string         `json:"number"`
+		Client    string         `json:"client"`
+		Currency  string         `json:"currency"`
+		From      string         `json:"from"`
+		Til       string         `json:"til"`
+		Issued    string         `json:"issued,omitempty"`
+		Items     []jsonItem     `json:"items"`
+		Subtotals []jsonSubtotal `json:"subtotals"`
+		Subtotal  float64        `json:"subtotal"`
+		TaxRate   float64        `json:"tax_rate"`
+		Tax       float64        `json:"tax"`
+		Total     float64        `json:"total"`
+	}
+	result := jsonInvoice{
+		Number:    invoice.Number,
+		Client:    invoice.Client,
+		Currency:  invoice.Currency,
+		From:      invoice.From.ToString(),
+		Til:       invoice.Til.ToString(),
+		Items:     make([]jsonItem, 0, len(invoice.Items)),
+		Subtotals: make([]jsonSubtotal, 0, len(invoice.Subtotals)),
+		Subtotal:  invoice.Subtotal,
+		TaxRate:   invoice.TaxRate,
+		Tax:       invoice.Tax,
+		Total:     invoice.Total,
+	}
+	if invoice.Number != "" {
+		result.Issued = invoice.Issued.ToString()
+	}
+	for _, item := range invoice.Items {
+		result.Items = append(result.Items, jsonItem{
+			Date:        item.Date.ToString(),
+			Category:    item.Category,
+			Description: item.Description,
+			Minutes:     int(item.Duration / time.Minute),
+			Rate:        item.Rate,
+			Amount:      item.Amount,
+		})
+	}
+	for _, subtotal := range invoice.Subtotals {
+		result.Subtotals = append(result.Subtotals, jsonSubtotal{
+			Category: subtotal.Category,
+			Minutes:  int(subtotal.Duration / time.Minute),
+			Amount:   subtotal.Amount,
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
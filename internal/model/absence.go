@@ -0,0 +1,167 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AbsenceKind is the kind of an absence, e.g. a vacation day.
+type AbsenceKind string
+
+const (
+	AbsenceVacation AbsenceKind = "vacation"
+	AbsenceSick     AbsenceKind = "sick"
+	AbsenceHoliday  AbsenceKind = "holiday"
+	AbsenceHalfDay  AbsenceKind = "half-day"
+)
+
+// AbsenceKinds are all known absence kinds, in the order in which they are
+// cycled through.
+var AbsenceKinds = []AbsenceKind{AbsenceVacation, AbsenceSick, AbsenceHoliday, AbsenceHalfDay}
+
+// AbsenceKindFromString returns the absence kind with the given name or an
+// error, if there is none.
+func AbsenceKindFromString(s string) (AbsenceKind, error) {
+	for _, kind := range AbsenceKinds {
+		if string(kind) == s {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("unknown absence kind '%s' (known: vacation, sick, holiday, half-day)", s)
+}
+
+// RequiredFraction returns the fraction of the usual goal duration that is
+// still required on a day with an absence of this kind.
+func (k AbsenceKind) RequiredFraction() float64 {
+	switch k {
+	case AbsenceHalfDay:
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+// Absence is a day-level status, such as a vacation or a public holiday, that
+// reduces the time required by goals.
+type Absence struct {
+	Kind AbsenceKind
+	// Note is an optional description, e.g. the holiday's name.
+	Note string
+}
+
+// String returns a short human-readable representation, e.g.
+// "holiday (New Year's Day)".
+func (a Absence) String() string {
+	if a.Note == "" {
+		return string(a.Kind)
+	}
+	return fmt.Sprintf("%s (%s)", a.Kind, a.Note)
+}
+
+// DatedAbsence is an absence along with its date.
+type DatedAbsence struct {
+	Date Date
+	Absence
+}
+
+// NewDatedAbsenceFromString parses a dated absence from its string
+// representation, as produced by ToString.
+func NewDatedAbsenceFromString(s string) (DatedAbsence, error) {
+	fields := strings.SplitN(s, "|", 3)
+	if len(fields) < 2 {
+		return DatedAbsence{}, fmt.Errorf("malformed absence '%s' (expected '<date>|<kind>[|<note>]')", s)
+	}
+	date, err := FromString(fields[0])
+	if err != nil {
+		return DatedAbsence{}, fmt.Errorf("malformed absence date '%s' (%w)", fields[0], err)
+	}
+	kind, err := AbsenceKindFromString(fields[1])
+	if err != nil {
+		return DatedAbsence{}, err
+	}
+	result := DatedAbsence{Date: date, Absence: Absence{Kind: kind}}
+	if len(fields) == 3 {
+		result.Note = fields[2]
+	}
+	return result, nil
+}
+
+// ToString returns the string representation of the dated absence, e.g.
+// "2022-12-26|holiday|Boxing Day".
+func (a DatedAbsence) ToString() string {
+	return a.Date.ToString() + "|" + string(a.Kind) + "|" + a.Note
+}
+
+// Absences are the absences by date.
+// They are safe for concurrent use; a nil *Absences is valid and empty.
+type Absences struct {
+	mutex  sync.RWMutex
+	byDate map[Date]Absence
+}
+
+// NewAbsences returns an empty set of absences.
+func NewAbsences() *Absences {
+	return &Absences{byDate: make(map[Date]Absence)}
+}
+
+// Get returns the absence on the given date, if there is one.
+func (a *Absences) Get(date Date) (Absence, bool) {
+	if a == nil {
+		return Absence{}, false
+	}
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	absence, ok := a.byDate[date]
+	return absence, ok
+}
+
+// Set sets the absence on the given date, replacing any previous one.
+func (a *Absences) Set(date Date, absence Absence) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.byDate[date] = absence
+}
+
+// Unset removes the absence on the given date, if there is one.
+func (a *Absences) Unset(date Date) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	delete(a.byDate, date)
+}
+
+// All returns all absences, sorted by date.
+func (a *Absences) All() []DatedAbsence {
+	if a == nil {
+		return nil
+	}
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	result := make([]DatedAbsence, 0, len(a.byDate))
+	for date, absence := range a.byDate {
+		result = append(result, DatedAbsence{Date: date, Absence: absence})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date.IsBefore(result[j].Date) })
+	return result
+}
+
+// AbsenceAdjustedGoal is a Goal that requires less (or nothing) on days with
+// absences.
+type AbsenceAdjustedGoal struct {
+	Goal     Goal
+	Absences *Absences
+}
+
+// Requires returns the duration required for the given date.
+//
+// It is the duration the underlying goal requires, reduced according to the
+// absence on the date, if any.
+func (g *AbsenceAdjustedGoal) Requires(date Date) time.Duration {
+	required := g.Goal.Requires(date)
+	if absence, ok := g.Absences.Get(date); ok {
+		return time.Duration(float64(required) * absence.Kind.RequiredFraction())
+	}
+	return required
+}
@@ -2,8 +2,9 @@ package model
 
 type Category struct {
 	Name       string `dpedit:"name"`
-	Priority   int    `dpeditr:"priority"`
+	Priority   int    `dpedit:"priority"`
 	Goal       Goal   `dpedit:",ignore"`
+	Limit      Goal   `dpedit:",ignore"`
 	Deprecated bool   `dpedit:",ignore"`
 }
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nathan-osman/go-sunrise"
@@ -273,6 +274,116 @@ func (d Date) MonthBounds() (first Date, last Date) {
 	return first, last
 }
 
+// AddMonths returns the date that is n months after (or, for negative n,
+// before) the receiver, on the same day of the month or, if that month is
+// shorter, on its last day.
+func (d Date) AddMonths(n int) Date {
+	months := d.Year*12 + (d.Month - 1) + n
+	first := Date{Year: months / 12, Month: months%12 + 1, Day: 1}
+	last := first.GetLastOfMonth()
+	if d.Day > last.Day {
+		return last
+	}
+	first.Day = d.Day
+	return first
+}
+
+// ParseRelativeDate parses a date as entered by a user, which can be
+//   - a date in the format "YYYY-MM-DD",
+//   - "today", "yesterday" or "tomorrow", relative to today,
+//   - a weekday (e.g. "friday" or "fri"), optionally preceded by "next" or
+//     "last", for the next (or last) such day strictly after (or before)
+//     today, or
+//   - an offset such as "+2w" or "-1m" in days (d), weeks (w), months (m) or
+//     years (y), relative to the current date (e.g. the one being viewed).
+func ParseRelativeDate(s string, today, current Date) (Date, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	switch len(fields) {
+	case 0:
+		return Date{}, fmt.Errorf("no date given")
+	case 1:
+		switch fields[0] {
+		case "today":
+			return today, nil
+		case "yesterday":
+			return today.Prev(), nil
+		case "tomorrow":
+			return today.Next(), nil
+		}
+		if weekday, ok := parseWeekday(fields[0]); ok {
+			return nextWeekday(today, weekday), nil
+		}
+		if offset := relativeDateOffsetRegex.FindStringSubmatch(fields[0]); offset != nil {
+			n, err := strconv.Atoi(offset[2])
+			if err != nil {
+				return Date{}, fmt.Errorf("invalid offset '%s' (%w)", fields[0], err)
+			}
+			if offset[1] == "-" {
+				n = -n
+			}
+			switch offset[3] {
+			case "", "d":
+				if n < 0 {
+					return current.Backward(-n), nil
+				}
+				return current.Forward(n), nil
+			case "w":
+				if n < 0 {
+					return current.Backward(-7 * n), nil
+				}
+				return current.Forward(7 * n), nil
+			case "m":
+				return current.AddMonths(n), nil
+			case "y":
+				return current.AddMonths(12 * n), nil
+			}
+		}
+		return FromString(fields[0])
+	case 2:
+		weekday, ok := parseWeekday(fields[1])
+		if !ok {
+			return Date{}, fmt.Errorf("not a weekday: '%s'", fields[1])
+		}
+		switch fields[0] {
+		case "next":
+			return nextWeekday(today, weekday), nil
+		case "last":
+			return lastWeekday(today, weekday), nil
+		}
+	}
+	return Date{}, fmt.Errorf("not a date: '%s'", s)
+}
+
+var relativeDateOffsetRegex = regexp.MustCompile(`^([+-])(\d+)([dwmy]?)$`)
+
+// parseWeekday parses a weekday by its (English) name or the name's first
+// three letters, in lower case.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for w := time.Sunday; w <= time.Saturday; w++ {
+		name := strings.ToLower(ToString(w))
+		if s == name || s == name[:3] {
+			return w, true
+		}
+	}
+	return 0, false
+}
+
+func nextWeekday(after Date, w time.Weekday) Date {
+	d := after.Next()
+	for d.ToWeekday() != w {
+		d = d.Next()
+	}
+	return d
+}
+
+func lastWeekday(before Date, w time.Weekday) Date {
+	d := before.Prev()
+	for d.ToWeekday() != w {
+		d = d.Prev()
+	}
+	return d
+}
+
 // ToString returns the weekday as a string.
 func ToString(w time.Weekday) string {
 	switch w {
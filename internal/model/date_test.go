@@ -368,3 +368,58 @@ func TestGetDayInMonth(t *testing.T) {
 		}
 	}
 }
+
+func TestAddMonths(t *testing.T) {
+	for _, testcase := range []struct {
+		date     Date
+		n        int
+		expected Date
+	}{
+		{Date{2022, 3, 9}, 1, Date{2022, 4, 9}},
+		{Date{2022, 3, 9}, -3, Date{2021, 12, 9}},
+		{Date{2022, 11, 30}, 14, Date{2024, 1, 30}},
+		{Date{2022, 3, 31}, -1, Date{2022, 2, 28}},
+		{Date{2024, 1, 31}, 1, Date{2024, 2, 29}},
+	} {
+		result := testcase.date.AddMonths(testcase.n)
+		if result != testcase.expected {
+			t.Fatalf("adding %d months to %s gave %s, expected %s",
+				testcase.n, testcase.date.ToString(), result.ToString(), testcase.expected.ToString())
+		}
+	}
+}
+
+func TestParseRelativeDate(t *testing.T) {
+	today := Date{2026, 10, 16}  // a Friday
+	current := Date{2026, 1, 31} // the date being viewed
+	for s, expected := range map[string]Date{
+		"2026-02-03":  {2026, 2, 3},
+		"today":       today,
+		" Yesterday ": {2026, 10, 15},
+		"tomorrow":    {2026, 10, 17},
+		"friday":      {2026, 10, 23},
+		"next fri":    {2026, 10, 23},
+		"mon":         {2026, 10, 19},
+		"last Friday": {2026, 10, 9},
+		"last sun":    {2026, 10, 11},
+		"+3":          {2026, 2, 3},
+		"-1d":         {2026, 1, 30},
+		"+2w":         {2026, 2, 14},
+		"-1m":         {2025, 12, 31},
+		"+1m":         {2026, 2, 28},
+		"+1y":         {2027, 1, 31},
+	} {
+		result, err := ParseRelativeDate(s, today, current)
+		if err != nil {
+			t.Fatalf("parsing '%s' gave error: %s", s, err.Error())
+		}
+		if result != expected {
+			t.Fatalf("parsing '%s' gave %s, expected %s", s, result.ToString(), expected.ToString())
+		}
+	}
+	for _, s := range []string{"", "someday", "next week", "2026-02-30", "+w", "next friday please"} {
+		if _, err := ParseRelativeDate(s, today, current); err == nil {
+			t.Fatalf("parsing '%s' gave no error", s)
+		}
+	}
+}
@@ -316,6 +316,24 @@ func (day *Day) SumUpByCategory() map[Category]int {
 	return result
 }
 
+// SumUpMatching returns the summed up duration (in minutes) of all events of
+// this day matching the given predicate.
+// Like SumUpByCategory, it counts overlapping events' time only once, for the
+// higher-priority event.
+func (day *Day) SumUpMatching(matches func(*Event) bool) int {
+	result := 0
+
+	flattened := day.Clone()
+	flattened.Flatten()
+	for i := range flattened.Events {
+		if matches(flattened.Events[i]) {
+			result += flattened.Events[i].Duration()
+		}
+	}
+
+	return result
+}
+
 // GetTimesheetEntry returns the TimesheetEntry for this day for a given
 // category (e.g. "work").
 func (day *Day) GetTimesheetEntry(matcher func(string) bool) TimesheetEntry {
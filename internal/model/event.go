@@ -8,14 +8,23 @@ import (
 type Event struct {
 	Name  string    `dpedit:"name"`
 	Cat   Category  `dpedit:"category"`
-	Start Timestamp `dpedit:",ignore"`
-	End   Timestamp `dpedit:",ignore"`
+	Start Timestamp `dpedit:"start"`
+	End   Timestamp `dpedit:"end"`
 }
 
 func (e *Event) Duration() int {
 	return e.Start.DurationInMinutesUntil(e.End)
 }
 
+// Validate returns an error if the event is invalid, i.e., if it does not end
+// after it starts.
+func (e *Event) Validate() error {
+	if !e.End.IsAfter(e.Start) {
+		return fmt.Errorf("end %s is not after start %s", e.End.ToString(), e.Start.ToString())
+	}
+	return nil
+}
+
 func NewEvent(s string, knownCategories []Category) *Event {
 	var e Event
 
@@ -127,7 +136,7 @@ func (event *Event) CanMoveBy(minutes int, snapMinsMod int) bool {
 }
 
 func (event *Event) CanMoveTo(newStart Timestamp) bool {
-	return event.CanMoveBy(newStart.DurationInMinutesUntil(event.Start), 1)
+	return event.CanMoveBy(event.Start.DurationInMinutesUntil(newStart), 1)
 }
 
 func (event *Event) CanBeResizedBy(delta int) bool {
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/ja-he/dayplan/internal/config"
@@ -167,6 +168,124 @@ func NewWorkweekGoalFromConfig(cfg config.WorkweekGoal) (*WorkweekGoal, error) {
 	}
 }
 
+// DailyGoal is a goal that requires the same duration on every day.
+type DailyGoal struct {
+	Time time.Duration
+}
+
+// Requires returns the duration required for the given date, which is the
+// same for any date.
+func (g *DailyGoal) Requires(date Date) time.Duration {
+	return g.Time
+}
+
+// WeeklyGoal is a goal that defines a total duration per week, regardless of
+// its distribution over the week's days.
+type WeeklyGoal struct {
+	Time time.Duration
+}
+
+// Requires returns the duration required for the given date.
+//
+// As the total may be distributed over the week in any way, it is an even
+// share of the total for workdays (Monday through Friday), 0 for weekends; for
+// a full week (see GoalForRange) it thus adds up to the total.
+func (g *WeeklyGoal) Requires(date Date) time.Duration {
+	if !isWorkday(date) {
+		return 0
+	}
+	return g.Time / 5
+}
+
+// MonthlyGoal is a goal that defines a total duration per month, regardless of
+// its distribution over the month's days.
+type MonthlyGoal struct {
+	Time time.Duration
+}
+
+// Requires returns the duration required for the given date.
+//
+// As for WeeklyGoal, it is an even share of the total for the month's workdays
+// (Monday through Friday), 0 for weekends.
+func (g *MonthlyGoal) Requires(date Date) time.Duration {
+	if !isWorkday(date) {
+		return 0
+	}
+	first, last := date.MonthBounds()
+	workdays := 0
+	for current := first; current != last.Next(); current = current.Next() {
+		if isWorkday(current) {
+			workdays++
+		}
+	}
+	return g.Time / time.Duration(workdays)
+}
+
+// isWorkday returns whether the given date is a workday (Monday through
+// Friday).
+func isWorkday(date Date) bool {
+	weekday := date.ToWeekday()
+	return weekday != time.Saturday && weekday != time.Sunday
+}
+
+// NewGoalFromConfig constructs the goal of whichever kind is defined in the
+// given config data.
+// If no goal is defined, it returns nil (and no error).
+func NewGoalFromConfig(cfg config.Goal) (Goal, error) {
+	defined := 0
+	for _, isDefined := range []bool{cfg.Workweek != nil, cfg.Ranged != nil, cfg.Daily != "", cfg.Weekly != "", cfg.Monthly != ""} {
+		if isDefined {
+			defined++
+		}
+	}
+	if defined > 1 {
+		return nil, fmt.Errorf("%d kinds of goal defined, at most one is allowed", defined)
+	}
+
+	parseTotal := func(kind, s string) (time.Duration, error) {
+		duration, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing %s duration: %w", kind, err)
+		}
+		return duration, nil
+	}
+
+	switch {
+	case cfg.Ranged != nil:
+		goal, err := NewRangedGoalFromConfig(*cfg.Ranged)
+		if err != nil {
+			return nil, err
+		}
+		return goal, nil
+	case cfg.Workweek != nil:
+		goal, err := NewWorkweekGoalFromConfig(*cfg.Workweek)
+		if err != nil {
+			return nil, err
+		}
+		return goal, nil
+	case cfg.Daily != "":
+		duration, err := parseTotal("daily", cfg.Daily)
+		if err != nil {
+			return nil, err
+		}
+		return &DailyGoal{Time: duration}, nil
+	case cfg.Weekly != "":
+		duration, err := parseTotal("weekly", cfg.Weekly)
+		if err != nil {
+			return nil, err
+		}
+		return &WeeklyGoal{Time: duration}, nil
+	case cfg.Monthly != "":
+		duration, err := parseTotal("monthly", cfg.Monthly)
+		if err != nil {
+			return nil, err
+		}
+		return &MonthlyGoal{Time: duration}, nil
+	default:
+		return nil, nil
+	}
+}
+
 // GoalForRange is a helper to sum up the duration for the given range expected
 // by the given Goal.
 func GoalForRange(goal Goal, startDate, endDate Date) time.Duration {
@@ -180,3 +299,99 @@ func GoalForRange(goal Goal, startDate, endDate Date) time.Duration {
 
 	return sum
 }
+
+// LimitForRange returns the duration allowed by the given limit over the given
+// range.
+//
+// Unlike a goal, which has to be met in any part of its period, a weekly or
+// monthly limit allows its total in any distribution over the week's or
+// month's days (e.g. all of it on a Saturday), so for these the range is
+// extended to the whole weeks or months it overlaps; other limits are summed up
+// like goals (see GoalForRange).
+func LimitForRange(limit Goal, startDate, endDate Date) time.Duration {
+	switch limit := limit.(type) {
+	case *WeeklyGoal:
+		weeks := 0
+		for monday, _ := startDate.WeekBounds(); !monday.IsAfter(endDate); monday = monday.Forward(7) {
+			weeks++
+		}
+		return time.Duration(weeks) * limit.Time
+	case *MonthlyGoal:
+		months := 0
+		for first, _ := startDate.MonthBounds(); !first.IsAfter(endDate); {
+			months++
+			_, last := first.MonthBounds()
+			first = last.Next()
+		}
+		return time.Duration(months) * limit.Time
+	default:
+		return GoalForRange(limit, startDate, endDate)
+	}
+}
+
+// PatternGoal is a goal and/or limit on the events matched by a pattern on
+// their names (e.g. a tag), rather than on a category.
+type PatternGoal struct {
+	Name string
+	// Pattern matches the names of the events that count towards the goal.
+	Pattern *regexp.Regexp
+	// Category, if non-empty, restricts the events that count towards the goal
+	// to those of the category of that name.
+	Category string
+
+	Goal  Goal
+	Limit Goal
+}
+
+// Matches returns whether the given event counts towards this goal.
+func (g *PatternGoal) Matches(event *Event) bool {
+	if g.Category != "" && event.Cat.Name != g.Category {
+		return false
+	}
+	return g.Pattern.MatchString(event.Name)
+}
+
+// NewPatternGoalFromConfig constructs a new PatternGoal from config data.
+//
+// A tag "foo" is matched as the word "#foo" anywhere in an event's name.
+func NewPatternGoalFromConfig(cfg config.PatternGoal) (*PatternGoal, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("goal has no name")
+	}
+
+	var pattern string
+	switch {
+	case cfg.Tag != "" && cfg.Pattern != "":
+		return nil, fmt.Errorf("goal '%s' defines both a tag and a pattern", cfg.Name)
+	case cfg.Tag != "":
+		pattern = `(^|\s)#` + regexp.QuoteMeta(cfg.Tag) + `(\s|$)`
+	case cfg.Pattern != "":
+		pattern = cfg.Pattern
+	default:
+		return nil, fmt.Errorf("goal '%s' defines neither a tag nor a pattern", cfg.Name)
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern for goal '%s': %w", cfg.Name, err)
+	}
+
+	goal, err := NewGoalFromConfig(cfg.Goal)
+	if err != nil {
+		return nil, fmt.Errorf("invalid goal for goal '%s': %w", cfg.Name, err)
+	}
+	limit, err := NewGoalFromConfig(cfg.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid limit for goal '%s': %w", cfg.Name, err)
+	}
+	if goal == nil && limit == nil {
+		return nil, fmt.Errorf("goal '%s' defines neither a goal nor a limit", cfg.Name)
+	}
+
+	return &PatternGoal{
+		Name:     cfg.Name,
+		Pattern:  compiled,
+		Category: cfg.Category,
+		Goal:     goal,
+		Limit:    limit,
+	}, nil
+}
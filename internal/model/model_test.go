@@ -6,6 +6,9 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/config"
 )
 
 func TestStartsDuring(t *testing.T) {
@@ -603,3 +606,271 @@ func eventsEqualExceptingIDs(a, b *Day) bool {
 
 	return true
 }
+
+func TestMoveSingleEventTo(t *testing.T) {
+	{
+		testcase := "move later"
+		e := NewEvent("09:00|12:00|work|Coding", make([]Category, 0))
+		day := NewDayWithEvents([]*Event{e})
+		err := day.MoveSingleEventTo(e, Timestamp{10, 30})
+		if err != nil {
+			t.Fatalf("test case '%s' failed with error: %s", testcase, err.Error())
+		}
+		if (e.Start != Timestamp{10, 30} || e.End != Timestamp{13, 30}) {
+			t.Fatalf("test case '%s' failed: event is %s", testcase, e.toString())
+		}
+	}
+	{
+		testcase := "move earlier"
+		e := NewEvent("09:00|12:00|work|Coding", make([]Category, 0))
+		day := NewDayWithEvents([]*Event{e})
+		err := day.MoveSingleEventTo(e, Timestamp{8, 15})
+		if err != nil {
+			t.Fatalf("test case '%s' failed with error: %s", testcase, err.Error())
+		}
+		if (e.Start != Timestamp{8, 15} || e.End != Timestamp{11, 15}) {
+			t.Fatalf("test case '%s' failed: event is %s", testcase, e.toString())
+		}
+	}
+	{
+		testcase := "move early event later"
+		e := NewEvent("01:00|02:00|sleep|Nap", make([]Category, 0))
+		day := NewDayWithEvents([]*Event{e})
+		err := day.MoveSingleEventTo(e, Timestamp{5, 0})
+		if err != nil {
+			t.Fatalf("test case '%s' failed with error: %s", testcase, err.Error())
+		}
+		if (e.Start != Timestamp{5, 0} || e.End != Timestamp{6, 0}) {
+			t.Fatalf("test case '%s' failed: event is %s", testcase, e.toString())
+		}
+	}
+	{
+		testcase := "move across end of day"
+		e := NewEvent("20:00|21:00|work|Coding", make([]Category, 0))
+		day := NewDayWithEvents([]*Event{e})
+		err := day.MoveSingleEventTo(e, Timestamp{23, 30})
+		if err == nil {
+			t.Fatalf("test case '%s' failed: expected error, event is %s", testcase, e.toString())
+		}
+		if (e.Start != Timestamp{20, 0} || e.End != Timestamp{21, 0}) {
+			t.Fatalf("test case '%s' failed: event was modified to %s", testcase, e.toString())
+		}
+	}
+}
+
+func TestEventValidate(t *testing.T) {
+	for _, tc := range []struct {
+		event string
+		valid bool
+	}{
+		{"09:00|12:00|work|Coding", true},
+		{"09:00|09:01|work|Coding", true},
+		{"09:00|09:00|work|Coding", false},
+		{"12:00|09:00|work|Coding", false},
+	} {
+		err := NewEvent(tc.event, make([]Category, 0)).Validate()
+		if (err == nil) != tc.valid {
+			t.Errorf("expected event '%s' to be valid: %t, got error: %v", tc.event, tc.valid, err)
+		}
+	}
+}
+
+func TestAbsenceAdjustedGoal(t *testing.T) {
+	monday := Date{Year: 2022, Month: 12, Day: 5}
+	tuesday := monday.Next()
+	wednesday := tuesday.Next()
+	absences := NewAbsences()
+	absences.Set(monday, Absence{Kind: AbsenceHoliday, Note: "Some Holiday"})
+	absences.Set(tuesday, Absence{Kind: AbsenceHalfDay})
+	goal := &AbsenceAdjustedGoal{
+		Goal:     &WorkweekGoal{Monday: 8 * time.Hour, Tuesday: 8 * time.Hour, Wednesday: 8 * time.Hour},
+		Absences: absences,
+	}
+
+	if goal.Requires(monday) != 0 {
+		t.Fatalf("expected no requirement on holiday, got %s", goal.Requires(monday))
+	}
+	if goal.Requires(tuesday) != 4*time.Hour {
+		t.Fatalf("expected half requirement on half-day, got %s", goal.Requires(tuesday))
+	}
+	if goal.Requires(wednesday) != 8*time.Hour {
+		t.Fatalf("expected full requirement on regular day, got %s", goal.Requires(wednesday))
+	}
+
+	absences.Unset(monday)
+	if goal.Requires(monday) != 8*time.Hour {
+		t.Fatalf("expected full requirement after unsetting absence, got %s", goal.Requires(monday))
+	}
+
+	nilAdjusted := &AbsenceAdjustedGoal{Goal: goal.Goal}
+	if nilAdjusted.Requires(monday) != 8*time.Hour {
+		t.Fatalf("expected full requirement without absences, got %s", nilAdjusted.Requires(monday))
+	}
+}
+
+func TestDatedAbsenceFromString(t *testing.T) {
+	{
+		testcase := "round trip with note containing separator"
+		absence, err := NewDatedAbsenceFromString("2022-12-26|holiday|Boxing Day | St. Stephen's")
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if absence.Date != (Date{2022, 12, 26}) || absence.Kind != AbsenceHoliday || absence.Note != "Boxing Day | St. Stephen's" {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, absence)
+		}
+		if absence.ToString() != "2022-12-26|holiday|Boxing Day | St. Stephen's" {
+			t.Fatalf("testcase '%s' failed: got '%s'", testcase, absence.ToString())
+		}
+	}
+	{
+		testcase := "without note"
+		absence, err := NewDatedAbsenceFromString("2022-12-27|sick")
+		if err != nil || absence.Kind != AbsenceSick || absence.Note != "" {
+			t.Fatalf("testcase '%s' failed: got %v, %v", testcase, absence, err)
+		}
+	}
+	for _, malformed := range []string{"2022-12-27", "2022-12-27|party", "yesterday|sick"} {
+		_, err := NewDatedAbsenceFromString(malformed)
+		if err == nil {
+			t.Fatalf("expected error for malformed absence '%s'", malformed)
+		}
+	}
+}
+
+func TestPeriodGoals(t *testing.T) {
+	monday := Date{Year: 2022, Month: 12, Day: 5}
+	_, sunday := monday.WeekBounds()
+	{
+		testcase := "weekly goal adds up over the week"
+		goal := &WeeklyGoal{Time: 40 * time.Hour}
+		if goal.Requires(monday) != 8*time.Hour || goal.Requires(sunday) != 0 || GoalForRange(goal, monday, sunday) != 40*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %s for monday, %s for sunday", testcase, goal.Requires(monday), goal.Requires(sunday))
+		}
+	}
+	{
+		testcase := "monthly goal adds up over the month"
+		goal := &MonthlyGoal{Time: 88 * time.Hour} // december 2022 has 22 workdays
+		first, last := monday.MonthBounds()
+		if goal.Requires(monday) != 4*time.Hour || GoalForRange(goal, first, last) != 88*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %s for monday, %s for month", testcase, goal.Requires(monday), GoalForRange(goal, first, last))
+		}
+	}
+	{
+		testcase := "daily goal"
+		goal := &DailyGoal{Time: time.Hour}
+		if GoalForRange(goal, monday, sunday) != 7*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %s", testcase, GoalForRange(goal, monday, sunday))
+		}
+	}
+}
+
+func TestLimitForRange(t *testing.T) {
+	monday := Date{Year: 2022, Month: 12, Day: 5}
+	_, sunday := monday.WeekBounds()
+	{
+		testcase := "weekly limit on a single day"
+		limit := &WeeklyGoal{Time: 5 * time.Hour}
+		if LimitForRange(limit, sunday, sunday) != 5*time.Hour || LimitForRange(limit, monday, monday) != 5*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %s for sunday, %s for monday", testcase, LimitForRange(limit, sunday, sunday), LimitForRange(limit, monday, monday))
+		}
+	}
+	{
+		testcase := "weekly limit over partial weeks"
+		limit := &WeeklyGoal{Time: 5 * time.Hour}
+		if got := LimitForRange(limit, sunday, sunday.Next()); got != 10*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %s", testcase, got)
+		}
+	}
+	{
+		testcase := "monthly limit over parts of two months"
+		limit := &MonthlyGoal{Time: 20 * time.Hour}
+		_, last := monday.MonthBounds()
+		if got := LimitForRange(limit, monday, last.Next()); got != 40*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %s", testcase, got)
+		}
+	}
+	{
+		testcase := "daily limit"
+		limit := &DailyGoal{Time: time.Hour}
+		if got := LimitForRange(limit, monday, sunday); got != 7*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %s", testcase, got)
+		}
+	}
+}
+
+func TestNewGoalFromConfig(t *testing.T) {
+	{
+		testcase := "none"
+		goal, err := NewGoalFromConfig(config.Goal{})
+		if goal != nil || err != nil {
+			t.Fatalf("testcase '%s' failed: got %v, %v", testcase, goal, err)
+		}
+	}
+	{
+		testcase := "weekly"
+		goal, err := NewGoalFromConfig(config.Goal{Weekly: "5h"})
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if weekly, ok := goal.(*WeeklyGoal); !ok || weekly.Time != 5*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, goal)
+		}
+	}
+	{
+		testcase := "multiple kinds"
+		_, err := NewGoalFromConfig(config.Goal{Weekly: "5h", Daily: "1h"})
+		if err == nil {
+			t.Fatalf("testcase '%s' failed: expected error", testcase)
+		}
+	}
+	{
+		testcase := "invalid duration"
+		_, err := NewGoalFromConfig(config.Goal{Monthly: "lots"})
+		if err == nil {
+			t.Fatalf("testcase '%s' failed: expected error", testcase)
+		}
+	}
+}
+
+func TestPatternGoal(t *testing.T) {
+	categories := []Category{{Name: "work", Priority: 1}, {Name: "leisure", Priority: 0}}
+	day := NewDayWithEvents([]*Event{
+		NewEvent("09:00|11:00|work|Refactoring #deep", categories),
+		NewEvent("10:00|12:00|leisure|Reading #deep", categories),
+		NewEvent("13:00|14:00|work|Meeting #deeper", categories),
+	})
+
+	{
+		testcase := "tag"
+		goal, err := NewPatternGoalFromConfig(config.PatternGoal{Name: "deep work", Tag: "deep", Goal: config.Goal{Daily: "4h"}})
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		// flattening cuts the leisure event to 11:00-12:00
+		if minutes := day.SumUpMatching(goal.Matches); minutes != 180 {
+			t.Fatalf("testcase '%s' failed: expected 180 minutes, got %d", testcase, minutes)
+		}
+	}
+	{
+		testcase := "pattern restricted to category"
+		goal, err := NewPatternGoalFromConfig(config.PatternGoal{Name: "work deep", Pattern: "#deep", Category: "work", Limit: config.Goal{Daily: "1h"}})
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if minutes := day.SumUpMatching(goal.Matches); minutes != 180 || goal.Goal != nil || goal.Limit == nil {
+			t.Fatalf("testcase '%s' failed: got %d minutes, %v", testcase, minutes, goal)
+		}
+	}
+	for _, invalid := range []config.PatternGoal{
+		{Tag: "deep", Goal: config.Goal{Daily: "1h"}},
+		{Name: "x", Goal: config.Goal{Daily: "1h"}},
+		{Name: "x", Tag: "a", Pattern: "b", Goal: config.Goal{Daily: "1h"}},
+		{Name: "x", Pattern: "(", Goal: config.Goal{Daily: "1h"}},
+		{Name: "x", Tag: "a"},
+	} {
+		_, err := NewPatternGoalFromConfig(invalid)
+		if err == nil {
+			t.Fatalf("expected error for invalid pattern goal %v", invalid)
+		}
+	}
+}
@@ -20,27 +20,36 @@ func NewTimestampFromGotime(time time.Time) *Timestamp {
 }
 
 func NewTimestamp(s string) *Timestamp {
+	t, err := TimestampFromString(s)
+	if err != nil {
+		log.Fatalf("error with string-to-timestamp conversion: %s", err.Error())
+	}
+	return &t
+}
+
+// TimestampFromString parses a timestamp from a string in the format "HH:MM".
+func TimestampFromString(s string) (Timestamp, error) {
 	components := strings.Split(s, ":")
 	if len(components) != 2 {
-		log.Fatalf("given string '%s' which does not fit the HH:MM format", s)
+		return Timestamp{}, fmt.Errorf("given string '%s' which does not fit the HH:MM format", s)
 	}
 	hStr := components[0]
 	mStr := components[1]
 	if len(hStr) != 2 || len(mStr) != 2 {
-		log.Fatalf("given string '%s' which does not fit the HH:MM format", s)
+		return Timestamp{}, fmt.Errorf("given string '%s' which does not fit the HH:MM format", s)
 	}
 	h, err := strconv.Atoi(hStr)
 	if err != nil {
-		log.Fatalf("error converting hour string '%s' to a number", hStr)
+		return Timestamp{}, fmt.Errorf("error converting hour string '%s' to a number", hStr)
 	}
 	m, err := strconv.Atoi(mStr)
 	if err != nil {
-		log.Fatalf("error converting minute string '%s' to a number", mStr)
+		return Timestamp{}, fmt.Errorf("error converting minute string '%s' to a number", mStr)
 	}
 	if h < 0 || h > 23 || m < 0 || m > 59 {
-		log.Fatalf("error with string-to-timestamp conversion: one of the yielded values illegal (%d) (%d)", h, m)
+		return Timestamp{}, fmt.Errorf("one of the values in '%s' illegal (%d) (%d)", s, h, m)
 	}
-	return &Timestamp{h, m}
+	return Timestamp{h, m}, nil
 }
 
 func (a Timestamp) ToString() string {
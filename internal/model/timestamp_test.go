@@ -104,3 +104,25 @@ func TestOffset(t *testing.T) {
 		}
 	}
 }
+
+func TestTimestampFromString(t *testing.T) {
+	for input, expected := range map[string]Timestamp{
+		"00:00": {0, 0},
+		"09:05": {9, 5},
+		"23:59": {23, 59},
+	} {
+		result, err := TimestampFromString(input)
+		if err != nil {
+			t.Fatalf("parsing '%s' failed with error: %s", input, err.Error())
+		}
+		if result != expected {
+			t.Fatalf("parsing '%s' should yield %s, but yielded %s", input, expected.ToString(), result.ToString())
+		}
+	}
+	for _, input := range []string{"", "9:05", "09:5", "0905", "24:00", "12:60", "ab:cd", "12:00:00"} {
+		_, err := TimestampFromString(input)
+		if err == nil {
+			t.Fatalf("parsing '%s' should fail, but did not", input)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package progress provides the progress of categories towards their goals
+// over a range of days, and projections of whether a week's goal is still
+// reachable.
+package progress
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// DefaultMaxPerDay is the default for the most time per day that is
+// considered feasible to spend on a goal when projecting whether it is
+// reachable.
+const DefaultMaxPerDay = 10 * time.Hour
+
+// Progress is the progress of a category towards its goal over a range.
+type Progress struct {
+	Category model.Category
+	// Goal is the duration required by the category's goal over the range.
+	Goal time.Duration
+	// Done is the duration spent on the category in the range.
+	Done time.Duration
+}
+
+// Remaining returns the time still required to reach the goal, which is 0 if
+// the goal is reached.
+func (p Progress) Remaining() time.Duration {
+	if p.Done >= p.Goal {
+		return 0
+	}
+	return p.Goal - p.Done
+}
+
+// Fraction returns the fraction of the goal that is done (which exceeds 1 if
+// more than required is done); a zero goal counts as done.
+func (p Progress) Fraction() float64 {
+	if p.Goal == 0 {
+		return 1
+	}
+	return float64(p.Done) / float64(p.Goal)
+}
+
+// Compute returns the progress of each of the given categories that have a
+// goal, over the given days (the consecutive days starting at from, nil days
+// are treated as empty), sorted by category name.
+func Compute(from model.Date, days []*model.Day, categories []model.Category) []Progress {
+	done := make(map[string]time.Duration)
+	for _, day := range days {
+		if day == nil {
+			continue
+		}
+		for cat, minutes := range day.SumUpByCategory() {
+			done[cat.Name] += time.Duration(minutes) * time.Minute
+		}
+	}
+
+	result := make([]Progress, 0)
+	if len(days) == 0 {
+		return result
+	}
+	til := from.Forward(len(days) - 1)
+	for _, cat := range categories {
+		if cat.Goal == nil {
+			continue
+		}
+		result = append(result, Progress{
+			Category: cat,
+			Goal:     model.GoalForRange(cat.Goal, from, til),
+			Done:     done[cat.Name],
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Category.Name < result[j].Category.Name })
+	return result
+}
+
+// DayFulfillment returns the time required by the given categories' goals on
+// the given day and how much of it is done, where time spent on a category
+// beyond its goal does not count towards the other categories' goals.
+func DayFulfillment(date model.Date, day *model.Day, categories []model.Category) (done, goal time.Duration) {
+	for _, goalProgress := range Compute(date, []*model.Day{day}, categories) {
+		goal += goalProgress.Goal
+		done += goalProgress.Goal - goalProgress.Remaining()
+	}
+	return done, goal
+}
+
+// Projection is the projection of whether a week's goal is still reachable.
+type Projection struct {
+	// Remaining is the time still required for the week's goal.
+	Remaining time.Duration
+	// DaysLeft is the number of days left in the week (including today) on
+	// which the goal requires time.
+	DaysLeft int
+	// PerDay is the remaining time spread evenly over the days left.
+	PerDay time.Duration
+	// Reachable is whether the goal is reached already or can still be reached
+	// without spending more than the maximum per day on any of the days left.
+	Reachable bool
+}
+
+// ProjectWeek projects whether the goal of the week containing weekOf is still
+// reachable, given the time done in the week so far, the current date and the
+// most time per day that is considered feasible.
+func ProjectWeek(goal model.Goal, done time.Duration, weekOf, today model.Date, maxPerDay time.Duration) Projection {
+	monday, sunday := weekOf.WeekBounds()
+
+	result := Projection{}
+	if required := model.GoalForRange(goal, monday, sunday); done < required {
+		result.Remaining = required - done
+	}
+
+	first := monday
+	if today.IsAfter(monday) {
+		first = today
+	}
+	for current := first; !current.IsAfter(sunday); current = current.Next() {
+		if goal.Requires(current) > 0 {
+			result.DaysLeft++
+		}
+	}
+
+	switch {
+	case result.Remaining == 0:
+		result.Reachable = true
+	case result.DaysLeft > 0:
+		result.PerDay = result.Remaining / time.Duration(result.DaysLeft)
+		result.Reachable = result.PerDay <= maxPerDay
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package progress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func TestCompute(t *testing.T) {
+	categories := []model.Category{
+		{Name: "work", Priority: 1, Goal: &model.WeeklyGoal{Time: 40 * time.Hour}},
+		{Name: "eating"},
+		{Name: "exercise", Goal: &model.DailyGoal{Time: 30 * time.Minute}},
+	}
+	monday := model.Date{Year: 2022, Month: 12, Day: 5}
+	days := []*model.Day{
+		model.NewDayWithEvents([]*model.Event{
+			model.NewEvent("09:00|17:00|work|Coding", categories),
+			model.NewEvent("12:00|12:30|eating|Lunch", categories),
+		}),
+		nil,
+	}
+
+	progress := Compute(monday, days, categories)
+	if len(progress) != 2 ||
+		progress[0].Category.Name != "exercise" || progress[0].Goal != time.Hour || progress[0].Done != 0 || progress[0].Remaining() != time.Hour ||
+		progress[1].Category.Name != "work" || progress[1].Goal != 16*time.Hour || progress[1].Done != 8*time.Hour || progress[1].Fraction() != 0.5 {
+		t.Fatalf("unexpected progress: %v", progress)
+	}
+
+	if len(Compute(monday, nil, categories)) != 0 {
+		t.Fatalf("expected no progress for no days")
+	}
+
+	done := Progress{Goal: time.Hour, Done: 2 * time.Hour}
+	if done.Remaining() != 0 || done.Fraction() != 2 {
+		t.Fatalf("unexpected remaining %s or fraction %f for exceeded goal", done.Remaining(), done.Fraction())
+	}
+}
+
+func TestDayFulfillment(t *testing.T) {
+	categories := []model.Category{
+		{Name: "work", Goal: &model.DailyGoal{Time: 2 * time.Hour}},
+		{Name: "exercise", Goal: &model.DailyGoal{Time: time.Hour}},
+		{Name: "eating"},
+	}
+	monday := model.Date{Year: 2022, Month: 12, Day: 5}
+	day := model.NewDayWithEvents([]*model.Event{
+		model.NewEvent("09:00|13:00|work|Coding", categories),
+		model.NewEvent("13:00|13:30|eating|Lunch", categories),
+	})
+
+	// the work beyond its goal does not make up for the missing exercise
+	done, goal := DayFulfillment(monday, day, categories)
+	if done != 2*time.Hour || goal != 3*time.Hour {
+		t.Fatalf("expected 2h of 3h done, got %s of %s", done, goal)
+	}
+
+	done, goal = DayFulfillment(monday, nil, categories)
+	if done != 0 || goal != 3*time.Hour {
+		t.Fatalf("expected 0 of 3h done for no day, got %s of %s", done, goal)
+	}
+}
+
+func TestProjectWeek(t *testing.T) {
+	goal := &model.WeeklyGoal{Time: 40 * time.Hour}
+	monday := model.Date{Year: 2022, Month: 12, Day: 5}
+	thursday := monday.Forward(3)
+	_, sunday := monday.WeekBounds()
+
+	{
+		testcase := "reachable"
+		projection := ProjectWeek(goal, 24*time.Hour, monday, thursday, DefaultMaxPerDay)
+		if projection.Remaining != 16*time.Hour || projection.DaysLeft != 2 || projection.PerDay != 8*time.Hour || !projection.Reachable {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, projection)
+		}
+	}
+	{
+		testcase := "unreachable"
+		projection := ProjectWeek(goal, 10*time.Hour, thursday, thursday, DefaultMaxPerDay)
+		if projection.Remaining != 30*time.Hour || projection.PerDay != 15*time.Hour || projection.Reachable {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, projection)
+		}
+	}
+	{
+		testcase := "past week, reached"
+		projection := ProjectWeek(goal, 41*time.Hour, monday, sunday.Next(), DefaultMaxPerDay)
+		if projection.Remaining != 0 || projection.DaysLeft != 0 || !projection.Reachable {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, projection)
+		}
+	}
+	{
+		testcase := "past week, missed"
+		projection := ProjectWeek(goal, 39*time.Hour, monday, sunday.Next(), DefaultMaxPerDay)
+		if projection.Remaining != time.Hour || projection.Reachable {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, projection)
+		}
+	}
+	{
+		testcase := "future week"
+		projection := ProjectWeek(goal, 0, sunday.Next(), monday, DefaultMaxPerDay)
+		if projection.DaysLeft != 5 || projection.PerDay != 8*time.Hour || !projection.Reachable {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, projection)
+		}
+	}
+}
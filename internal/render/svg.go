@@ -0,0 +1,153 @@
+// Package render provides rendering of days to formats outside of the
+// terminal UI, e.g., SVG images or plain text.
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/ui"
+)
+
+// Day is a day to be rendered, along with its date and (optionally) its
+// sunrise and sunset times.
+type Day struct {
+	Date     model.Date
+	Day      *model.Day
+	SunTimes *model.SunTimes
+}
+
+// SVGOptions are the options for rendering days to SVG.
+type SVGOptions struct {
+	// FromHour and TilHour define the range of the day that is rendered, e.g.,
+	// 0 and 24 for the entire day.
+	FromHour, TilHour int
+	// HourHeight is the height of an hour in pixels.
+	HourHeight float64
+	// DayWidth is the width of a single day's column in pixels.
+	DayWidth float64
+	// ShowCategories defines whether to render the category names of events.
+	ShowCategories bool
+}
+
+// DefaultSVGOptions returns the default SVG rendering options.
+func DefaultSVGOptions() SVGOptions {
+	return SVGOptions{
+		FromHour:       0,
+		TilHour:        24,
+		HourHeight:     40,
+		DayWidth:       180,
+		ShowCategories: true,
+	}
+}
+
+const (
+	svgAxisWidth    = 48.0
+	svgHeaderHeight = 32.0
+	svgFontSize     = 11.0
+	svgNightColor   = "#222255"
+	svgNightOpacity = 0.12
+	svgGridColor    = "#e0e0e0"
+	svgTextColor    = "#404040"
+	svgPadding      = 2.0
+)
+
+// WriteSVG renders the given days next to each other to an SVG image, laying
+// out events the same way the TUI does (see ui.EventStackDepths), and writes
+// it to the given writer.
+//
+// The colors function provides the foreground and background colors (in hex
+// notation) for an event's category.
+func WriteSVG(w io.Writer, days []Day, colors func(model.Category) (fg, bg string), opts SVGOptions) error {
+	if opts.TilHour <= opts.FromHour || opts.FromHour < 0 || opts.TilHour > 24 {
+		return fmt.Errorf("invalid hour range %d..%d", opts.FromHour, opts.TilHour)
+	}
+
+	fromMinutes := opts.FromHour * 60
+	tilMinutes := opts.TilHour * 60
+	yFor := func(t model.Timestamp) float64 {
+		minutes := t.Hour*60 + t.Minute
+		if minutes < fromMinutes {
+			minutes = fromMinutes
+		}
+		if minutes > tilMinutes {
+			minutes = tilMinutes
+		}
+		return svgHeaderHeight + float64(minutes-fromMinutes)/60*opts.HourHeight
+	}
+
+	width := svgAxisWidth + float64(len(days))*opts.DayWidth
+	height := svgHeaderHeight + float64(opts.TilHour-opts.FromHour)*opts.HourHeight
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="sans-serif" font-size="%.0f">`+"\n", width, height, width, height, svgFontSize)
+	fmt.Fprintf(b, `<rect x="0" y="0" width="%.0f" height="%.0f" fill="#ffffff"/>`+"\n", width, height)
+
+	// time axis and grid
+	for hour := opts.FromHour; hour <= opts.TilHour; hour++ {
+		y := svgHeaderHeight + float64(hour-opts.FromHour)*opts.HourHeight
+		fmt.Fprintf(b, `<line x1="%.1f" x2="%.1f" y1="%.1f" y2="%.1f" stroke="%s"/>`+"\n", svgAxisWidth, width, y, y, svgGridColor)
+		if hour < 24 {
+			fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="end" dominant-baseline="hanging" fill="%s">%02d:00</text>`+"\n", svgAxisWidth-4, y+2, svgTextColor, hour)
+		}
+	}
+
+	for i, day := range days {
+		colX := svgAxisWidth + float64(i)*opts.DayWidth
+
+		// header
+		header := fmt.Sprintf("%s %s", day.Date.ToWeekday().String()[:3], day.Date.ToString())
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="middle" font-weight="bold" fill="%s">%s</text>`+"\n", colX+opts.DayWidth/2, svgHeaderHeight/2, svgTextColor, escape(header))
+		fmt.Fprintf(b, `<line x1="%.1f" x2="%.1f" y1="%.1f" y2="%.1f" stroke="%s"/>`+"\n", colX, colX, svgHeaderHeight, height, svgGridColor)
+
+		// night shading
+		if day.SunTimes != nil {
+			riseY, setY := yFor(day.SunTimes.Rise), yFor(day.SunTimes.Set)
+			fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" fill-opacity="%.2f"/>`+"\n", colX, svgHeaderHeight, opts.DayWidth, riseY-svgHeaderHeight, svgNightColor, svgNightOpacity)
+			fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" fill-opacity="%.2f"/>`+"\n", colX, setY, opts.DayWidth, height-setY, svgNightColor, svgNightOpacity)
+		}
+
+		if day.Day == nil {
+			continue
+		}
+
+		stackDepths := ui.EventStackDepths(day.Day.Events)
+		innerX := colX + svgPadding
+		innerW := opts.DayWidth - 2*svgPadding
+		for _, e := range day.Day.Events {
+			y := yFor(e.Start)
+			h := yFor(e.End) - y
+			if h <= 0 {
+				continue
+			}
+			w := innerW * ui.EventWidthFactor(stackDepths[e])
+			x := innerX + (innerW - w)
+			fg, bg := colors(e.Cat)
+
+			title := fmt.Sprintf("%s-%s %s (%s)", e.Start.ToString(), e.End.ToString(), e.Name, e.Cat.Name)
+			fmt.Fprintf(b, `<g><title>%s</title>`+"\n", escape(title))
+			fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="#ffffff" stroke-width="0.5"/>`+"\n", x, y, w, h, bg)
+			// nested svg to clip the text to the event's box
+			fmt.Fprintf(b, `<svg x="%.1f" y="%.1f" width="%.1f" height="%.1f">`+"\n", x, y, w, h)
+			fmt.Fprintf(b, `<text x="3" y="2" dominant-baseline="hanging" fill="%s">%s</text>`+"\n", fg, escape(e.Name))
+			fmt.Fprintf(b, `<text x="%.1f" y="2" text-anchor="end" dominant-baseline="hanging" fill="%s" fill-opacity="0.7">%s</text>`+"\n", w-3, fg, e.Start.ToString())
+			if opts.ShowCategories && h >= 2*(svgFontSize+2) {
+				fmt.Fprintf(b, `<text x="3" y="%.1f" dominant-baseline="hanging" font-style="italic" fill="%s" fill-opacity="0.7">%s</text>`+"\n", svgFontSize+4, fg, escape(e.Cat.Name))
+			}
+			fmt.Fprintf(b, "</svg>\n</g>\n")
+		}
+	}
+
+	fmt.Fprintf(b, "</svg>\n")
+	return b.Flush()
+}
+
+func escape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
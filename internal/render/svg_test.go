@@ -0,0 +1,65 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func TestWriteSVG(t *testing.T) {
+	day := model.NewDayWithEvents([]*model.Event{
+		model.NewEvent("09:00|12:00|work|Coding & <Review>", nil),
+		model.NewEvent("10:00|11:00|meeting|Standup", nil),
+	})
+	colors := func(model.Category) (string, string) { return "#000000", "#ccebff" }
+	opts := SVGOptions{FromHour: 8, TilHour: 18, HourHeight: 10, DayWidth: 104}
+
+	{
+		testcase := "valid xml with escaped names"
+		var buf bytes.Buffer
+		err := WriteSVG(&buf, []Day{{Date: model.Date{Year: 2022, Month: 12, Day: 5}, Day: day}}, colors, opts)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		decoder := xml.NewDecoder(&buf)
+		for {
+			_, err := decoder.Token()
+			if err != nil {
+				if err != io.EOF {
+					t.Fatalf("testcase '%s' failed, invalid xml: %s", testcase, err.Error())
+				}
+				break
+			}
+		}
+	}
+	{
+		testcase := "stacked event is narrower and right-aligned"
+		var buf bytes.Buffer
+		err := WriteSVG(&buf, []Day{{Date: model.Date{Year: 2022, Month: 12, Day: 5}, Day: day}}, colors, opts)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		// column at x=48, padding 2, inner width 100; 09:00 is y=32+10
+		for _, expected := range []string{
+			`<rect x="50.0" y="42.0" width="100.0" height="30.0"`,
+			`<rect x="75.0" y="52.0" width="75.0" height="10.0"`,
+			`Coding &amp; &lt;Review&gt;`,
+		} {
+			if !strings.Contains(buf.String(), expected) {
+				t.Fatalf("testcase '%s' failed: output does not contain '%s'", testcase, expected)
+			}
+		}
+	}
+	{
+		testcase := "invalid hour range"
+		var buf bytes.Buffer
+		err := WriteSVG(&buf, nil, colors, SVGOptions{FromHour: 10, TilHour: 10, HourHeight: 10, DayWidth: 10})
+		if err == nil {
+			t.Fatalf("testcase '%s' failed: expected error", testcase)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/ui"
+	"github.com/ja-he/dayplan/internal/util"
+)
+
+// TextOptions are the options for rendering days as a plain-text grid.
+type TextOptions struct {
+	// FromHour and TilHour define the range of the day that is rendered, e.g.,
+	// 0 and 24 for the entire day.
+	FromHour, TilHour int
+	// RowsPerHour is the number of lines an hour takes up.
+	RowsPerHour int
+	// ColumnWidth is the width of a single day's column in characters.
+	ColumnWidth int
+}
+
+// DefaultTextOptions returns the default text grid rendering options.
+func DefaultTextOptions() TextOptions {
+	return TextOptions{
+		FromHour:    6,
+		TilHour:     22,
+		RowsPerHour: 2,
+		ColumnWidth: 18,
+	}
+}
+
+const textAxisWidth = 6
+
+// WriteAgenda writes the events of the given days as an agenda, i.e., a list
+// of the days with the time range, category, name and duration of each event,
+// followed by the day's (flattened) total.
+//
+// If colors is not nil, category names are colored using ANSI escape
+// sequences with the foreground and background colors (in hex notation) it
+// provides.
+func WriteAgenda(w io.Writer, days []Day, colors func(model.Category) (fg, bg string)) error {
+	catWidth, nameWidth := 0, 0
+	for _, day := range days {
+		if day.Day == nil {
+			continue
+		}
+		for _, e := range day.Day.Events {
+			if n := len([]rune(e.Cat.Name)); n > catWidth {
+				catWidth = n
+			}
+			if n := len([]rune(e.Name)); n > nameWidth {
+				nameWidth = n
+			}
+		}
+	}
+
+	b := bufio.NewWriter(w)
+	for i, day := range days {
+		if i > 0 {
+			fmt.Fprintln(b)
+		}
+		fmt.Fprintf(b, "%s %s\n", day.Date.ToWeekday().String()[:3], day.Date.ToString())
+
+		if day.Day == nil || len(day.Day.Events) == 0 {
+			fmt.Fprintf(b, "  (no events)\n")
+			continue
+		}
+
+		for _, e := range day.Day.Events {
+			cat := padRight(e.Cat.Name, catWidth)
+			if colors != nil {
+				cat = ansiStyle(colors(e.Cat)) + cat + ansiReset
+			}
+			fmt.Fprintf(b, "  %s-%s  %s  %s  %s\n",
+				e.Start.ToString(), e.End.ToString(),
+				cat,
+				padRight(e.Name, nameWidth),
+				util.DurationToString(e.Duration()),
+			)
+		}
+
+		total := 0
+		for _, minutes := range day.Day.SumUpByCategory() {
+			total += minutes
+		}
+		fmt.Fprintf(b, "  total: %s\n", util.DurationToString(total))
+	}
+	return b.Flush()
+}
+
+// WriteGrid renders the given days next to each other as a plain-text grid
+// with a time axis, similar to the TUI's week view, laying out events the same
+// way the TUI does (see ui.EventStackDepths).
+//
+// If colors is not nil, events are colored using ANSI escape sequences with
+// the foreground and background colors (in hex notation) it provides;
+// otherwise event boxes are marked by a '|' on their left edge.
+func WriteGrid(w io.Writer, days []Day, colors func(model.Category) (fg, bg string), opts TextOptions) error {
+	if opts.TilHour <= opts.FromHour || opts.FromHour < 0 || opts.TilHour > 24 {
+		return fmt.Errorf("invalid hour range %d..%d", opts.FromHour, opts.TilHour)
+	}
+	if opts.RowsPerHour < 1 || opts.RowsPerHour > 60 {
+		return fmt.Errorf("invalid rows per hour %d", opts.RowsPerHour)
+	}
+	if opts.ColumnWidth < 4 {
+		return fmt.Errorf("invalid column width %d", opts.ColumnWidth)
+	}
+
+	fromMinutes := opts.FromHour * 60
+	nRows := (opts.TilHour - opts.FromHour) * opts.RowsPerHour
+	rowFor := func(t model.Timestamp, roundUp bool) int {
+		offset := (t.Hour*60 + t.Minute - fromMinutes) * opts.RowsPerHour
+		row := offset / 60
+		if roundUp && offset%60 > 0 {
+			row++
+		}
+		switch {
+		case row < 0:
+			return 0
+		case row > nRows:
+			return nRows
+		default:
+			return row
+		}
+	}
+
+	type cell struct {
+		r     rune
+		style string
+	}
+	width := textAxisWidth + len(days)*(opts.ColumnWidth+1)
+	grid := make([][]cell, nRows)
+	for row := range grid {
+		grid[row] = make([]cell, width)
+		filler := ' '
+		if row%opts.RowsPerHour == 0 {
+			filler = '.'
+		}
+		for col := range grid[row] {
+			grid[row][col] = cell{r: filler}
+		}
+		if row%opts.RowsPerHour == 0 {
+			label := fmt.Sprintf("%02d:00", opts.FromHour+row/opts.RowsPerHour)
+			for i, r := range label {
+				grid[row][i] = cell{r: r}
+			}
+			grid[row][textAxisWidth-1] = cell{r: ' '}
+		} else {
+			for col := 0; col < textAxisWidth; col++ {
+				grid[row][col] = cell{r: ' '}
+			}
+		}
+	}
+
+	header := strings.Repeat(" ", textAxisWidth)
+	for i, day := range days {
+		colX := textAxisWidth + i*(opts.ColumnWidth+1)
+		for row := range grid {
+			grid[row][colX+opts.ColumnWidth] = cell{r: ' '}
+		}
+
+		label := fmt.Sprintf("%s %s", day.Date.ToWeekday().String()[:3], day.Date.ToString())
+		if len(label) > opts.ColumnWidth {
+			label = fmt.Sprintf("%s %02d-%02d", day.Date.ToWeekday().String()[:3], day.Date.Month, day.Date.Day)
+		}
+		header += padRight(util.TruncateAt(label, opts.ColumnWidth), opts.ColumnWidth+1)
+
+		if day.Day == nil {
+			continue
+		}
+
+		stackDepths := ui.EventStackDepths(day.Day.Events)
+		for _, e := range day.Day.Events {
+			startRow, endRow := rowFor(e.Start, false), rowFor(e.End, true)
+			if startRow == nRows || endRow == 0 || !e.End.IsAfter(e.Start) {
+				continue
+			}
+			if endRow <= startRow {
+				endRow = startRow + 1
+			}
+			w := int(float64(opts.ColumnWidth) * ui.EventWidthFactor(stackDepths[e]))
+			x := colX + (opts.ColumnWidth - w)
+
+			style := ""
+			if colors != nil {
+				style = ansiStyle(colors(e.Cat))
+			}
+			for row := startRow; row < endRow; row++ {
+				for col := x; col < x+w; col++ {
+					grid[row][col] = cell{r: ' ', style: style}
+				}
+				if colors == nil {
+					grid[row][x] = cell{r: '|'}
+				}
+			}
+			textX, textW := x, w
+			if colors == nil {
+				textX, textW = x+1, w-1
+			}
+			for i, r := range []rune(util.TruncateAt(e.Name, textW)) {
+				grid[startRow][textX+i] = cell{r: r, style: style}
+			}
+		}
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, strings.TrimRight(header, " "))
+	for _, row := range grid {
+		var line strings.Builder
+		currentStyle := ""
+		for _, c := range row {
+			if c.style != currentStyle {
+				if currentStyle != "" {
+					line.WriteString(ansiReset)
+				}
+				line.WriteString(c.style)
+				currentStyle = c.style
+			}
+			line.WriteRune(c.r)
+		}
+		if currentStyle != "" {
+			line.WriteString(ansiReset)
+		}
+		fmt.Fprintln(b, strings.TrimRight(line.String(), " "))
+	}
+	return b.Flush()
+}
+
+const ansiReset = "\x1b[0m"
+
+// ansiStyle returns the ANSI escape sequence setting the given (hex notation)
+// foreground and background colors as 24-bit colors. Invalid colors are
+// omitted.
+func ansiStyle(fg, bg string) string {
+	var s string
+	if r, g, b, ok := parseHexColor(fg); ok {
+		s += fmt.Sprintf("\x1b[38;2;%d;%d;%dm", r, g, b)
+	}
+	if r, g, b, ok := parseHexColor(bg); ok {
+		s += fmt.Sprintf("\x1b[48;2;%d;%d;%dm", r, g, b)
+	}
+	return s
+}
+
+func parseHexColor(hex string) (r, g, b uint8, ok bool) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return 0, 0, 0, false
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(value >> 16), uint8(value >> 8), uint8(value), true
+}
+
+func padRight(s string, length int) string {
+	n := len([]rune(s))
+	if n >= length {
+		return s
+	}
+	return s + strings.Repeat(" ", length-n)
+}
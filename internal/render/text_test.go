@@ -0,0 +1,100 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func TestWriteAgenda(t *testing.T) {
+	monday := model.Date{Year: 2022, Month: 12, Day: 5}
+	days := []Day{
+		{
+			Date: monday,
+			Day: model.NewDayWithEvents([]*model.Event{
+				model.NewEvent("09:00|12:00|work|Coding", nil),
+				model.NewEvent("10:00|11:00|meeting|Standup", nil),
+			}),
+		},
+		{Date: monday.Next(), Day: nil},
+	}
+
+	{
+		testcase := "plain agenda"
+		var buf bytes.Buffer
+		err := WriteAgenda(&buf, days, nil)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		expected := strings.Join([]string{
+			"Mon 2022-12-05",
+			"  09:00-12:00  work     Coding   3h 0min",
+			"  10:00-11:00  meeting  Standup  1h 0min",
+			"  total: 3h 0min",
+			"",
+			"Tue 2022-12-06",
+			"  (no events)",
+			"",
+		}, "\n")
+		if buf.String() != expected {
+			t.Fatalf("testcase '%s' failed: expected\n%s\ngot\n%s", testcase, expected, buf.String())
+		}
+	}
+	{
+		testcase := "colored agenda"
+		var buf bytes.Buffer
+		err := WriteAgenda(&buf, days, func(model.Category) (string, string) { return "#000000", "#ccebff" })
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if !strings.Contains(buf.String(), "\x1b[38;2;0;0;0m\x1b[48;2;204;235;255mwork   \x1b[0m") {
+			t.Fatalf("testcase '%s' failed: no colored category in output:\n%q", testcase, buf.String())
+		}
+	}
+}
+
+func TestWriteGrid(t *testing.T) {
+	day := model.NewDayWithEvents([]*model.Event{
+		model.NewEvent("09:00|10:00|work|Coding", nil),
+		model.NewEvent("09:30|10:00|meeting|Standup", nil),
+	})
+	opts := TextOptions{FromHour: 9, TilHour: 11, RowsPerHour: 2, ColumnWidth: 12}
+
+	{
+		testcase := "plain grid"
+		var buf bytes.Buffer
+		err := WriteGrid(&buf, []Day{{Date: model.Date{Year: 2022, Month: 12, Day: 5}, Day: day}}, nil, opts)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		expected := strings.Join([]string{
+			"      Mon 12-05",
+			"09:00 |Coding",
+			"      |  |Standup",
+			"10:00 ............",
+			"",
+			"",
+		}, "\n")
+		if buf.String() != expected {
+			t.Fatalf("testcase '%s' failed: expected\n%s\ngot\n%s", testcase, expected, buf.String())
+		}
+	}
+	{
+		testcase := "no escape sequences without colors"
+		var buf bytes.Buffer
+		_ = WriteGrid(&buf, []Day{{Date: model.Date{Year: 2022, Month: 12, Day: 5}, Day: day}}, nil, opts)
+		if strings.Contains(buf.String(), "\x1b") {
+			t.Fatalf("testcase '%s' failed: output contains escape sequences", testcase)
+		}
+	}
+	{
+		testcase := "invalid options"
+		var buf bytes.Buffer
+		err := WriteGrid(&buf, nil, nil, TextOptions{FromHour: 9, TilHour: 11, RowsPerHour: 0, ColumnWidth: 12})
+		if err == nil {
+			t.Fatalf("testcase '%s' failed: expected error", testcase)
+		}
+	}
+}
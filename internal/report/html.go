@@ -0,0 +1,309 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/util"
+)
+
+//go:embed report.html.tmpl
+var htmlTemplateSource string
+
+var htmlTemplate = template.Must(
+	template.New("report").
+		Funcs(template.FuncMap{
+			"half":   func(f float64) float64 { return f / 2 },
+			"minus1": func(f float64) float64 { return f - 1 },
+		}).
+		Parse(htmlTemplateSource),
+)
+
+// fallbackColor is the color used for categories for which no color is known.
+const fallbackColor = "#cccccc"
+
+// WriteHTML writes the report as a single self-contained HTML document (i.e.
+// with inline styles and SVG charts, requiring no network access) to the
+// given writer.
+//
+// The colors map category names to their (hex) colors.
+func WriteHTML(w io.Writer, r Report, colors map[string]string) error {
+	return htmlTemplate.Execute(w, newHTMLView(r, colors))
+}
+
+type htmlView struct {
+	From, Til string
+	Total     string
+
+	Totals    []htmlTotal
+	Goals     []htmlGoal
+	Legend    []htmlTotal
+	DayChart  htmlBarChart
+	Heatmap   htmlHeatmap
+	Timesheet []htmlTimesheetRow
+}
+
+type htmlTotal struct {
+	Name     string
+	Color    string
+	Duration string
+	Percent  float64
+}
+
+type htmlGoal struct {
+	Name       string
+	Color      string
+	Goal       string
+	Actual     string
+	Difference string
+	Percent    float64
+	BarPercent float64
+	Reached    bool
+}
+
+type htmlBarChart struct {
+	Width, Height float64
+	ChartHeight   float64
+	Bars          []htmlBar
+	Ticks         []htmlTick
+}
+
+type htmlBar struct {
+	X, W     float64
+	Label    string
+	Title    string
+	Segments []htmlSegment
+}
+
+type htmlSegment struct {
+	Y, H  float64
+	Color string
+	Title string
+}
+
+type htmlTick struct {
+	Offset float64
+	Label  string
+}
+
+type htmlHeatmap struct {
+	Width, Height float64
+	CellSize      float64
+	LabelWidth    float64
+	Rows          []htmlHeatmapRow
+	HourLabels    []htmlTick
+}
+
+type htmlHeatmapRow struct {
+	Y     float64
+	Label string
+	Cells []htmlHeatmapCell
+}
+
+type htmlHeatmapCell struct {
+	X       float64
+	Opacity float64
+	Title   string
+}
+
+type htmlTimesheetRow struct {
+	Date    string
+	Weekday string
+	Start   string
+	Break   string
+	End     string
+	Empty   bool
+}
+
+func newHTMLView(r Report, colors map[string]string) htmlView {
+	colorOf := func(cat model.Category) string {
+		if color, ok := colors[cat.Name]; ok && color != "" {
+			return color
+		}
+		return fallbackColor
+	}
+
+	view := htmlView{
+		From: r.From.ToString(),
+		Til:  r.Til.ToString(),
+	}
+
+	total := time.Duration(0)
+	for _, t := range r.Totals {
+		total += t.Duration
+	}
+	view.Total = formatDuration(total)
+	for _, t := range r.Totals {
+		percent := 0.0
+		if total > 0 {
+			percent = float64(t.Duration) / float64(total) * 100
+		}
+		view.Totals = append(view.Totals, htmlTotal{
+			Name:     t.Category.Name,
+			Color:    colorOf(t.Category),
+			Duration: formatDuration(t.Duration),
+			Percent:  percent,
+		})
+	}
+	view.Legend = view.Totals
+
+	for _, g := range r.Goals {
+		percent := g.Ratio() * 100
+		view.Goals = append(view.Goals, htmlGoal{
+			Name:       g.Category.Name,
+			Color:      colorOf(g.Category),
+			Goal:       formatDuration(g.Goal),
+			Actual:     formatDuration(g.Actual),
+			Difference: formatDuration(g.Actual - g.Goal),
+			Percent:    percent,
+			BarPercent: math.Min(percent, 100),
+			Reached:    g.Actual >= g.Goal,
+		})
+	}
+
+	view.DayChart = newDayChart(r.Days, colorOf)
+	view.Heatmap = newHeatmap(r.Heatmap)
+
+	for _, row := range r.Timesheet {
+		view.Timesheet = append(view.Timesheet, htmlTimesheetRow{
+			Date:    row.Date.ToString(),
+			Weekday: row.Date.ToWeekday().String()[:3],
+			Start:   row.Entry.Start.ToString(),
+			Break:   formatDuration(row.Entry.BreakDuration),
+			End:     row.Entry.End.ToString(),
+			Empty:   row.Entry.IsEmpty(),
+		})
+	}
+
+	return view
+}
+
+func newDayChart(days []DayTotals, colorOf func(model.Category) string) htmlBarChart {
+	const (
+		width        = 800.0
+		chartHeight  = 240.0
+		labelHeight  = 40.0
+		axisWidth    = 40.0
+		barSpacing   = 0.2
+		labelEveryPx = 24.0
+	)
+
+	chart := htmlBarChart{
+		Width:       width,
+		Height:      chartHeight + labelHeight,
+		ChartHeight: chartHeight,
+	}
+	if len(days) == 0 {
+		return chart
+	}
+
+	maxSum := time.Duration(0)
+	for _, day := range days {
+		if day.Sum() > maxSum {
+			maxSum = day.Sum()
+		}
+	}
+	// scale to full hours, at least one
+	maxHours := math.Max(1, math.Ceil(maxSum.Hours()))
+	scale := chartHeight / maxHours
+
+	tickEvery := math.Max(1, math.Ceil(maxHours/6))
+	for hours := 0.0; hours <= maxHours; hours += tickEvery {
+		chart.Ticks = append(chart.Ticks, htmlTick{
+			Offset: chartHeight - hours*scale,
+			Label:  fmt.Sprintf("%.0fh", hours),
+		})
+	}
+
+	slotWidth := (width - axisWidth) / float64(len(days))
+	labelEvery := int(math.Ceil(labelEveryPx / slotWidth))
+	for i, day := range days {
+		bar := htmlBar{
+			X:     axisWidth + float64(i)*slotWidth + slotWidth*barSpacing/2,
+			W:     slotWidth * (1 - barSpacing),
+			Title: fmt.Sprintf("%s: %s", day.Date.ToString(), formatDuration(day.Sum())),
+		}
+		if i%labelEvery == 0 {
+			bar.Label = fmt.Sprintf("%02d-%02d", day.Date.Month, day.Date.Day)
+		}
+		y := chartHeight
+		for _, total := range day.Totals {
+			h := total.Duration.Hours() * scale
+			y -= h
+			bar.Segments = append(bar.Segments, htmlSegment{
+				Y:     y,
+				H:     h,
+				Color: colorOf(total.Category),
+				Title: fmt.Sprintf("%s %s: %s", day.Date.ToString(), total.Category.Name, formatDuration(total.Duration)),
+			})
+		}
+		chart.Bars = append(chart.Bars, bar)
+	}
+
+	return chart
+}
+
+func newHeatmap(data [7][24]time.Duration) htmlHeatmap {
+	const (
+		cellSize    = 28.0
+		labelWidth  = 40.0
+		labelHeight = 20.0
+	)
+
+	heatmap := htmlHeatmap{
+		Width:      labelWidth + 24*cellSize,
+		Height:     labelHeight + 7*cellSize,
+		CellSize:   cellSize,
+		LabelWidth: labelWidth,
+	}
+
+	max := time.Duration(0)
+	for _, row := range data {
+		for _, value := range row {
+			if value > max {
+				max = value
+			}
+		}
+	}
+
+	weekdays := []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+	for weekday, row := range data {
+		heatmapRow := htmlHeatmapRow{
+			Y:     labelHeight + float64(weekday)*cellSize,
+			Label: weekdays[weekday],
+		}
+		for hour, value := range row {
+			opacity := 0.0
+			if max > 0 {
+				opacity = float64(value) / float64(max)
+			}
+			heatmapRow.Cells = append(heatmapRow.Cells, htmlHeatmapCell{
+				X:       labelWidth + float64(hour)*cellSize,
+				Opacity: opacity,
+				Title:   fmt.Sprintf("%s %02d:00-%02d:00: %s", weekdays[weekday], hour, (hour+1)%24, formatDuration(value)),
+			})
+		}
+		heatmap.Rows = append(heatmap.Rows, heatmapRow)
+	}
+	for hour := 0; hour < 24; hour += 2 {
+		heatmap.HourLabels = append(heatmap.HourLabels, htmlTick{
+			Offset: labelWidth + float64(hour)*cellSize,
+			Label:  fmt.Sprintf("%02d", hour),
+		})
+	}
+
+	return heatmap
+}
+
+// formatDuration formats a duration as hours and minutes, e.g., "7h 30min".
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + util.DurationToString(int((-d).Minutes()))
+	}
+	return util.DurationToString(int(d.Minutes()))
+}
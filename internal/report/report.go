@@ -0,0 +1,183 @@
+// Package report provides reports summarizing the events of a range of days,
+// e.g., for monthly reviews.
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// Report is the data of a report over a range of days.
+type Report struct {
+	From, Til model.Date
+
+	// Totals are the total (flattened) durations per category, longest first.
+	Totals []CategoryTotal
+	// Goals is the goal progress of all categories that have goals, in the
+	// order of their definition.
+	Goals []GoalProgress
+	// Days are the per-category totals for each day in the range.
+	Days []DayTotals
+	// Heatmap contains the tracked time per weekday (Monday first) and hour of
+	// day.
+	Heatmap [7][24]time.Duration
+	// Timesheet contains the timesheet entries for each day in the range.
+	Timesheet []TimesheetRow
+}
+
+// CategoryTotal is the total duration of a category.
+type CategoryTotal struct {
+	Category model.Category
+	Duration time.Duration
+}
+
+// GoalProgress is the progress of a category towards its goal.
+type GoalProgress struct {
+	Category model.Category
+	Goal     time.Duration
+	Actual   time.Duration
+}
+
+// Ratio returns the ratio of actual to goal duration (0 for a zero goal).
+func (p GoalProgress) Ratio() float64 {
+	if p.Goal == 0 {
+		return 0
+	}
+	return float64(p.Actual) / float64(p.Goal)
+}
+
+// DayTotals are the totals per category of a single day.
+type DayTotals struct {
+	Date   model.Date
+	Totals []CategoryTotal
+}
+
+// Sum returns the sum of all totals of the day.
+func (d DayTotals) Sum() time.Duration {
+	sum := time.Duration(0)
+	for _, total := range d.Totals {
+		sum += total.Duration
+	}
+	return sum
+}
+
+// TimesheetRow is the timesheet entry for a date.
+type TimesheetRow struct {
+	Date  model.Date
+	Entry model.TimesheetEntry
+}
+
+// Build builds the report for the range of days from `from` to `til`
+// (inclusive).
+//
+// The given days have to correspond to the dates in the range in order; nil
+// days are treated as empty. The categories determine the order in which
+// categories are listed (unknown categories are appended by name) and which
+// goals are reported on. The timesheet matcher decides which categories count
+// towards the timesheet.
+func Build(
+	from, til model.Date,
+	days []*model.Day,
+	categories []model.Category,
+	timesheetMatcher func(string) bool,
+) Report {
+	result := Report{From: from, Til: til}
+
+	categoryIndex := make(map[string]int)
+	for i, cat := range categories {
+		categoryIndex[cat.Name] = i
+	}
+	sortByDefinition := func(totals []CategoryTotal) {
+		sort.SliceStable(totals, func(i, j int) bool {
+			iIndex, iKnown := categoryIndex[totals[i].Category.Name]
+			jIndex, jKnown := categoryIndex[totals[j].Category.Name]
+			switch {
+			case iKnown && jKnown:
+				return iIndex < jIndex
+			case iKnown != jKnown:
+				return iKnown
+			default:
+				return totals[i].Category.Name < totals[j].Category.Name
+			}
+		})
+	}
+
+	totalsByName := make(map[string]*CategoryTotal)
+	date := from
+	for _, day := range days {
+		if day == nil {
+			day = model.NewDay()
+		}
+
+		dayTotals := DayTotals{Date: date}
+		for category, minutes := range day.SumUpByCategory() {
+			duration := time.Duration(minutes) * time.Minute
+			dayTotals.Totals = append(dayTotals.Totals, CategoryTotal{Category: category, Duration: duration})
+			if _, ok := totalsByName[category.Name]; !ok {
+				totalsByName[category.Name] = &CategoryTotal{Category: category}
+			}
+			totalsByName[category.Name].Duration += duration
+		}
+		sortByDefinition(dayTotals.Totals)
+		result.Days = append(result.Days, dayTotals)
+
+		flattened := day.Clone()
+		flattened.Flatten()
+		weekdayIndex := (int(date.ToWeekday()) + 6) % 7
+		for _, event := range flattened.Events {
+			addToHourBuckets(&result.Heatmap[weekdayIndex], event.Start, event.End)
+		}
+
+		result.Timesheet = append(result.Timesheet, TimesheetRow{
+			Date:  date,
+			Entry: day.GetTimesheetEntry(timesheetMatcher),
+		})
+
+		date = date.Next()
+	}
+
+	for _, total := range totalsByName {
+		result.Totals = append(result.Totals, *total)
+	}
+	sortByDefinition(result.Totals)
+	sort.SliceStable(result.Totals, func(i, j int) bool {
+		return result.Totals[i].Duration > result.Totals[j].Duration
+	})
+
+	for _, cat := range categories {
+		if cat.Goal == nil {
+			continue
+		}
+		progress := GoalProgress{
+			Category: cat,
+			Goal:     model.GoalForRange(cat.Goal, from, til),
+		}
+		if total, ok := totalsByName[cat.Name]; ok {
+			progress.Actual = total.Duration
+		}
+		result.Goals = append(result.Goals, progress)
+	}
+
+	return result
+}
+
+// addToHourBuckets adds the time between start and end to the buckets for the
+// hours of the day it falls into.
+func addToHourBuckets(buckets *[24]time.Duration, start, end model.Timestamp) {
+	for hour := start.Hour; hour <= end.Hour && hour < 24; hour++ {
+		bucketStart := model.Timestamp{Hour: hour, Minute: 0}
+		if bucketStart.IsBefore(start) {
+			bucketStart = start
+		}
+		bucketEnd := model.Timestamp{Hour: hour, Minute: 59}
+		minutes := bucketStart.DurationInMinutesUntil(bucketEnd) + 1
+		if !end.IsAfter(bucketEnd) {
+			minutes = bucketStart.DurationInMinutesUntil(end)
+		}
+		if minutes > 0 {
+			buckets[hour] += time.Duration(minutes) * time.Minute
+		}
+	}
+}
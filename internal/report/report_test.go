@@ -0,0 +1,85 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func TestBuild(t *testing.T) {
+	categories := []model.Category{
+		{Name: "work", Priority: 1, Goal: &model.WorkweekGoal{Monday: 8 * time.Hour, Tuesday: 8 * time.Hour}},
+		{Name: "eating", Priority: 2},
+	}
+	monday := model.Date{Year: 2022, Month: 12, Day: 5}
+	tuesday := monday.Next()
+	days := []*model.Day{
+		model.NewDayWithEvents([]*model.Event{
+			model.NewEvent("09:30|12:00|work|Coding", categories),
+			model.NewEvent("11:30|12:30|eating|Lunch", categories),
+		}),
+		nil,
+	}
+
+	r := Build(monday, tuesday, days, categories, func(s string) bool { return s == "work" })
+
+	{
+		testcase := "totals (flattened, longest first)"
+		if len(r.Totals) != 2 ||
+			r.Totals[0].Category.Name != "work" || r.Totals[0].Duration != 2*time.Hour ||
+			r.Totals[1].Category.Name != "eating" || r.Totals[1].Duration != 1*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, r.Totals)
+		}
+	}
+	{
+		testcase := "goals"
+		if len(r.Goals) != 1 || r.Goals[0].Goal != 16*time.Hour || r.Goals[0].Actual != 2*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, r.Goals)
+		}
+	}
+	{
+		testcase := "days (nil day treated as empty)"
+		if len(r.Days) != 2 || r.Days[0].Sum() != 3*time.Hour || r.Days[1].Sum() != 0 {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, r.Days)
+		}
+	}
+	{
+		testcase := "heatmap buckets"
+		expected := map[int]time.Duration{9: 30 * time.Minute, 10: time.Hour, 11: time.Hour, 12: 30 * time.Minute}
+		for hour, value := range r.Heatmap[0] {
+			if value != expected[hour] {
+				t.Fatalf("testcase '%s' failed: hour %d has %s, expected %s", testcase, hour, value, expected[hour])
+			}
+		}
+		for _, value := range r.Heatmap[1] {
+			if value != 0 {
+				t.Fatalf("testcase '%s' failed: tuesday not empty", testcase)
+			}
+		}
+	}
+	{
+		testcase := "timesheet"
+		if len(r.Timesheet) != 2 ||
+			r.Timesheet[0].Entry.Start != (model.Timestamp{Hour: 9, Minute: 30}) ||
+			r.Timesheet[0].Entry.End != (model.Timestamp{Hour: 11, Minute: 30}) ||
+			!r.Timesheet[1].Entry.IsEmpty() {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, r.Timesheet)
+		}
+	}
+	{
+		testcase := "html output"
+		var buf bytes.Buffer
+		err := WriteHTML(&buf, r, map[string]string{"work": "#ccebff"})
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		for _, expected := range []string{"<html", "#ccebff", fallbackColor, "Weekday × Hour", "2022-12-06"} {
+			if !strings.Contains(buf.String(), expected) {
+				t.Fatalf("testcase '%s' failed: output does not contain '%s'", testcase, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Package status provides a compact summary of the current state of a day
+// (current and next event, totals, goal progress), e.g., for shell prompts or
+// status bars.
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/tracking"
+)
+
+// Status is the status of a day at a point in time.
+type Status struct {
+	Date string `json:"date"`
+	Time string `json:"time"`
+
+	// Current is the event at the current time, considering overlaps by
+	// category priority (see model.Day.Flatten), if any.
+	Current *Event `json:"current"`
+	// Next is the next event starting after the current time, if any.
+	Next *Event `json:"next"`
+	// Remaining is the time remaining in the current event.
+	Remaining Duration `json:"remaining_minutes"`
+	// UntilNext is the time until the next event starts.
+	UntilNext Duration `json:"until_next_minutes"`
+
+	// Tracked is the total (flattened) duration of the day's events up to the
+	// current time.
+	Tracked Duration `json:"tracked_minutes"`
+	// Planned is the total (flattened) duration of all the day's events.
+	Planned Duration `json:"planned_minutes"`
+
+	// Goals is the progress on the day's category goals, sorted by category
+	// name.
+	Goals []GoalProgress `json:"goals"`
+
+	// Tracking is whether the current event is being tracked live.
+	Tracking bool `json:"tracking"`
+}
+
+// Event is an event as reported in the status.
+type Event struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+}
+
+// GoalProgress is the progress on a category's goal for the day.
+type GoalProgress struct {
+	Category string   `json:"category"`
+	Required Duration `json:"required_minutes"`
+	Done     Duration `json:"done_minutes"`
+}
+
+// Percent returns the fulfilled percentage of the goal.
+func (g GoalProgress) Percent() int {
+	if g.Required <= 0 {
+		return 100
+	}
+	return int(100 * time.Duration(g.Done) / time.Duration(g.Required))
+}
+
+// Duration is a duration that is formatted compactly (e.g., "1h05m" or "12m")
+// in templates and as minutes in JSON.
+type Duration time.Duration
+
+// String returns the duration formatted compactly, e.g., "1h05m" or "12m".
+func (d Duration) String() string {
+	minutes := d.Minutes()
+	sign := ""
+	if minutes < 0 {
+		sign, minutes = "-", -minutes
+	}
+	if minutes < 60 {
+		return fmt.Sprintf("%s%dm", sign, minutes)
+	}
+	return fmt.Sprintf("%s%dh%02dm", sign, minutes/60, minutes%60)
+}
+
+// Minutes returns the duration in whole minutes.
+func (d Duration) Minutes() int {
+	return int(time.Duration(d) / time.Minute)
+}
+
+// MarshalJSON marshals the duration as a number of minutes.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Minutes())
+}
+
+// Build builds the status for the given day at the given date and time.
+// The categories are used to determine the goals.
+func Build(date model.Date, now model.Timestamp, day *model.Day, categories []model.Category) Status {
+	return BuildTracking(date, now, day, categories, nil)
+}
+
+// BuildTracking builds the status like Build, but with the given running event
+// (if any, see tracking.Running) extended to the current time and, since it
+// ends just now, still reported as current (and as tracked).
+func BuildTracking(date model.Date, now model.Timestamp, day *model.Day, categories []model.Category, running *tracking.Running) Status {
+	result := Status{
+		Date:  date.ToString(),
+		Time:  now.ToString(),
+		Goals: make([]GoalProgress, 0),
+	}
+	if day == nil {
+		day = model.NewDay()
+	}
+
+	// the running event is only extended in its day file when stopped (or saved
+	// in the TUI), so extend it here
+	var runningEvent *model.Event
+	if running != nil && running.Date == date {
+		day = day.Clone()
+		if runningEvent = running.Find(day); runningEvent != nil {
+			_ = day.ResizeTo(runningEvent, running.EndAt(date, now))
+		}
+	}
+	// isCurrent returns whether the given event (which may be cut by
+	// flattening) is ongoing; the running event's end is inclusive
+	isCurrent := func(e *model.Event) bool {
+		if e.Start.IsAfter(now) {
+			return false
+		}
+		if runningEvent != nil && e.Name == runningEvent.Name && e.Cat.Name == runningEvent.Cat.Name && e.End == runningEvent.End {
+			return !e.End.IsBefore(now)
+		}
+		return e.End.IsAfter(now)
+	}
+
+	flattened := day.Clone()
+	flattened.Flatten()
+
+	done := make(map[string]time.Duration)
+	var current *model.Event
+	for _, e := range flattened.Events {
+		result.Planned += Duration(e.Start.DurationUntil(e.End))
+		if !e.Start.IsAfter(now) {
+			end := e.End
+			if isCurrent(e) {
+				end = now
+				current = e
+			}
+			result.Tracked += Duration(e.Start.DurationUntil(end))
+			done[e.Cat.Name] += e.Start.DurationUntil(end)
+		}
+	}
+	// flattening may have cut the current event, so report the original one
+	if current != nil {
+		for _, e := range day.Events {
+			if e.Name == current.Name && e.Cat == current.Cat && isCurrent(e) {
+				result.Current = toEvent(e)
+				result.Remaining = Duration(now.DurationUntil(e.End))
+				result.Tracking = e == runningEvent
+				break
+			}
+		}
+	}
+
+	for _, e := range day.Events {
+		if e.Start.IsAfter(now) {
+			result.Next = toEvent(e)
+			result.UntilNext = Duration(now.DurationUntil(e.Start))
+			break
+		}
+	}
+
+	for _, cat := range categories {
+		if cat.Goal == nil {
+			continue
+		}
+		required := cat.Goal.Requires(date)
+		if required == 0 {
+			continue
+		}
+		result.Goals = append(result.Goals, GoalProgress{
+			Category: cat.Name,
+			Required: Duration(required),
+			Done:     Duration(done[cat.Name]),
+		})
+	}
+	sort.Slice(result.Goals, func(i, j int) bool { return result.Goals[i].Category < result.Goals[j].Category })
+
+	return result
+}
+
+func toEvent(e *model.Event) *Event {
+	return &Event{
+		Name:     e.Name,
+		Category: e.Cat.Name,
+		Start:    e.Start.ToString(),
+		End:      e.End.ToString(),
+	}
+}
@@ -0,0 +1,115 @@
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/tracking"
+)
+
+func TestBuild(t *testing.T) {
+	categories := []model.Category{
+		{Name: "work", Priority: 1, Goal: &model.WorkweekGoal{Monday: 8 * time.Hour}},
+		{Name: "meeting", Priority: 2},
+		{Name: "eating", Priority: 1, Goal: &model.WorkweekGoal{Tuesday: time.Hour}},
+	}
+	monday := model.Date{Year: 2022, Month: 12, Day: 5}
+	day := model.NewDayWithEvents([]*model.Event{
+		model.NewEvent("09:00|12:00|work|Deep work", categories),
+		model.NewEvent("10:00|10:30|meeting|Standup", categories),
+		model.NewEvent("12:00|12:30|eating|Lunch", categories),
+	})
+
+	{
+		testcase := "during an event that is cut by flattening"
+		s := Build(monday, model.Timestamp{Hour: 11, Minute: 0}, day, categories)
+		if s.Current == nil || s.Current.Name != "Deep work" || s.Current.Start != "09:00" || s.Remaining.Minutes() != 60 {
+			t.Fatalf("testcase '%s' failed: current is %v (remaining %s)", testcase, s.Current, s.Remaining)
+		}
+		if s.Next == nil || s.Next.Name != "Lunch" || s.UntilNext.Minutes() != 60 {
+			t.Fatalf("testcase '%s' failed: next is %v (until %s)", testcase, s.Next, s.UntilNext)
+		}
+		if s.Tracked.Minutes() != 120 || s.Planned.Minutes() != 210 {
+			t.Fatalf("testcase '%s' failed: tracked %s, planned %s", testcase, s.Tracked, s.Planned)
+		}
+		if len(s.Goals) != 1 || s.Goals[0].Category != "work" || s.Goals[0].Done.Minutes() != 90 || s.Goals[0].Percent() != 18 {
+			t.Fatalf("testcase '%s' failed: goals are %v", testcase, s.Goals)
+		}
+	}
+	{
+		testcase := "during overlapping higher-priority event"
+		s := Build(monday, model.Timestamp{Hour: 10, Minute: 10}, day, categories)
+		if s.Current == nil || s.Current.Name != "Standup" || s.Remaining.Minutes() != 20 {
+			t.Fatalf("testcase '%s' failed: current is %v", testcase, s.Current)
+		}
+	}
+	{
+		testcase := "after all events"
+		s := Build(monday, model.Timestamp{Hour: 20, Minute: 0}, day, categories)
+		if s.Current != nil || s.Next != nil || s.Tracked != s.Planned {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, s)
+		}
+	}
+	{
+		testcase := "json"
+		s := Build(monday, model.Timestamp{Hour: 11, Minute: 0}, nil, categories)
+		var buf bytes.Buffer
+		err := json.NewEncoder(&buf).Encode(s)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		expected := `{"date":"2022-12-05","time":"11:00","current":null,"next":null,"remaining_minutes":0,"until_next_minutes":0,"tracked_minutes":0,"planned_minutes":0,"goals":[{"category":"work","required_minutes":480,"done_minutes":0}],"tracking":false}` + "\n"
+		if buf.String() != expected {
+			t.Fatalf("testcase '%s' failed: expected\n%s\ngot\n%s", testcase, expected, buf.String())
+		}
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	for d, expected := range map[time.Duration]string{
+		0:                             "0m",
+		12 * time.Minute:              "12m",
+		65 * time.Minute:              "1h05m",
+		-90 * time.Minute:             "-1h30m",
+		10*time.Hour + 59*time.Second: "10h00m",
+	} {
+		if Duration(d).String() != expected {
+			t.Fatalf("duration %s should be formatted as '%s', but is '%s'", d, expected, Duration(d).String())
+		}
+	}
+}
+
+func TestBuildTracking(t *testing.T) {
+	categories := []model.Category{{Name: "work", Priority: 1}}
+	monday := model.Date{Year: 2022, Month: 12, Day: 5}
+	running := &tracking.Running{Date: monday, Start: model.Timestamp{Hour: 9, Minute: 0}, Category: "work", Name: "Deep work"}
+	day := model.NewDayWithEvents([]*model.Event{
+		// as last persisted, i.e., not yet extended to the current time
+		model.NewEvent("09:00|09:30|work|Deep work", categories),
+		model.NewEvent("12:00|12:30|work|Review", categories),
+	})
+
+	{
+		testcase := "during running event"
+		s := BuildTracking(monday, model.Timestamp{Hour: 10, Minute: 30}, day, categories, running)
+		if s.Current == nil || s.Current.Name != "Deep work" || s.Current.End != "10:30" || !s.Tracking {
+			t.Fatalf("testcase '%s' failed: current is %v (tracking: %t)", testcase, s.Current, s.Tracking)
+		}
+		if s.Tracked.Minutes() != 90 {
+			t.Fatalf("testcase '%s' failed: tracked %s", testcase, s.Tracked)
+		}
+		if day.Events[0].End != (model.Timestamp{Hour: 9, Minute: 30}) {
+			t.Fatalf("testcase '%s' failed: given day was modified", testcase)
+		}
+	}
+	{
+		testcase := "running event of another day"
+		s := BuildTracking(monday.Next(), model.Timestamp{Hour: 10, Minute: 30}, day, categories, running)
+		if s.Current != nil || s.Tracking {
+			t.Fatalf("testcase '%s' failed: current is %v (tracking: %t)", testcase, s.Current, s.Tracking)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// AbsencesFileName is the name of the file (in the dayplan base directory) in
+// which absences are stored, one per line.
+const AbsencesFileName = "absences"
+
+// ReadAbsences reads the absences from the file at the given path.
+// A missing file means there are no absences.
+func ReadAbsences(path string) (*model.Absences, error) {
+	absences := model.NewAbsences()
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return absences, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		absence, err := model.NewDatedAbsenceFromString(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		absences.Set(absence.Date, absence.Absence)
+	}
+	return absences, scanner.Err()
+}
+
+// WriteAbsences writes the given absences to the file at the given path,
+// sorted by date.
+func WriteAbsences(path string, absences *model.Absences) error {
+	f, err := os.OpenFile(path, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(f)
+	for _, absence := range absences.All() {
+		_, _ = writer.WriteString(absence.ToString() + "\n")
+	}
+	err = writer.Flush()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// CommandHistoryFileName is the name of the file (in the dayplan base
+// directory) in which the history of the TUI's command line is stored, one
+// command line per line, oldest first.
+const CommandHistoryFileName = "command-history"
+
+// ReadCommandHistory reads the command line history from the file at the
+// given path.
+// A missing file means there is no history yet.
+func ReadCommandHistory(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	result := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result, scanner.Err()
+}
+
+// WriteCommandHistory writes the given command line history to the file at
+// the given path.
+func WriteCommandHistory(path string, entries []string) error {
+	f, err := os.OpenFile(path, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(f)
+	for _, entry := range entries {
+		_, _ = writer.WriteString(entry + "\n")
+	}
+	err = writer.Flush()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/ja-he/dayplan/internal/invoice"
+)
+
+// InvoiceLedgerFileName is the name of the file (in the dayplan base
+// directory) in which the ledger of issued invoices is stored, one per line.
+const InvoiceLedgerFileName = "invoices"
+
+// ReadInvoiceLedger reads the invoice ledger from the file at the given path.
+// A missing file means no invoices were issued yet.
+func ReadInvoiceLedger(path string) (*invoice.Ledger, error) {
+	ledger := &invoice.Ledger{}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ledger, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		entry, err := invoice.NewLedgerEntryFromString(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		ledger.Entries = append(ledger.Entries, entry)
+	}
+	return ledger, scanner.Err()
+}
+
+// WriteInvoiceLedger writes the given invoice ledger to the file at the given
+// path.
+func WriteInvoiceLedger(path string, ledger *invoice.Ledger) error {
+	f, err := os.OpenFile(path, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(f)
+	for _, entry := range ledger.Entries {
+		_, _ = writer.WriteString(entry.ToString() + "\n")
+	}
+	err = writer.Flush()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
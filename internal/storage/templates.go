@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// TemplatesDirName is the name of the directory (in the dayplan base
+// directory) in which day templates are stored, one file per template named
+// like the template, in the same format as days.
+const TemplatesDirName = "templates"
+
+// ListTemplates returns the (sorted) names of the templates in the given
+// directory.
+// A missing directory means there are no templates.
+func ListTemplates(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := []string{}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			result = append(result, entry.Name())
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// ReadTemplate reads the template of the given name from the given
+// directory.
+func ReadTemplate(dir, name string, knownCategories []model.Category) (*model.Day, error) {
+	if err := validateTemplateName(name); err != nil {
+		return nil, err
+	}
+	templatePath := path.Join(dir, name)
+	if _, err := os.Stat(templatePath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("no template '%s'", name)
+		}
+		return nil, err
+	}
+	return NewFileHandler(templatePath).Read(knownCategories), nil
+}
+
+// WriteTemplate writes the given day as the template of the given name to
+// the given directory, creating the directory if necessary.
+func WriteTemplate(dir, name string, day *model.Day) error {
+	if err := validateTemplateName(name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	NewFileHandler(path.Join(dir, name)).Write(day)
+	return nil
+}
+
+func validateTemplateName(name string) error {
+	if name == "" || strings.ContainsAny(name, "/\\") || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid template name '%s'", name)
+	}
+	return nil
+}
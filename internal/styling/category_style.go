@@ -55,6 +55,15 @@ func (cs *CategoryStyling) GetAll() []StyledCategory {
 	return cs.styles
 }
 
+// GetCategories returns the plain categories in this styling.
+func (cs *CategoryStyling) GetCategories() []model.Category {
+	result := make([]model.Category, 0, len(cs.styles))
+	for _, styling := range cs.styles {
+		result = append(result, styling.Cat)
+	}
+	return result
+}
+
 // GetStyle returns the styling for the requested category from this styling.
 //
 // If no styling is present for the category, it returns nil an an error.
@@ -30,6 +30,8 @@ type DrawStyling interface {
 	Bolded() DrawStyling
 	Unbolded() DrawStyling
 
+	HexColors() (fg, bg string)
+
 	ToString() string
 }
 
@@ -159,6 +161,13 @@ func (s *FallbackStyling) Bolded() DrawStyling {
 	return result
 }
 
+// HexColors returns the foreground and background colors of this styling in
+// hexadecimal notation (e.g., '#ff0000'), e.g., for rendering outside of a
+// terminal.
+func (s *FallbackStyling) HexColors() (fg, bg string) {
+	return s.fg.Hex(), s.bg.Hex()
+}
+
 // ToString returns a string representation of this styling, e.g., for logging
 // purposes.
 func (s *FallbackStyling) ToString() string {
@@ -220,6 +229,18 @@ func StyleFromColors(fg, bg colorful.Color) *FallbackStyling {
 	}
 }
 
+// BlendedBG returns a style with the foreground color of `to` and a background
+// color blended from the background of `from` (fraction 0) to the background
+// of `to` (fraction 1).
+func BlendedBG(from, to DrawStyling, fraction float64) DrawStyling {
+	_, fromBG := from.HexColors()
+	toFG, toBG := to.HexColors()
+	return StyleFromColors(
+		colorfulColorFromHexString(toFG),
+		colorfulColorFromHexString(fromBG).BlendLab(colorfulColorFromHexString(toBG), fraction).Clamped(),
+	)
+}
+
 // StyleFromConfig takes a styling as specified in a configuration file and
 // converts it to a usable DrawStyling.
 func StyleFromConfig(config config.Styling) DrawStyling {
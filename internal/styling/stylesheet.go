@@ -39,6 +39,11 @@ type Stylesheet struct {
 
 	SummaryDefault  DrawStyling
 	SummaryTitleBox DrawStyling
+
+	// Heatmap is the style of the days with the most time (or goal progress)
+	// in the year view's heatmap, the other days' being blended towards
+	// NormalEmphasized.
+	Heatmap DrawStyling
 }
 
 // NewStylesheetFromConfig constructs a new stylesheet from a given config
@@ -69,6 +74,7 @@ func NewStylesheetFromConfig(config config.Stylesheet) *Stylesheet {
 	stylesheet.SummaryDefault = StyleFromConfig(config.SummaryDefault)
 	stylesheet.SummaryTitleBox = StyleFromConfig(config.SummaryTitleBox)
 	stylesheet.CategoryFallback = StyleFromConfig(config.CategoryFallback)
+	stylesheet.Heatmap = StyleFromConfig(config.Heatmap)
 
 	return &stylesheet
 }
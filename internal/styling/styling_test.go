@@ -88,3 +88,21 @@ func TestLighten(t *testing.T) {
 		}
 	}
 }
+
+func TestBlendedBG(t *testing.T) {
+	from := StyleFromHexPair("#000000", "#ffffff")
+	to := StyleFromHexPair("#ffffff", "#216e39")
+
+	for fraction, expectedBG := range map[float64]string{0: "#ffffff", 1: "#216e39"} {
+		fg, bg := BlendedBG(from, to, fraction).HexColors()
+		if fg != "#ffffff" || bg != expectedBG {
+			t.Fatalf("blending by %f gave fg %s and bg %s, expected fg #ffffff and bg %s", fraction, fg, bg, expectedBG)
+		}
+	}
+
+	_, bg := BlendedBG(from, to, 0.5).HexColors()
+	blended := colorfulColorFromHexString(bg)
+	if getLuminance(blended) <= getLuminance(colorfulColorFromHexString("#216e39")) || getLuminance(blended) >= 100 {
+		t.Fatalf("blending halfway gave %s, which is not between the backgrounds", bg)
+	}
+}
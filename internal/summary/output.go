@@ -0,0 +1,162 @@
+package summary
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/util"
+)
+
+// WriteTable writes the given rows as a human-readable table, with a header
+// line per group (unless there is only a single group).
+// If humanReadable is set, durations are formatted as hours and minutes
+// instead of minutes.
+func WriteTable(w io.Writer, rows []Row, humanReadable bool) error {
+	multipleGroups := len(rows) > 0 && rows[0].Group != rows[len(rows)-1].Group
+	lastGroup := ""
+	for _, row := range rows {
+		if multipleGroups && row.Group != lastGroup {
+			if _, err := fmt.Fprintf(w, "%s:\n", row.Group); err != nil {
+				return err
+			}
+			lastGroup = row.Group
+		}
+
+		minutes := int(row.Duration / time.Minute)
+		var durationStr string
+		if humanReadable {
+			durationStr = util.DurationToString(minutes)
+		} else {
+			durationStr = fmt.Sprint(minutes, " min")
+		}
+
+		var goalStr string
+		if row.Goal != nil {
+			deficit := *row.Goal - row.Duration
+			goalStr = fmt.Sprintf("(%.2f%% of goal, %s deficit)", percentage(row.Duration, *row.Goal), deficit-(deficit%time.Minute))
+		}
+		if over := row.OverLimit(); over > 0 {
+			goalStr = strings.TrimLeft(goalStr+fmt.Sprintf(" WARNING: over limit (%s) by %s", *row.Limit, over), " ")
+		}
+
+		kindStr := fmt.Sprintf("(prio:% 3d)", row.Category.Priority)
+		if row.Pattern {
+			kindStr = "(pattern) "
+		}
+
+		_, err := fmt.Fprintf(w, "  % 20s %s: % 10s %s\n", row.Category.Name, kindStr, durationStr, goalStr)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes the given rows as CSV with a header line.
+// Durations are given in minutes; the goal and limit columns are empty for
+// categories without a goal or limit respectively.
+// The kind is "category" or "pattern" (for pattern goals, which have no
+// priority).
+func WriteCSV(w io.Writer, rows []Row) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{"group", "from", "til", "category", "priority", "minutes", "goal_minutes", "goal_percent", "limit_minutes", "over_limit_minutes", "kind"})
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		var goalMinutes, goalPercent, limitMinutes, overLimitMinutes string
+		if row.Goal != nil {
+			goalMinutes = strconv.Itoa(int(*row.Goal / time.Minute))
+			goalPercent = strconv.FormatFloat(percentage(row.Duration, *row.Goal), 'f', 2, 64)
+		}
+		if row.Limit != nil {
+			limitMinutes = strconv.Itoa(int(*row.Limit / time.Minute))
+			overLimitMinutes = strconv.Itoa(int(row.OverLimit() / time.Minute))
+		}
+		priority, kind := strconv.Itoa(row.Category.Priority), "category"
+		if row.Pattern {
+			priority, kind = "", "pattern"
+		}
+		err := writer.Write([]string{
+			row.Group,
+			row.From.ToString(),
+			row.Til.ToString(),
+			row.Category.Name,
+			priority,
+			strconv.Itoa(int(row.Duration / time.Minute)),
+			goalMinutes,
+			goalPercent,
+			limitMinutes,
+			overLimitMinutes,
+			kind,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the given rows as a JSON array.
+// Durations are given in minutes; the goal and limit fields are omitted for
+// categories without a goal or limit respectively.
+// The kind is "category" or "pattern" (for pattern goals).
+func WriteJSON(w io.Writer, rows []Row) error {
+	type jsonRow struct {
+		Group            string   `json:"group"`
+		From             string   `json:"from"`
+		Til              string   `json:"til"`
+		Category         string   `json:"category"`
+		Kind             string   `json:"kind"`
+		Priority         int      `json:"priority"`
+		Minutes          int      `json:"minutes"`
+		GoalMinutes      *int     `json:"goal_minutes,omitempty"`
+		GoalPercent      *float64 `json:"goal_percent,omitempty"`
+		LimitMinutes     *int     `json:"limit_minutes,omitempty"`
+		OverLimitMinutes *int     `json:"over_limit_minutes,omitempty"`
+	}
+	result := make([]jsonRow, 0, len(rows))
+	for _, row := range rows {
+		r := jsonRow{
+			Group:    row.Group,
+			From:     row.From.ToString(),
+			Til:      row.Til.ToString(),
+			Category: row.Category.Name,
+			Kind:     "category",
+			Priority: row.Category.Priority,
+			Minutes:  int(row.Duration / time.Minute),
+		}
+		if row.Pattern {
+			r.Kind = "pattern"
+		}
+		if row.Goal != nil {
+			goalMinutes := int(*row.Goal / time.Minute)
+			goalPercent := percentage(row.Duration, *row.Goal)
+			r.GoalMinutes, r.GoalPercent = &goalMinutes, &goalPercent
+		}
+		if row.Limit != nil {
+			limitMinutes := int(*row.Limit / time.Minute)
+			overLimitMinutes := int(row.OverLimit() / time.Minute)
+			r.LimitMinutes, r.OverLimitMinutes = &limitMinutes, &overLimitMinutes
+		}
+		result = append(result, r)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// percentage returns the percentage of the goal that the actual duration
+// fulfills; a zero goal counts as fulfilled.
+func percentage(actual, goal time.Duration) float64 {
+	if goal == 0 {
+		return 100
+	}
+	return float64(actual) / float64(goal) * 100.0
+}
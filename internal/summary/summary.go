@@ -0,0 +1,235 @@
+// Package summary provides summaries of time spent per category, optionally
+// grouped by time period, along with the comparison to category goals and
+// limits.
+package summary
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// GroupBy defines how a summary is grouped.
+type GroupBy string
+
+const (
+	// GroupByCategory summarizes the entire range per category.
+	GroupByCategory GroupBy = "category"
+	// GroupByDay summarizes each day per category.
+	GroupByDay GroupBy = "day"
+	// GroupByWeek summarizes each week (Monday through Sunday) per category.
+	GroupByWeek GroupBy = "week"
+	// GroupByMonth summarizes each month per category.
+	GroupByMonth GroupBy = "month"
+)
+
+// Row is the summarized duration of a category in a group (i.e., period).
+type Row struct {
+	// Group identifies the group, e.g. "2022-12-05" for a day or the week
+	// starting on that day, or "2022-12" for a month.
+	Group string
+	// From and Til are the (inclusive) bounds of the group, limited to the
+	// summarized range.
+	From, Til model.Date
+
+	// Category is the summarized category; for a pattern goal, it is a
+	// category named like the goal and with its goal and limit.
+	Category model.Category
+	// Pattern is set if the row is for a pattern goal rather than a category.
+	Pattern  bool
+	Duration time.Duration
+	// Goal is the duration required by the category's goal over the group's
+	// range, if the category has a goal.
+	Goal *time.Duration
+	// Limit is the maximum duration allowed by the category's limit over the
+	// group's range (see model.LimitForRange), if the category has a limit that
+	// can be checked for the group (see limitCheckable).
+	Limit *time.Duration
+}
+
+// OverLimit returns by how much the duration exceeds the limit, or 0 if it
+// does not (or there is no limit).
+func (r Row) OverLimit() time.Duration {
+	if r.Limit == nil || r.Duration <= *r.Limit {
+		return 0
+	}
+	return r.Duration - *r.Limit
+}
+
+// Warnings returns a warning for each of the given rows that is over its
+// limit.
+func Warnings(rows []Row) []string {
+	result := make([]string, 0)
+	for _, row := range rows {
+		if over := row.OverLimit(); over > 0 {
+			result = append(result, fmt.Sprintf("'%s' is over its limit of %s by %s (%s)", row.Category.Name, *row.Limit, over, row.Group))
+		}
+	}
+	return result
+}
+
+// Summarize sums up the time spent per category and per pattern goal in the
+// given days (the consecutive days starting at from, nil days are treated as
+// empty), grouped as requested.
+// Only categories (and pattern goals, by name) for which include returns true
+// are considered.
+//
+// The result is sorted chronologically by group and, within each group, the
+// categories precede the pattern goals, each sorted by descending duration and
+// then name.
+func Summarize(from model.Date, days []*model.Day, groupBy GroupBy, include func(model.Category) bool, patternGoals []model.PatternGoal) ([]Row, error) {
+	if len(days) == 0 {
+		return make([]Row, 0), nil
+	}
+	groupOf, err := grouping(groupBy, from, from.Forward(len(days)-1))
+	if err != nil {
+		return nil, err
+	}
+
+	type group struct {
+		name      string
+		from, til model.Date
+		durations map[model.Category]int
+		// patternDurations are the durations per pattern goal (by index)
+		patternDurations map[int]int
+	}
+	var groups []*group
+
+	date := from
+	for _, day := range days {
+		name, groupFrom, groupTil := groupOf(date)
+		if len(groups) == 0 || groups[len(groups)-1].name != name {
+			groups = append(groups, &group{name: name, from: groupFrom, til: groupTil, durations: make(map[model.Category]int), patternDurations: make(map[int]int)})
+		}
+		if day != nil {
+			current := groups[len(groups)-1]
+			for cat, minutes := range day.SumUpByCategory() {
+				if include(cat) {
+					current.durations[cat] += minutes
+				}
+			}
+			for i := range patternGoals {
+				if minutes := day.SumUpMatching(patternGoals[i].Matches); minutes > 0 {
+					current.patternDurations[i] += minutes
+				}
+			}
+		}
+		date = date.Next()
+	}
+
+	result := make([]Row, 0)
+	for _, g := range groups {
+		newRow := func(cat model.Category, minutes int) Row {
+			row := Row{
+				Group:    g.name,
+				From:     g.from,
+				Til:      g.til,
+				Category: cat,
+				Duration: time.Duration(minutes) * time.Minute,
+			}
+			if cat.Goal != nil {
+				goal := model.GoalForRange(cat.Goal, g.from, g.til)
+				row.Goal = &goal
+			}
+			if cat.Limit != nil && limitCheckable(cat.Limit, g.from, g.til) {
+				limit := model.LimitForRange(cat.Limit, g.from, g.til)
+				row.Limit = &limit
+			}
+			return row
+		}
+
+		rows := make([]Row, 0, len(g.durations))
+		for cat, minutes := range g.durations {
+			rows = append(rows, newRow(cat, minutes))
+		}
+		sortRows(rows)
+		result = append(result, rows...)
+
+		patternRows := make([]Row, 0, len(g.patternDurations))
+		for i, minutes := range g.patternDurations {
+			cat := model.Category{Name: patternGoals[i].Name, Goal: patternGoals[i].Goal, Limit: patternGoals[i].Limit}
+			if include(cat) {
+				row := newRow(cat, minutes)
+				row.Pattern = true
+				patternRows = append(patternRows, row)
+			}
+		}
+		sortRows(patternRows)
+		result = append(result, patternRows...)
+	}
+	return result, nil
+}
+
+// limitCheckable returns whether the given limit can be checked for a group
+// over the given range, which is not the case for weekly and monthly limits on
+// ranges not containing a whole week or month (e.g. a single day), as such a
+// limit allows its total in any distribution over its period.
+func limitCheckable(limit model.Goal, from, til model.Date) bool {
+	switch limit.(type) {
+	case *model.WeeklyGoal:
+		monday, _ := from.WeekBounds()
+		if monday != from {
+			monday = monday.Forward(7)
+		}
+		return !monday.Forward(6).IsAfter(til)
+	case *model.MonthlyGoal:
+		first, last := from.MonthBounds()
+		if first != from {
+			first, last = last.Next().MonthBounds()
+		}
+		return !last.IsAfter(til)
+	default:
+		return true
+	}
+}
+
+// sortRows sorts the given rows by descending duration and then name.
+func sortRows(rows []Row) {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Duration != rows[j].Duration {
+			return rows[i].Duration > rows[j].Duration
+		}
+		return rows[i].Category.Name < rows[j].Category.Name
+	})
+}
+
+// grouping returns a function that determines the group name and (range
+// limited) bounds for a date.
+func grouping(groupBy GroupBy, from, til model.Date) (func(model.Date) (string, model.Date, model.Date), error) {
+	limit := func(groupFrom, groupTil model.Date) (model.Date, model.Date) {
+		if groupFrom.IsBefore(from) {
+			groupFrom = from
+		}
+		if groupTil.IsAfter(til) {
+			groupTil = til
+		}
+		return groupFrom, groupTil
+	}
+
+	switch groupBy {
+	case GroupByCategory, "":
+		return func(model.Date) (string, model.Date, model.Date) {
+			return from.ToString() + ".." + til.ToString(), from, til
+		}, nil
+	case GroupByDay:
+		return func(d model.Date) (string, model.Date, model.Date) {
+			return d.ToString(), d, d
+		}, nil
+	case GroupByWeek:
+		return func(d model.Date) (string, model.Date, model.Date) {
+			monday, sunday := d.WeekBounds()
+			groupFrom, groupTil := limit(monday, sunday)
+			return monday.ToString(), groupFrom, groupTil
+		}, nil
+	case GroupByMonth:
+		return func(d model.Date) (string, model.Date, model.Date) {
+			first, last := d.MonthBounds()
+			groupFrom, groupTil := limit(first, last)
+			return fmt.Sprintf("%04d-%02d", d.Year, d.Month), groupFrom, groupTil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown grouping '%s'", groupBy)
+	}
+}
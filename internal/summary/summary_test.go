@@ -0,0 +1,231 @@
+package summary
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func TestSummarize(t *testing.T) {
+	categories := []model.Category{
+		{Name: "work", Priority: 1, Goal: &model.WorkweekGoal{Monday: 8 * time.Hour, Tuesday: 8 * time.Hour, Wednesday: 8 * time.Hour}},
+		{Name: "eating", Priority: 2},
+		{Name: "admin", Priority: 1},
+	}
+	sunday := model.Date{Year: 2022, Month: 12, Day: 4}
+	days := []*model.Day{
+		model.NewDayWithEvents([]*model.Event{model.NewEvent("12:00|12:30|eating|Lunch", categories)}),
+		model.NewDayWithEvents([]*model.Event{
+			model.NewEvent("09:00|12:00|work|Coding", categories),
+			model.NewEvent("12:00|13:00|eating|Lunch", categories),
+			model.NewEvent("13:00|14:00|admin|Mail", categories),
+		}),
+		nil,
+		model.NewDayWithEvents([]*model.Event{model.NewEvent("09:00|10:00|work|Coding", categories)}),
+	}
+	all := func(model.Category) bool { return true }
+
+	{
+		testcase := "by category, sorted by duration then name"
+		rows, err := Summarize(sunday, days, GroupByCategory, all, nil)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if len(rows) != 3 ||
+			rows[0].Category.Name != "work" || rows[0].Duration != 4*time.Hour || rows[0].Goal == nil || *rows[0].Goal != 24*time.Hour ||
+			rows[1].Category.Name != "eating" || rows[1].Duration != 90*time.Minute || rows[1].Goal != nil ||
+			rows[2].Category.Name != "admin" || rows[2].Group != "2022-12-04..2022-12-07" {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, rows)
+		}
+	}
+	{
+		testcase := "by week, limited to range"
+		rows, err := Summarize(sunday, days, GroupByWeek, func(cat model.Category) bool { return cat.Name == "work" }, nil)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if len(rows) != 1 || rows[0].Group != "2022-12-05" || rows[0].From != sunday.Next() || *rows[0].Goal != 24*time.Hour {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, rows)
+		}
+	}
+	{
+		testcase := "by day"
+		rows, err := Summarize(sunday, days, GroupByDay, all, nil)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		groups := make([]string, 0)
+		for _, row := range rows {
+			groups = append(groups, row.Group+":"+row.Category.Name)
+		}
+		expected := "2022-12-04:eating 2022-12-05:work 2022-12-05:admin 2022-12-05:eating 2022-12-07:work"
+		if strings.Join(groups, " ") != expected {
+			t.Fatalf("testcase '%s' failed: expected '%s', got '%s'", testcase, expected, strings.Join(groups, " "))
+		}
+	}
+	{
+		testcase := "by month"
+		rows, err := Summarize(sunday, days, GroupByMonth, all, nil)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if len(rows) != 3 || rows[0].Group != "2022-12" {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, rows)
+		}
+	}
+	{
+		testcase := "unknown grouping"
+		_, err := Summarize(sunday, days, GroupBy("year"), all, nil)
+		if err == nil {
+			t.Fatalf("testcase '%s' failed: expected error", testcase)
+		}
+	}
+}
+
+func TestSummarizeLimitsAndPatternGoals(t *testing.T) {
+	categories := []model.Category{
+		{Name: "work", Priority: 1},
+		{Name: "meeting", Priority: 2, Limit: &model.WeeklyGoal{Time: 5 * time.Hour}},
+	}
+	monday := model.Date{Year: 2022, Month: 12, Day: 5}
+	days := []*model.Day{
+		model.NewDayWithEvents([]*model.Event{
+			model.NewEvent("09:00|12:00|work|Coding #deep", categories),
+			model.NewEvent("13:00|15:00|meeting|Planning", categories),
+		}),
+		model.NewDayWithEvents([]*model.Event{
+			model.NewEvent("09:00|10:00|work|Review #deep", categories),
+			model.NewEvent("13:00|17:00|meeting|Workshop", categories),
+		}),
+	}
+	deep, err := model.NewPatternGoalFromConfig(config.PatternGoal{Name: "deep work", Tag: "deep", Goal: config.Goal{Daily: "2h"}, Limit: config.Goal{Daily: "3h"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	week := append(append([]*model.Day{}, days...), nil, nil, nil, nil, nil)
+	all := func(model.Category) bool { return true }
+
+	{
+		testcase := "limits over range"
+		rows, err := Summarize(monday, week, GroupByCategory, all, []model.PatternGoal{*deep})
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		// the meeting limit is the week's 5h
+		if len(rows) != 3 ||
+			rows[0].Category.Name != "meeting" || *rows[0].Limit != 5*time.Hour || rows[0].OverLimit() != time.Hour ||
+			rows[1].Category.Name != "work" || rows[1].Limit != nil || rows[1].OverLimit() != 0 ||
+			!rows[2].Pattern || rows[2].Category.Name != "deep work" || rows[2].Duration != 4*time.Hour || *rows[2].Goal != 14*time.Hour || rows[2].OverLimit() != 0 {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, rows)
+		}
+		warnings := Warnings(rows)
+		if len(warnings) != 1 || !strings.Contains(warnings[0], "'meeting'") {
+			t.Fatalf("testcase '%s' failed: got warnings %v", testcase, warnings)
+		}
+	}
+	{
+		testcase := "limits per day"
+		rows, err := Summarize(monday, days, GroupByDay, all, []model.PatternGoal{*deep})
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		var deepMonday *Row
+		for i := range rows {
+			if rows[i].Pattern && rows[i].Group == "2022-12-05" {
+				deepMonday = &rows[i]
+			}
+			// the weekly limit is not checked per day
+			if rows[i].Category.Name == "meeting" && rows[i].Limit != nil {
+				t.Fatalf("testcase '%s' failed: got limit %s for meeting on %s", testcase, *rows[i].Limit, rows[i].Group)
+			}
+		}
+		if deepMonday == nil || deepMonday.Limit == nil || deepMonday.OverLimit() != 0 || len(Warnings(rows)) != 0 {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, rows)
+		}
+	}
+	{
+		testcase := "weekly limit not checked on part of a week"
+		rows, err := Summarize(monday, days, GroupByCategory, all, nil)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if len(rows) != 2 || rows[0].Category.Name != "meeting" || rows[0].Limit != nil || len(Warnings(rows)) != 0 {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, rows)
+		}
+	}
+	{
+		testcase := "limits per week"
+		rows, err := Summarize(monday, week, GroupByWeek, all, nil)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if len(rows) != 2 || rows[0].Category.Name != "meeting" || rows[0].OverLimit() != time.Hour {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, rows)
+		}
+	}
+	{
+		testcase := "table shows warning"
+		rows, _ := Summarize(monday, week, GroupByCategory, all, []model.PatternGoal{*deep})
+		var buf bytes.Buffer
+		err := WriteTable(&buf, rows, true)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if !strings.Contains(buf.String(), "meeting (prio:  2):    6h 0min WARNING: over limit (5h0m0s) by 1h0m0s\n") ||
+			!strings.Contains(buf.String(), "deep work (pattern) :") {
+			t.Fatalf("testcase '%s' failed: got\n%s", testcase, buf.String())
+		}
+	}
+}
+
+func TestOutput(t *testing.T) {
+	goal := 8 * time.Hour
+	monday := model.Date{Year: 2022, Month: 12, Day: 5}
+	rows := []Row{
+		{Group: "2022-12-05", From: monday, Til: monday, Category: model.Category{Name: "work", Priority: 1}, Duration: 6 * time.Hour, Goal: &goal},
+		{Group: "2022-12-05", From: monday, Til: monday, Category: model.Category{Name: "eating", Priority: 2}, Duration: time.Hour},
+	}
+
+	{
+		testcase := "csv"
+		var buf bytes.Buffer
+		err := WriteCSV(&buf, rows)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		expected := "group,from,til,category,priority,minutes,goal_minutes,goal_percent,limit_minutes,over_limit_minutes,kind\n" +
+			"2022-12-05,2022-12-05,2022-12-05,work,1,360,480,75.00,,,category\n" +
+			"2022-12-05,2022-12-05,2022-12-05,eating,2,60,,,,,category\n"
+		if buf.String() != expected {
+			t.Fatalf("testcase '%s' failed: expected\n%s\ngot\n%s", testcase, expected, buf.String())
+		}
+	}
+	{
+		testcase := "json"
+		var buf bytes.Buffer
+		err := WriteJSON(&buf, rows)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if !strings.Contains(buf.String(), `"goal_minutes": 480`) || strings.Count(buf.String(), "goal_minutes") != 1 {
+			t.Fatalf("testcase '%s' failed: got\n%s", testcase, buf.String())
+		}
+	}
+	{
+		testcase := "table"
+		var buf bytes.Buffer
+		err := WriteTable(&buf, rows, true)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		expected := "                  work (prio:  1):    6h 0min (75.00% of goal, 2h0m0s deficit)\n" +
+			"                eating (prio:  2):    1h 0min \n"
+		if buf.String() != expected {
+			t.Fatalf("testcase '%s' failed: expected\n%q\ngot\n%q", testcase, expected, buf.String())
+		}
+	}
+}
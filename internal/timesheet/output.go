@@ -0,0 +1,189 @@
+package timesheet
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/util"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// BuiltinTemplateNames returns the names of the built-in output templates.
+func BuiltinTemplateNames() []string {
+	entries, _ := builtinTemplates.ReadDir("templates")
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, strings.TrimSuffix(entry.Name(), ".tmpl"))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Day is a day of a timesheet.
+type Day struct {
+	Date  model.Date
+	Entry model.TimesheetEntry
+	// Absence is the kind of absence on the day (e.g. "vacation"), if any.
+	Absence string
+}
+
+// Empty returns whether no time was worked on the day.
+func (d Day) Empty() bool {
+	return d.Entry.IsEmpty()
+}
+
+// Work returns the duration worked on the day.
+func (d Day) Work() time.Duration {
+	if d.Empty() {
+		return 0
+	}
+	return WorkDuration(d.Entry)
+}
+
+// Week is a week (Monday through Sunday) of a timesheet, containing only the
+// timesheet's days.
+type Week struct {
+	Start model.Date
+	Days  []Day
+}
+
+// Work returns the duration worked in the week.
+func (w Week) Work() time.Duration {
+	return sumWork(w.Days)
+}
+
+// Data is the data a timesheet template is executed on.
+type Data struct {
+	From, Til model.Date
+	// Project identifies what the timesheet is for, e.g. a client project.
+	Project string
+	Days    []Day
+}
+
+// Weeks returns the timesheet's days grouped by week.
+func (d Data) Weeks() []Week {
+	result := make([]Week, 0)
+	for _, day := range d.Days {
+		monday, _ := day.Date.WeekBounds()
+		if len(result) == 0 || result[len(result)-1].Start != monday {
+			result = append(result, Week{Start: monday})
+		}
+		result[len(result)-1].Days = append(result[len(result)-1].Days, day)
+	}
+	return result
+}
+
+// Work returns the total duration worked.
+func (d Data) Work() time.Duration {
+	return sumWork(d.Days)
+}
+
+// Break returns the total duration of breaks.
+func (d Data) Break() time.Duration {
+	sum := time.Duration(0)
+	for _, day := range d.Days {
+		sum += day.Entry.BreakDuration
+	}
+	return sum
+}
+
+func sumWork(days []Day) time.Duration {
+	sum := time.Duration(0)
+	for _, day := range days {
+		sum += day.Work()
+	}
+	return sum
+}
+
+// FormatOptions define how the template functions format values.
+type FormatOptions struct {
+	// DateFormat is the format for dates (see time.Time.Format).
+	DateFormat string
+	// DurationFormat is "golang" (e.g. "1h30m") or "colon-delimited" (e.g.
+	// "1:30").
+	DurationFormat string
+	// Enquote defines whether fields are enquoted.
+	Enquote bool
+	// Separator is the field separator.
+	Separator string
+}
+
+// LoadTemplate returns the text of the built-in template of the given name or,
+// if there is none, of the template file at the given path.
+func LoadTemplate(nameOrPath string) (string, error) {
+	builtin, err := builtinTemplates.ReadFile("templates/" + nameOrPath + ".tmpl")
+	if err == nil {
+		return string(builtin), nil
+	}
+	fromFile, err := os.ReadFile(nameOrPath)
+	if err != nil {
+		return "", fmt.Errorf("'%s' is neither a built-in template (%s) nor a readable file (%w)", nameOrPath, strings.Join(BuiltinTemplateNames(), ", "), err)
+	}
+	return string(fromFile), nil
+}
+
+// ShowsAbsences returns whether the given template text shows the kind of
+// absence of days (i.e., refers to .Absence), so that days of absence without
+// an entry are worth listing.
+func ShowsAbsences(templateText string) bool {
+	return strings.Contains(templateText, ".Absence")
+}
+
+// Write executes the given template text on the given data and writes the
+// result.
+//
+// Besides the data (see Data), templates can use the functions
+//   - date: formats a date per the date format
+//   - clock: formats a timestamp as "HH:MM"
+//   - duration: formats a duration per the duration format
+//   - hours: formats a duration as decimal hours, e.g. "7.50"
+//   - field: enquotes a string, if enquoting is enabled
+//   - sep: returns the field separator
+func Write(w io.Writer, templateText string, data Data, opts FormatOptions) error {
+	tmpl, err := template.New("timesheet").Funcs(template.FuncMap{
+		"date":  func(d model.Date) string { return d.ToGotime().Format(opts.DateFormat) },
+		"clock": func(ts model.Timestamp) string { return ts.ToString() },
+		"duration": func(d time.Duration) (string, error) {
+			return formatDurationAs(d, opts.DurationFormat)
+		},
+		"hours": func(d time.Duration) string { return fmt.Sprintf("%.2f", d.Hours()) },
+		"field": func(s string) string {
+			if opts.Enquote {
+				return util.Enquote(s)
+			}
+			return s
+		},
+		"sep": func() string { return opts.Separator },
+	}).Parse(templateText)
+	if err != nil {
+		return fmt.Errorf("invalid template (%w)", err)
+	}
+	return tmpl.Execute(w, data)
+}
+
+// formatDurationAs formats the given duration in the given format.
+func formatDurationAs(d time.Duration, format string) (string, error) {
+	switch format {
+	case "golang", "":
+		str := d.String()
+		if strings.HasSuffix(str, "m0s") {
+			str = strings.TrimSuffix(str, "0s")
+		}
+		return str, nil
+	case "colon-delimited":
+		hours := d.Truncate(time.Hour)
+		minutes := d - hours
+		return fmt.Sprintf("%d:%02d", int(hours.Hours()), int(minutes.Minutes())), nil
+	default:
+		return "", fmt.Errorf("unknown duration format '%s'", format)
+	}
+}
@@ -0,0 +1,127 @@
+package timesheet
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func testData() Data {
+	entry := func(start string, breakDuration time.Duration, end string) model.TimesheetEntry {
+		return model.TimesheetEntry{Start: *model.NewTimestamp(start), BreakDuration: breakDuration, End: *model.NewTimestamp(end)}
+	}
+	friday := model.Date{Year: 2022, Month: 12, Day: 9}
+	return Data{
+		From: friday,
+		Til:  friday.Forward(3),
+		Days: []Day{
+			{Date: friday, Entry: entry("08:00", 30*time.Minute, "16:30")},
+			{Date: friday.Forward(3), Entry: entry("09:00", 45*time.Minute, "12:45")},
+			{Date: friday.Forward(4), Absence: "vacation"},
+		},
+	}
+}
+
+func write(t *testing.T, name string, opts FormatOptions) string {
+	text, err := LoadTemplate(name)
+	if err != nil {
+		t.Fatalf("unexpected error loading template '%s': %s", name, err.Error())
+	}
+	var b strings.Builder
+	err = Write(&b, text, testData(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error writing template '%s': %s", name, err.Error())
+	}
+	return b.String()
+}
+
+func TestWrite(t *testing.T) {
+	opts := FormatOptions{DateFormat: "2006-01-02", DurationFormat: "golang", Separator: ","}
+
+	{
+		testcase := "csv"
+		expected := "2022-12-09,08:00,30m,16:30\n" +
+			"2022-12-12,09:00,45m,12:45\n" +
+			"2022-12-13,00:00,0s,00:00\n"
+		if result := write(t, testcase, opts); result != expected {
+			t.Fatalf("testcase '%s' failed: expected\n%s\ngot\n%s", testcase, expected, result)
+		}
+	}
+	{
+		testcase := "csv-absences"
+		expected := "2022-12-09,08:00,30m,16:30,\n" +
+			"2022-12-12,09:00,45m,12:45,\n" +
+			"2022-12-13,00:00,0s,00:00,vacation\n"
+		if result := write(t, testcase, opts); result != expected {
+			t.Fatalf("testcase '%s' failed: expected\n%s\ngot\n%s", testcase, expected, result)
+		}
+	}
+	{
+		testcase := "csv, enquoted and colon-delimited"
+		opts := opts
+		opts.Enquote, opts.DurationFormat, opts.Separator = true, "colon-delimited", ";"
+		expected := `"2022-12-09";"08:00";"0:30";"16:30"` + "\n"
+		if result := write(t, "csv", opts); !strings.HasPrefix(result, expected) {
+			t.Fatalf("testcase '%s' failed: expected prefix\n%s\ngot\n%s", testcase, expected, result)
+		}
+	}
+	{
+		testcase := "markdown"
+		result := write(t, "markdown", opts)
+		if !strings.Contains(result, "| 2022-12-09 | 08:00 | 30m | 16:30 | 8.00 |  |\n") ||
+			!strings.Contains(result, "| 2022-12-13 |  |  |  | 0.00 | vacation |\n") ||
+			!strings.HasSuffix(result, "| **Total** | | 1h15m | | **11.00** | |\n") {
+			t.Fatalf("testcase '%s' failed: got\n%s", testcase, result)
+		}
+	}
+	{
+		testcase := "weekly-csv"
+		result := write(t, "weekly-csv", opts)
+		if !strings.Contains(result, "2022-12-05,subtotal,,,,8.00,\n") ||
+			!strings.Contains(result, "2022-12-12,subtotal,,,,3.00,\n") ||
+			!strings.HasSuffix(result, "total,,,,,11.00,\n") {
+			t.Fatalf("testcase '%s' failed: got\n%s", testcase, result)
+		}
+	}
+	{
+		testcase := "custom"
+		var b strings.Builder
+		data := testData()
+		data.Project = "acme"
+		err := Write(&b, `{{.Project}}: {{len .Weeks}} weeks, {{hours .Work}}h`, data, opts)
+		if err != nil || b.String() != "acme: 2 weeks, 11.00h" {
+			t.Fatalf("testcase '%s' failed: got '%s' (err: %v)", testcase, b.String(), err)
+		}
+	}
+	{
+		testcase := "invalid"
+		var b strings.Builder
+		if err := Write(&b, `{{.Nonsense`, testData(), opts); err == nil {
+			t.Fatalf("testcase '%s' failed: expected error", testcase)
+		}
+		if _, err := LoadTemplate("/nonexistent/template"); err == nil {
+			t.Fatalf("testcase '%s' failed: expected error for unknown template", testcase)
+		}
+	}
+}
+
+func TestBuiltinTemplateNames(t *testing.T) {
+	names := BuiltinTemplateNames()
+	if strings.Join(names, " ") != "csv csv-absences markdown weekly-csv" {
+		t.Fatalf("unexpected built-in template names: %v", names)
+	}
+}
+
+func TestShowsAbsences(t *testing.T) {
+	for name, expected := range map[string]bool{"csv": false, "csv-absences": true, "markdown": true, "weekly-csv": true} {
+		text, err := LoadTemplate(name)
+		if err != nil {
+			t.Fatalf("unexpected error loading template '%s': %s", name, err.Error())
+		}
+		if ShowsAbsences(text) != expected {
+			t.Fatalf("expected template '%s' to show absences: %t", name, expected)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+// Package timesheet provides the rules that timesheet entries are subject to,
+// i.e., how they are rounded and which labor-law rules (minimum breaks,
+// maximum work duration) they are checked against, and the templated output
+// of timesheets.
+package timesheet
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// RoundingMode defines in which direction to round.
+type RoundingMode string
+
+const (
+	RoundNearest RoundingMode = "nearest"
+	RoundUp      RoundingMode = "up"
+	RoundDown    RoundingMode = "down"
+)
+
+// Rounding defines rounding to a multiple of a duration.
+// The zero value does not round.
+type Rounding struct {
+	Mode RoundingMode
+	To   time.Duration
+}
+
+// RoundDuration rounds the given duration.
+func (r Rounding) RoundDuration(d time.Duration) time.Duration {
+	if r.To <= 0 {
+		return d
+	}
+	switch r.Mode {
+	case RoundUp:
+		if rest := d % r.To; rest != 0 {
+			return d - rest + r.To
+		}
+		return d
+	case RoundDown:
+		return d - d%r.To
+	default:
+		return d.Round(r.To)
+	}
+}
+
+// RoundTimestamp rounds the given timestamp (as a duration since midnight),
+// keeping it within the day.
+func (r Rounding) RoundTimestamp(ts model.Timestamp) model.Timestamp {
+	minutes := int(r.RoundDuration(time.Duration(ts.Hour*60+ts.Minute)*time.Minute) / time.Minute)
+	if minutes > 23*60+59 {
+		minutes = 23*60 + 59
+	}
+	return model.Timestamp{Hour: minutes / 60, Minute: minutes % 60}
+}
+
+// BreakRule requires breaks of a minimum total duration when working for
+// more than a given duration.
+type BreakRule struct {
+	After   time.Duration
+	Minimum time.Duration
+}
+
+// Rules are the rounding and compliance rules for timesheet entries.
+type Rules struct {
+	StartRounding Rounding
+	EndRounding   Rounding
+	BreakRounding Rounding
+
+	// Breaks are the break rules, sorted by ascending work duration.
+	Breaks []BreakRule
+	// MaxWork is the maximum work duration per day, if non-zero.
+	MaxWork time.Duration
+	// Adjust defines whether violating entries are adjusted (rather than only
+	// flagged).
+	Adjust bool
+}
+
+// NewRulesFromConfig constructs timesheet rules from config data.
+func NewRulesFromConfig(cfg config.Timesheet) (*Rules, error) {
+	result := Rules{Adjust: cfg.Compliance.Adjust}
+
+	var err error
+	result.StartRounding, err = NewRoundingFromConfig(cfg.Rounding.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start rounding: %w", err)
+	}
+	result.EndRounding, err = NewRoundingFromConfig(cfg.Rounding.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end rounding: %w", err)
+	}
+	result.BreakRounding, err = NewRoundingFromConfig(cfg.Rounding.Break)
+	if err != nil {
+		return nil, fmt.Errorf("invalid break rounding: %w", err)
+	}
+
+	for i, rule := range cfg.Compliance.Breaks {
+		after, err := time.ParseDuration(rule.After)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing 'after' of break rule no. %d: %w", i, err)
+		}
+		minimum, err := time.ParseDuration(rule.Minimum)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing 'minimum' of break rule no. %d: %w", i, err)
+		}
+		result.Breaks = append(result.Breaks, BreakRule{After: after, Minimum: minimum})
+	}
+	sort.Slice(result.Breaks, func(i, j int) bool { return result.Breaks[i].After < result.Breaks[j].After })
+
+	if cfg.Compliance.MaxWork != "" {
+		maxWork, err := time.ParseDuration(cfg.Compliance.MaxWork)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing max-work: %w", err)
+		}
+		result.MaxWork = maxWork
+	}
+
+	return &result, nil
+}
+
+// NewRoundingFromConfig constructs a rounding from config data; an undefined
+// rounding does not round.
+func NewRoundingFromConfig(cfg config.Rounding) (Rounding, error) {
+	if cfg == (config.Rounding{}) {
+		return Rounding{}, nil
+	}
+	to, err := time.ParseDuration(cfg.To)
+	if err != nil {
+		return Rounding{}, fmt.Errorf("error parsing duration: %w", err)
+	}
+	if to <= 0 {
+		return Rounding{}, fmt.Errorf("duration %s is not positive", to)
+	}
+	switch mode := RoundingMode(cfg.Mode); mode {
+	case RoundNearest, RoundUp, RoundDown:
+		return Rounding{Mode: mode, To: to}, nil
+	case "":
+		return Rounding{Mode: RoundNearest, To: to}, nil
+	default:
+		return Rounding{}, fmt.Errorf("unknown mode '%s' (known: nearest, up, down)", cfg.Mode)
+	}
+}
+
+// HasCompliance returns whether any compliance rules are defined.
+func (r *Rules) HasCompliance() bool {
+	return len(r.Breaks) > 0 || r.MaxWork > 0
+}
+
+// Round returns the given entry rounded according to the rules.
+// Empty entries are not rounded.
+//
+// An entry shorter than the rounding may be rounded to end before it starts
+// (e.g. with the start rounded up and the end rounded down) or to a break
+// longer than itself; such an entry is rounded to no work at all, i.e., to end
+// at its start without a break.
+func (r *Rules) Round(e model.TimesheetEntry) model.TimesheetEntry {
+	if e.IsEmpty() {
+		return e
+	}
+	result := model.TimesheetEntry{
+		Start:         r.StartRounding.RoundTimestamp(e.Start),
+		BreakDuration: r.BreakRounding.RoundDuration(e.BreakDuration),
+		End:           r.EndRounding.RoundTimestamp(e.End),
+	}
+	if result.End.IsBefore(result.Start) {
+		result.End = result.Start
+	}
+	if span := result.Start.DurationUntil(result.End); result.BreakDuration > span {
+		result.BreakDuration = span
+	}
+	return result
+}
+
+// WorkDuration returns the duration worked according to the given entry, i.e.,
+// the time between start and end, minus breaks.
+func WorkDuration(e model.TimesheetEntry) time.Duration {
+	return e.Start.DurationUntil(e.End) - e.BreakDuration
+}
+
+// Violation is a violation of a compliance rule by a timesheet entry.
+type Violation struct {
+	// Problem describes the violation.
+	Problem string
+	// Adjustment describes how the entry was adjusted, if it was.
+	Adjustment string
+}
+
+// Check checks the given entry against the compliance rules and returns the
+// violations. If the rules are set to adjust, it also returns the entry
+// adjusted to comply, otherwise the entry as is.
+//
+// A break that is too short is extended (shortening the work duration); a
+// work duration beyond the maximum is cut short by moving the end earlier.
+func (r *Rules) Check(e model.TimesheetEntry) (model.TimesheetEntry, []Violation) {
+	violations := make([]Violation, 0)
+	if e.IsEmpty() {
+		return e, violations
+	}
+
+	work := WorkDuration(e)
+	var requiredBreak *BreakRule
+	for i := range r.Breaks {
+		if work > r.Breaks[i].After && (requiredBreak == nil || r.Breaks[i].Minimum > requiredBreak.Minimum) {
+			requiredBreak = &r.Breaks[i]
+		}
+	}
+	if requiredBreak != nil && e.BreakDuration < requiredBreak.Minimum {
+		violation := Violation{
+			Problem: fmt.Sprintf("%s of work with %s of breaks, but at least %s required after %s", formatDuration(work), formatDuration(e.BreakDuration), formatDuration(requiredBreak.Minimum), formatDuration(requiredBreak.After)),
+		}
+		if r.Adjust {
+			e.BreakDuration = requiredBreak.Minimum
+			violation.Adjustment = fmt.Sprintf("breaks extended to %s", formatDuration(e.BreakDuration))
+		}
+		violations = append(violations, violation)
+	}
+
+	work = WorkDuration(e)
+	if r.MaxWork > 0 && work > r.MaxWork {
+		violation := Violation{
+			Problem: fmt.Sprintf("%s of work, but at most %s allowed", formatDuration(work), formatDuration(r.MaxWork)),
+		}
+		if r.Adjust {
+			e.End = e.End.OffsetMinutes(-int((work - r.MaxWork) / time.Minute))
+			violation.Adjustment = fmt.Sprintf("end moved to %s", e.End.ToString())
+		}
+		violations = append(violations, violation)
+	}
+
+	return e, violations
+}
+
+// formatDuration formats a duration compactly, e.g. "6h30m" or "45m".
+func formatDuration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if minutes < 60 && minutes > -60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, abs(minutes%60))
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,144 @@
+package timesheet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/config"
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func TestRounding(t *testing.T) {
+	for _, tc := range []struct {
+		rounding Rounding
+		in       time.Duration
+		expected time.Duration
+	}{
+		{Rounding{}, 7 * time.Minute, 7 * time.Minute},
+		{Rounding{Mode: RoundNearest, To: 15 * time.Minute}, 7 * time.Minute, 0},
+		{Rounding{Mode: RoundNearest, To: 15 * time.Minute}, 8 * time.Minute, 15 * time.Minute},
+		{Rounding{Mode: RoundUp, To: 15 * time.Minute}, 1 * time.Minute, 15 * time.Minute},
+		{Rounding{Mode: RoundUp, To: 15 * time.Minute}, 30 * time.Minute, 30 * time.Minute},
+		{Rounding{Mode: RoundDown, To: 15 * time.Minute}, 29 * time.Minute, 15 * time.Minute},
+	} {
+		if result := tc.rounding.RoundDuration(tc.in); result != tc.expected {
+			t.Fatalf("rounding %v of %s: expected %s, got %s", tc.rounding, tc.in, tc.expected, result)
+		}
+	}
+
+	up := Rounding{Mode: RoundUp, To: 15 * time.Minute}
+	if ts := up.RoundTimestamp(model.Timestamp{Hour: 8, Minute: 52}); ts != (model.Timestamp{Hour: 9, Minute: 0}) {
+		t.Fatalf("expected 09:00, got %s", ts.ToString())
+	}
+	if ts := up.RoundTimestamp(model.Timestamp{Hour: 23, Minute: 50}); ts != (model.Timestamp{Hour: 23, Minute: 59}) {
+		t.Fatalf("expected rounding to stay within the day, got %s", ts.ToString())
+	}
+}
+
+func TestRound(t *testing.T) {
+	rules := Rules{
+		StartRounding: Rounding{Mode: RoundUp, To: 15 * time.Minute},
+		EndRounding:   Rounding{Mode: RoundDown, To: 15 * time.Minute},
+		BreakRounding: Rounding{Mode: RoundUp, To: 15 * time.Minute},
+	}
+	{
+		testcase := "regular entry"
+		e := rules.Round(model.TimesheetEntry{Start: *model.NewTimestamp("08:52"), BreakDuration: 25 * time.Minute, End: *model.NewTimestamp("16:40")})
+		if e.Start != *model.NewTimestamp("09:00") || e.BreakDuration != 30*time.Minute || e.End != *model.NewTimestamp("16:30") {
+			t.Fatalf("testcase '%s' failed: got %s, %s, %s", testcase, e.Start.ToString(), e.BreakDuration, e.End.ToString())
+		}
+	}
+	{
+		testcase := "entry shorter than the rounding"
+		e := rules.Round(model.TimesheetEntry{Start: *model.NewTimestamp("10:05"), BreakDuration: 2 * time.Minute, End: *model.NewTimestamp("10:10")})
+		if e.End.IsBefore(e.Start) || WorkDuration(e) != 0 || e.BreakDuration != 0 {
+			t.Fatalf("testcase '%s' failed: got %s, %s, %s (work %s)", testcase, e.Start.ToString(), e.BreakDuration, e.End.ToString(), WorkDuration(e))
+		}
+	}
+}
+
+func TestNewRulesFromConfig(t *testing.T) {
+	{
+		testcase := "full"
+		rules, err := NewRulesFromConfig(config.Timesheet{
+			Rounding: config.TimesheetRounding{
+				Start: config.Rounding{Mode: "down", To: "15m"},
+				Break: config.Rounding{To: "5m"},
+			},
+			Compliance: config.TimesheetCompliance{
+				Breaks:  []config.BreakRule{{After: "9h", Minimum: "45m"}, {After: "6h", Minimum: "30m"}},
+				MaxWork: "10h",
+			},
+		})
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if rules.StartRounding != (Rounding{Mode: RoundDown, To: 15 * time.Minute}) ||
+			rules.EndRounding != (Rounding{}) ||
+			rules.BreakRounding != (Rounding{Mode: RoundNearest, To: 5 * time.Minute}) ||
+			len(rules.Breaks) != 2 || rules.Breaks[0].After != 6*time.Hour ||
+			rules.MaxWork != 10*time.Hour || !rules.HasCompliance() {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, rules)
+		}
+	}
+	for _, invalid := range []config.Timesheet{
+		{Rounding: config.TimesheetRounding{End: config.Rounding{Mode: "sideways", To: "15m"}}},
+		{Rounding: config.TimesheetRounding{End: config.Rounding{Mode: "up"}}},
+		{Compliance: config.TimesheetCompliance{Breaks: []config.BreakRule{{After: "6h"}}}},
+		{Compliance: config.TimesheetCompliance{MaxWork: "long"}},
+	} {
+		_, err := NewRulesFromConfig(invalid)
+		if err == nil {
+			t.Fatalf("expected error for invalid config %v", invalid)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rules := Rules{
+		Breaks:  []BreakRule{{After: 6 * time.Hour, Minimum: 30 * time.Minute}, {After: 9 * time.Hour, Minimum: 45 * time.Minute}},
+		MaxWork: 10 * time.Hour,
+	}
+	entry := func(start string, breakDuration time.Duration, end string) model.TimesheetEntry {
+		return model.TimesheetEntry{Start: *model.NewTimestamp(start), BreakDuration: breakDuration, End: *model.NewTimestamp(end)}
+	}
+
+	{
+		testcase := "compliant"
+		_, violations := rules.Check(entry("08:00", 30*time.Minute, "16:30"))
+		if len(violations) != 0 {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, violations)
+		}
+	}
+	{
+		testcase := "short break, flagged only"
+		e := entry("08:00", 15*time.Minute, "17:30")
+		result, violations := rules.Check(e)
+		if len(violations) != 1 || violations[0].Adjustment != "" || result != e {
+			t.Fatalf("testcase '%s' failed: got %v, %v", testcase, result, violations)
+		}
+	}
+	rules.Adjust = true
+	{
+		testcase := "short break, adjusted"
+		result, violations := rules.Check(entry("08:00", 15*time.Minute, "17:30"))
+		// 9h15m of work requires 45m
+		if len(violations) != 1 || result.BreakDuration != 45*time.Minute || violations[0].Adjustment == "" {
+			t.Fatalf("testcase '%s' failed: got %v, %v", testcase, result, violations)
+		}
+	}
+	{
+		testcase := "too much work, adjusted"
+		result, violations := rules.Check(entry("07:00", time.Hour, "18:30"))
+		if len(violations) != 1 || result.End != (model.Timestamp{Hour: 18, Minute: 0}) {
+			t.Fatalf("testcase '%s' failed: got %v, %v", testcase, result, violations)
+		}
+	}
+	{
+		testcase := "empty"
+		_, violations := rules.Check(model.TimesheetEntry{})
+		if len(violations) != 0 {
+			t.Fatalf("testcase '%s' failed: got %v", testcase, violations)
+		}
+	}
+}
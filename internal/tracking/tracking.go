@@ -0,0 +1,131 @@
+// Package tracking provides live time tracking, i.e., the persisted state of
+// an event that has been started but not yet stopped.
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// FileName is the name of the file (in the dayplan base directory) that the
+// running event is persisted in.
+const FileName = "tracking"
+
+// Running is an event that is being tracked live, i.e., that has been started
+// but not yet stopped.
+//
+// The event itself is stored in its day (with an end time that is extended as
+// time passes); Running identifies it by its start, category and name.
+type Running struct {
+	Date     model.Date
+	Start    model.Timestamp
+	Category string
+	Name     string
+}
+
+// Read reads the running event from the given file.
+// If there is no running event (i.e., the file does not exist), it returns
+// nil.
+func Read(path string) (*Running, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read tracking file '%s' (%w)", path, err)
+	}
+	return fromString(strings.TrimRight(string(data), "\n"))
+}
+
+// Write persists the given running event to the given file.
+func Write(path string, r Running) error {
+	err := os.WriteFile(path, []byte(r.toString()+"\n"), 0644)
+	if err != nil {
+		return fmt.Errorf("could not write tracking file '%s' (%w)", path, err)
+	}
+	return nil
+}
+
+// Clear removes the running event persisted in the given file, if any.
+func Clear(path string) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not remove tracking file '%s' (%w)", path, err)
+	}
+	return nil
+}
+
+// Matches returns whether the given event is the running event.
+func (r *Running) Matches(e *model.Event) bool {
+	return e.Start == r.Start && e.Cat.Name == r.Category && e.Name == r.Name
+}
+
+// Find returns the running event in the given day (which should be the day
+// for the running event's date), or nil if it is not contained.
+func (r *Running) Find(day *model.Day) *model.Event {
+	for _, e := range day.Events {
+		if r.Matches(e) {
+			return e
+		}
+	}
+	return nil
+}
+
+// EndAt returns the end time the running event should have at the given date
+// and time.
+// Running events last at least a minute and end at 23:59 at the latest, as
+// events cannot span multiple days.
+func (r *Running) EndAt(date model.Date, now model.Timestamp) model.Timestamp {
+	lastMinute := model.Timestamp{Hour: 23, Minute: 59}
+	switch {
+	case date.IsAfter(r.Date):
+		return lastMinute
+	case now.IsAfter(r.Start):
+		return now
+	case r.Start.IsBefore(lastMinute):
+		return r.Start.OffsetMinutes(1)
+	default:
+		return lastMinute
+	}
+}
+
+// ToEvent returns the running event as it should be at the given date and
+// time.
+func (r *Running) ToEvent(date model.Date, now model.Timestamp, knownCategories []model.Category) *model.Event {
+	e := &model.Event{
+		Name:  r.Name,
+		Cat:   model.Category{Name: r.Category},
+		Start: r.Start,
+		End:   r.EndAt(date, now),
+	}
+	for _, cat := range knownCategories {
+		if cat.Name == r.Category {
+			e.Cat = cat
+		}
+	}
+	return e
+}
+
+func (r *Running) toString() string {
+	return r.Date.ToString() + "|" + r.Start.ToString() + "|" + r.Category + "|" + r.Name
+}
+
+func fromString(s string) (*Running, error) {
+	fields := strings.SplitN(s, "|", 4)
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("malformed tracking entry '%s'", s)
+	}
+	date, err := model.FromString(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed date in tracking entry '%s' (%w)", s, err)
+	}
+	start, err := model.TimestampFromString(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed start in tracking entry '%s' (%w)", s, err)
+	}
+	return &Running{Date: date, Start: start, Category: fields[2], Name: fields[3]}, nil
+}
@@ -0,0 +1,76 @@
+package tracking
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+func TestPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	{
+		testcase := "read without file"
+		r, err := Read(path)
+		if err != nil || r != nil {
+			t.Fatalf("testcase '%s' failed: got %v, %v", testcase, r, err)
+		}
+	}
+	{
+		testcase := "write and read back"
+		written := Running{
+			Date:     model.Date{Year: 2022, Month: 12, Day: 5},
+			Start:    model.Timestamp{Hour: 9, Minute: 12},
+			Category: "work",
+			Name:     "review | part 2",
+		}
+		err := Write(path, written)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		read, err := Read(path)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		if read == nil || *read != written {
+			t.Fatalf("testcase '%s' failed: wrote %v, read %v", testcase, written, read)
+		}
+	}
+	{
+		testcase := "clear"
+		err := Clear(path)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed with error: %s", testcase, err.Error())
+		}
+		r, err := Read(path)
+		if err != nil || r != nil {
+			t.Fatalf("testcase '%s' failed: got %v, %v", testcase, r, err)
+		}
+		err = Clear(path)
+		if err != nil {
+			t.Fatalf("testcase '%s' failed on repeated clear: %s", testcase, err.Error())
+		}
+	}
+}
+
+func TestEndAt(t *testing.T) {
+	date := model.Date{Year: 2022, Month: 12, Day: 5}
+	r := Running{Date: date, Start: model.Timestamp{Hour: 9, Minute: 12}, Category: "work", Name: "review"}
+
+	for _, tc := range []struct {
+		name     string
+		date     model.Date
+		now      model.Timestamp
+		expected model.Timestamp
+	}{
+		{"later same day", date, model.Timestamp{Hour: 10, Minute: 40}, model.Timestamp{Hour: 10, Minute: 40}},
+		{"just started", date, model.Timestamp{Hour: 9, Minute: 12}, model.Timestamp{Hour: 9, Minute: 13}},
+		{"next day", date.Next(), model.Timestamp{Hour: 1, Minute: 0}, model.Timestamp{Hour: 23, Minute: 59}},
+	} {
+		result := r.EndAt(tc.date, tc.now)
+		if result != tc.expected {
+			t.Fatalf("testcase '%s' failed: expected %s, got %s", tc.name, tc.expected.ToString(), result.ToString())
+		}
+	}
+}
@@ -17,7 +17,17 @@ type ScreenHandler struct {
 
 // NewTUIScreenHandler initializes and returns a TUIScreenHandler.
 func NewTUIScreenHandler() *ScreenHandler {
-	r := &ScreenHandler{}
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		log.Fatalf("%+v", err)
+	}
+	return NewTUIScreenHandlerFor(screen)
+}
+
+// NewTUIScreenHandlerFor initializes and returns a TUIScreenHandler for the
+// given (uninitialized) screen, e.g. a tcell.SimulationScreen.
+func NewTUIScreenHandlerFor(screen tcell.Screen) *ScreenHandler {
+	r := &ScreenHandler{screen: screen}
 	r.init()
 
 	return r
@@ -26,12 +36,7 @@ func NewTUIScreenHandler() *ScreenHandler {
 // Initialize the screen checking errors and return it, so long as no critical
 // error occurred.
 func (s *ScreenHandler) init() {
-	var err error
-	s.screen, err = tcell.NewScreen()
-	if err != nil {
-		log.Fatalf("%+v", err)
-	}
-	err = s.screen.Init()
+	err := s.screen.Init()
 	if err != nil {
 		log.Fatalf("%+v", err)
 	}
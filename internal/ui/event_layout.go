@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/ja-he/dayplan/internal/model"
+)
+
+// EventStackDepths returns for each of the given events (which are expected to
+// be ordered, as they are in a model.Day) how deep it is stacked, i.e., on top
+// of how many earlier events, that have not ended yet when it starts, it is
+// to be drawn.
+func EventStackDepths(events []*model.Event) map[*model.Event]int {
+	activeStack := make([]*model.Event, 0)
+	depths := make(map[*model.Event]int)
+	for _, e := range events {
+		// remove all stacked elements that have finished
+		for i := len(activeStack) - 1; i >= 0; i-- {
+			if e.Start.IsAfter(activeStack[i].End) || e.Start == activeStack[i].End {
+				activeStack = activeStack[:i]
+			} else {
+				break
+			}
+		}
+		activeStack = append(activeStack, e)
+		depths[e] = len(activeStack) - 1
+	}
+	return depths
+}
+
+// EventWidthFactor returns the factor by which the width of an event at the
+// given stack depth is to be scaled.
+//
+// The width is scaled by 3/4 for every item stacked underneath, so for one
+// item stacked underneath the event's width will be (3/4) ** 1 = 75% of the
+// original width, for four it would be (3/4) ** 4 = (3**4)/(4**4) or 31.5 % of
+// the width, etc.
+// Events are expected to be right-aligned, so that the events underneath
+// remain visible on the left.
+func EventWidthFactor(stackDepth int) float64 {
+	return math.Pow(0.75, float64(stackDepth))
+}
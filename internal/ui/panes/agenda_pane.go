@@ -0,0 +1,266 @@
+package panes
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ja-he/dayplan/internal/input"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/styling"
+	"github.com/ja-he/dayplan/internal/ui"
+	"github.com/ja-he/dayplan/internal/util"
+)
+
+// AgendaPane lists the events of a number of days from a given date on, as
+// rows under a header per day, along with the deadlines of the backlog's tasks
+// that fall on these days.
+// One of the rows is selected, for it to be jumped to.
+//
+// The rows are gathered anew on every draw, so they always reflect the
+// current state of the days and the backlog.
+type AgendaPane struct {
+	ui.LeafPane
+
+	from    func() model.Date
+	nDays   int
+	day     func(model.Date) *model.Day
+	backlog *model.Backlog
+
+	categories *styling.CategoryStyling
+
+	selected     int
+	scrollOffset int
+}
+
+// agendaRow is a row of the agenda, which is either the header of its date
+// (if it has neither event nor task), an event of the date or a task due on
+// the date.
+type agendaRow struct {
+	date  model.Date
+	event *model.Event
+	task  *model.Task
+}
+
+// Draw draws the agenda.
+func (p *AgendaPane) Draw() {
+	x, y, w, h := p.Dimensions()
+	p.Renderer.DrawBox(x, y, w, h, p.Stylesheet.Normal)
+
+	rows := p.rows()
+	p.clampSelection(len(rows))
+	listHeight := h - 1
+	if p.selected < p.scrollOffset {
+		p.scrollOffset = p.selected
+	}
+	if p.selected >= p.scrollOffset+listHeight {
+		p.scrollOffset = p.selected - listHeight + 1
+	}
+
+	for i := p.scrollOffset; i < len(rows) && i-p.scrollOffset < listHeight; i++ {
+		p.drawRow(rows[i], x, y+1+i-p.scrollOffset, w, i == p.selected)
+	}
+
+	// draw title last
+	titleStyle := p.Stylesheet.NormalEmphasized.DefaultEmphasized()
+	from := p.from()
+	title := fmt.Sprintf("Agenda %s..%s", from.ToString(), from.Forward(p.nDays-1).ToString())
+	p.Renderer.DrawBox(x, y, w, 1, titleStyle)
+	p.Renderer.DrawText(x+(w/2)-(len(title)/2), y, len(title), 1, titleStyle.Bolded(), title)
+}
+
+func (p *AgendaPane) drawRow(row agendaRow, x, y, w int, selected bool) {
+	style := p.Stylesheet.Normal
+	switch {
+	case row.event == nil && row.task == nil:
+		style = p.Stylesheet.NormalEmphasized.Bolded()
+	case row.task != nil:
+		style = p.Stylesheet.Normal.Italicized()
+	}
+	if selected {
+		style = style.Invert()
+	}
+	p.Renderer.DrawBox(x, y, w, 1, style)
+
+	const timeWidth = len("00:00-00:00")
+	var left, right string
+	var cat *model.Category
+	switch {
+	case row.event != nil:
+		left = fmt.Sprintf("%s-%s", row.event.Start.ToString(), row.event.End.ToString())
+		right = util.DurationToString(row.event.Duration())
+		cat = &row.event.Cat
+		name := row.event.Name
+		if name == "" {
+			name = row.event.Cat.Name
+		}
+		left = fmt.Sprintf("%-*s      %s", timeWidth, left, name)
+	case row.task != nil:
+		left = fmt.Sprintf("%-*s      %s", timeWidth, "due "+model.FromTime(*row.task.Deadline).Timestamp.ToString(), row.task.Name)
+		right = "deadline"
+		cat = &row.task.Category
+	default:
+		left = fmt.Sprintf("%s %s", row.date.ToWeekday().String()[:3], row.date.ToString())
+		if day := p.day(row.date); day == nil {
+			right = "(loading)"
+		} else if len(day.Events) == 0 {
+			right = "(no events)"
+		} else {
+			total := 0
+			for _, minutes := range day.SumUpByCategory() {
+				total += minutes
+			}
+			right = "total: " + util.DurationToString(total)
+		}
+		p.Renderer.DrawText(x+1, y, w-len(right)-3, 1, style, util.TruncateAt(left, w-len(right)-3))
+		p.Renderer.DrawText(x+w-len(right)-1, y, len(right), 1, style, right)
+		return
+	}
+
+	p.Renderer.DrawText(x+3, y, w-len(right)-5, 1, style, util.TruncateAt(left, w-len(right)-5))
+	p.Renderer.DrawBox(x+3+timeWidth+2, y, 2, 1, p.styleFor(*cat))
+	p.Renderer.DrawText(x+w-len(right)-1, y, len(right), 1, style, right)
+}
+
+// rows gathers the rows of the agenda; days that are not loaded (yet) only
+// have their header.
+func (p *AgendaPane) rows() []agendaRow {
+	from := p.from()
+	until := from.Forward(p.nDays - 1)
+
+	tasksByDate := make(map[model.Date][]*model.Task)
+	func() {
+		p.backlog.Mtx.RLock()
+		defer p.backlog.Mtx.RUnlock()
+		var collect func(tasks []*model.Task)
+		collect = func(tasks []*model.Task) {
+			for _, task := range tasks {
+				if task.Deadline != nil {
+					date := model.FromTime(*task.Deadline).Date
+					if !date.IsBefore(from) && !date.IsAfter(until) {
+						tasksByDate[date] = append(tasksByDate[date], task)
+					}
+				}
+				collect(task.Subtasks)
+			}
+		}
+		collect(p.backlog.Tasks)
+	}()
+
+	rows := make([]agendaRow, 0)
+	for date := from; !date.IsAfter(until); date = date.Next() {
+		rows = append(rows, agendaRow{date: date})
+		if day := p.day(date); day != nil {
+			for _, event := range day.Events {
+				rows = append(rows, agendaRow{date: date, event: event})
+			}
+		}
+		tasks := tasksByDate[date]
+		sort.Sort(model.TasksByDeadline(tasks))
+		for _, task := range tasks {
+			rows = append(rows, agendaRow{date: date, task: task})
+		}
+	}
+	return rows
+}
+
+func (p *AgendaPane) clampSelection(nRows int) {
+	if p.selected >= nRows {
+		p.selected = nRows - 1
+	}
+	if p.selected < 0 {
+		p.selected = 0
+	}
+}
+
+// SelectNext selects the next row.
+func (p *AgendaPane) SelectNext() {
+	p.selected++
+	p.clampSelection(len(p.rows()))
+}
+
+// SelectPrevious selects the previous row.
+func (p *AgendaPane) SelectPrevious() {
+	p.selected--
+	p.clampSelection(len(p.rows()))
+}
+
+// SelectFirst selects the first row.
+func (p *AgendaPane) SelectFirst() { p.selected = 0 }
+
+// SelectLast selects the last row.
+func (p *AgendaPane) SelectLast() { p.selected = len(p.rows()) - 1 }
+
+// SelectNextDay selects the header of the next day.
+func (p *AgendaPane) SelectNextDay() {
+	rows := p.rows()
+	p.clampSelection(len(rows))
+	for i := p.selected + 1; i < len(rows); i++ {
+		if rows[i].event == nil && rows[i].task == nil {
+			p.selected = i
+			return
+		}
+	}
+}
+
+// SelectPreviousDay selects the header of the selected row's day or, if the
+// header is selected, the previous day's.
+func (p *AgendaPane) SelectPreviousDay() {
+	rows := p.rows()
+	p.clampSelection(len(rows))
+	for i := p.selected - 1; i >= 0; i-- {
+		if rows[i].event == nil && rows[i].task == nil {
+			p.selected = i
+			return
+		}
+	}
+}
+
+// Selected returns the date of the selected row and its event or task, if it
+// has one (i.e., if it is not the date's header).
+func (p *AgendaPane) Selected() (model.Date, *model.Event, *model.Task) {
+	rows := p.rows()
+	p.clampSelection(len(rows))
+	row := rows[p.selected]
+	return row.date, row.event, row.task
+}
+
+func (p *AgendaPane) styleFor(cat model.Category) styling.DrawStyling {
+	style, err := p.categories.GetStyle(cat)
+	if err != nil {
+		return p.Stylesheet.CategoryFallback
+	}
+	return style
+}
+
+// GetPositionInfo returns information on a requested position in this pane (nil, for now).
+func (p *AgendaPane) GetPositionInfo(_, _ int) ui.PositionInfo { return nil }
+
+// NewAgendaPane constructs and returns a new AgendaPane.
+func NewAgendaPane(
+	renderer ui.ConstrainedRenderer,
+	dimensions func() (x, y, w, h int),
+	stylesheet styling.Stylesheet,
+	inputProcessor input.ModalInputProcessor,
+	from func() model.Date,
+	nDays int,
+	day func(model.Date) *model.Day,
+	backlog *model.Backlog,
+	categories *styling.CategoryStyling,
+) *AgendaPane {
+	return &AgendaPane{
+		LeafPane: ui.LeafPane{
+			BasePane: ui.BasePane{
+				ID:             ui.GeneratePaneID(),
+				InputProcessor: inputProcessor,
+			},
+			Renderer:   renderer,
+			Dims:       dimensions,
+			Stylesheet: stylesheet,
+		},
+		from:       from,
+		nDays:      nDays,
+		day:        day,
+		backlog:    backlog,
+		categories: categories,
+	}
+}
@@ -0,0 +1,157 @@
+package panes
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ja-he/dayplan/internal/control/edit"
+	"github.com/ja-he/dayplan/internal/control/edit/editors"
+	"github.com/ja-he/dayplan/internal/input"
+	"github.com/ja-he/dayplan/internal/styling"
+	"github.com/ja-he/dayplan/internal/ui"
+	"github.com/ja-he/dayplan/internal/util"
+)
+
+// choiceEditorListHeight is the number of candidates a ChoiceEditorPane shows.
+const choiceEditorListHeight = 4
+
+// ChoiceEditorPane visualizes a choice editor, i.e. its filter and, below,
+// the candidates matching it, each with a swatch of its style.
+type ChoiceEditorPane struct {
+	ui.LeafPane
+
+	e *editors.ChoiceEditor
+
+	// choiceStyle returns the style of the swatch for a choice's value (or nil
+	// for no swatch).
+	choiceStyle func(value any) styling.DrawStyling
+
+	cursorController ui.CursorLocationRequestHandler
+
+	idStr string
+}
+
+// Draw draws the editor.
+func (p *ChoiceEditorPane) Draw() {
+	if p.IsVisible() {
+		x, y, w, h := p.Dims()
+
+		status := p.e.GetStatus()
+		baseStyle := getAlteredStyleForEditorStatus(p.Stylesheet.Editor, status)
+
+		nameWidth := 8
+		modeWidth := 5
+		padding := 1
+
+		p.Renderer.DrawBox(x, y, w, h, baseStyle)
+		p.Renderer.DrawText(x+padding, y, nameWidth, 1, baseStyle, p.e.GetID())
+		p.Renderer.DrawText(x, y, 1, 1, baseStyle.Bolded(), string(getRuneForEditorStatus(status)))
+
+		candidates, selected := p.e.Candidates()
+
+		// the filter while focussed, the chosen value otherwise
+		contentXOffset := padding + nameWidth + padding + modeWidth + padding
+		content := p.e.GetContent()
+		if status == edit.EditorFocussed {
+			switch p.e.GetMode() {
+			case input.TextEditModeInsert:
+				p.Renderer.DrawText(x+padding+nameWidth+padding, y, modeWidth, 1, baseStyle.DarkenedFG(30).Invert(), "(ins)")
+			case input.TextEditModeNormal:
+				p.Renderer.DrawText(x+padding+nameWidth+padding, y, modeWidth, 1, baseStyle.DarkenedFG(30), "(nrm)")
+			}
+		} else if len(candidates) > 0 {
+			content = candidates[selected].Name
+		}
+		p.Renderer.DrawText(x+contentXOffset, y, w-contentXOffset-padding, 1, baseStyle, content)
+
+		if err := p.e.GetError(); err != nil {
+			errorXOffset := contentXOffset + len([]rune(content)) + padding
+			p.Renderer.DrawText(x+errorXOffset, y, w-errorXOffset-padding, 1, p.Stylesheet.LogEntryTypeError, util.TruncateAt(err.Error(), w-errorXOffset-padding))
+		}
+
+		// the candidates, scrolled such that the selected one is shown
+		offset := selected - choiceEditorListHeight + 1
+		if offset < 0 {
+			offset = 0
+		}
+		for i := offset; i < len(candidates) && i-offset < choiceEditorListHeight && i-offset < h-1; i++ {
+			rowY := y + 1 + i - offset
+			candidate := candidates[i]
+
+			style := baseStyle.DarkenedFG(30)
+			if i == selected {
+				style = baseStyle.Bolded()
+				p.Renderer.DrawText(x+contentXOffset-2, rowY, 1, 1, style, ">")
+			}
+			if swatch := p.choiceStyle(candidate.Value); swatch != nil {
+				p.Renderer.DrawBox(x+contentXOffset, rowY, 2, 1, swatch)
+			}
+			label := candidate.Name
+			switch {
+			case candidate.IsUnknown():
+				label += " (unknown)"
+			case candidate.Deprecated:
+				label += " (deprecated)"
+			}
+			labelX := contentXOffset + 3
+			p.Renderer.DrawText(x+labelX, rowY, w-labelX-padding, 1, style, util.TruncateAt(label, w-labelX-padding))
+		}
+
+		if status == edit.EditorFocussed {
+			p.cursorController.Put(ui.CursorLocation{X: x + contentXOffset + p.e.GetCursorPos(), Y: y}, p.idStr)
+		} else {
+			p.cursorController.Delete(p.idStr)
+		}
+	}
+}
+
+// Undraw ensures that the cursor is hidden.
+func (p *ChoiceEditorPane) Undraw() {
+	p.cursorController.Delete(p.idStr)
+}
+
+// GetPositionInfo returns information on a requested position in this pane (nil, for now).
+func (p *ChoiceEditorPane) GetPositionInfo(_, _ int) ui.PositionInfo { return nil }
+
+// ProcessInput attempts to process the provided input.
+func (p *ChoiceEditorPane) ProcessInput(k input.Key) bool {
+	if p.e.GetStatus() == edit.EditorInactive {
+		log.Warn().Msgf("choice editor pane asked to process input despite view reporting not active; likely logic error")
+	}
+	return p.LeafPane.ProcessInput(k)
+}
+
+// NewChoiceEditorPane creates a new ChoiceEditorPane.
+func NewChoiceEditorPane(
+	renderer ui.ConstrainedRenderer,
+	cursorController ui.CursorLocationRequestHandler,
+	visible func() bool,
+	stylesheet styling.Stylesheet,
+	inputConfig input.InputConfig,
+	choiceStyle func(value any) styling.DrawStyling,
+	e *editors.ChoiceEditor,
+) (*ChoiceEditorPane, error) {
+	inputProcessor, err := e.CreateInputProcessor(inputConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not construct input processor (%s)", err.Error())
+	}
+
+	return &ChoiceEditorPane{
+		LeafPane: ui.LeafPane{
+			BasePane: ui.BasePane{
+				ID:             ui.GeneratePaneID(),
+				InputProcessor: inputProcessor,
+				Visible:        visible,
+			},
+			Renderer:   renderer,
+			Dims:       renderer.Dimensions,
+			Stylesheet: stylesheet,
+		},
+		e:                e,
+		choiceStyle:      choiceStyle,
+		cursorController: cursorController,
+		idStr:            "choice-editor-pane-" + uuid.Must(uuid.NewRandom()).String(),
+	}, nil
+}
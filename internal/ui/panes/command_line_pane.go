@@ -0,0 +1,98 @@
+package panes
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/ja-he/dayplan/internal/control/command"
+	"github.com/ja-he/dayplan/internal/input"
+	"github.com/ja-he/dayplan/internal/styling"
+	"github.com/ja-he/dayplan/internal/ui"
+)
+
+// CommandLinePane is the command line, drawn over the bottom of the status
+// bar while a command is being entered, with the completions being cycled
+// through (if any) in the line above it.
+type CommandLinePane struct {
+	ui.LeafPane
+
+	prompt func() *command.Prompt
+
+	cursorController ui.CursorLocationRequestHandler
+
+	idStr string
+}
+
+// Draw draws the command line.
+func (p *CommandLinePane) Draw() {
+	prompt := p.prompt()
+	if prompt == nil {
+		return
+	}
+	x, y, w, h := p.Dims()
+	style := p.Stylesheet.Status.DefaultEmphasized()
+
+	if completions, current := prompt.Completions(); len(completions) > 1 && h > 1 {
+		p.Renderer.DrawBox(x, y+h-2, w, 1, style.DarkenedBG(10))
+		offset := x + 1
+		for i, completion := range completions {
+			word := completion[strings.LastIndexAny(completion, " \t")+1:]
+			if offset+len([]rune(word)) > x+w {
+				break
+			}
+			wordStyle := style.DarkenedBG(10)
+			if i == current {
+				wordStyle = wordStyle.Invert()
+			}
+			p.Renderer.DrawText(offset, y+h-2, len([]rune(word)), 1, wordStyle, word)
+			offset += len([]rune(word)) + 2
+		}
+	}
+
+	// scroll the content horizontally to keep the cursor in view
+	content := []rune(prompt.Content())
+	scroll := 0
+	if prompt.Cursor() > w-2 {
+		scroll = prompt.Cursor() - (w - 2)
+	}
+	p.Renderer.DrawBox(x, y+h-1, w, 1, style)
+	p.Renderer.DrawText(x, y+h-1, 1, 1, style.Bolded(), ":")
+	p.Renderer.DrawText(x+1, y+h-1, w-1, 1, style, string(content[scroll:]))
+	p.cursorController.Put(ui.CursorLocation{X: x + 1 + prompt.Cursor() - scroll, Y: y + h - 1}, p.idStr)
+}
+
+// Undraw ensures that the cursor is hidden.
+func (p *CommandLinePane) Undraw() {
+	p.cursorController.Delete(p.idStr)
+}
+
+// GetPositionInfo returns information on a requested position in this pane (nil, for now).
+func (p *CommandLinePane) GetPositionInfo(_, _ int) ui.PositionInfo { return nil }
+
+// NewCommandLinePane constructs and returns a new CommandLinePane, which is
+// visible while the given prompt is non-nil.
+func NewCommandLinePane(
+	renderer ui.ConstrainedRenderer,
+	dimensions func() (x, y, w, h int),
+	cursorController ui.CursorLocationRequestHandler,
+	stylesheet styling.Stylesheet,
+	inputProcessor input.ModalInputProcessor,
+	prompt func() *command.Prompt,
+) *CommandLinePane {
+	return &CommandLinePane{
+		LeafPane: ui.LeafPane{
+			BasePane: ui.BasePane{
+				ID:             ui.GeneratePaneID(),
+				InputProcessor: inputProcessor,
+				Visible:        func() bool { return prompt() != nil },
+			},
+			Renderer:   renderer,
+			Dims:       dimensions,
+			Stylesheet: stylesheet,
+		},
+		prompt:           prompt,
+		cursorController: cursorController,
+		idStr:            "command-line-pane-" + uuid.Must(uuid.NewRandom()).String(),
+	}
+}
@@ -97,6 +97,16 @@ func (p *Composite) FocusPrev() {
 	}
 }
 
+// Focus focusses the given pane, if it is one of the composite's focussables.
+func (p *Composite) Focus(pane ui.Pane) {
+	for _, focussable := range p.focussables {
+		if focussable == pane {
+			p.FocussedPane = pane
+			return
+		}
+	}
+}
+
 func (p *Composite) EnsureFocusIsOnVisible() {
 	if !p.FocussedPane.IsVisible() {
 		for i := range p.focussables {
@@ -38,6 +38,10 @@ func (p *CompositeEditorPane) Draw() {
 		p.Renderer.DrawBox(x, y, w, h, style)
 		p.Renderer.DrawText(x+1, y, w-2, 1, style, util.TruncateAt(p.e.GetID(), w-2))
 		p.Renderer.DrawText(x, y, 1, 1, style.Bolded(), string(getRuneForEditorStatus(p.e.GetStatus())))
+		if err := p.e.GetError(); err != nil {
+			errorX := x + 1 + len(p.e.GetID()) + 1
+			p.Renderer.DrawText(errorX, y, x+w-errorX-1, 1, p.Stylesheet.LogEntryTypeError, util.TruncateAt(err.Error(), x+w-errorX-1))
+		}
 
 		// draw all subpanes
 		fieldOrderSlice := p.e.GetFieldOrder()
@@ -137,6 +141,7 @@ func NewCompositeEditorPane(
 	visible func() bool,
 	inputConfig input.InputConfig,
 	stylesheet styling.Stylesheet,
+	choiceStyle func(value any) styling.DrawStyling,
 	e *editors.Composite,
 ) (*CompositeEditorPane, error) {
 
@@ -155,10 +160,12 @@ func NewCompositeEditorPane(
 		var subeditorPane ui.Pane
 		var err error
 		switch child := child.Represents.(type) {
-		case *editors.StringEditor:
-			subeditorPane, err = NewStringEditorPane(subRenderer, cursorController, visible, stylesheet, inputConfig, child)
 		case *editors.Composite:
-			subeditorPane, err = NewCompositeEditorPane(subRenderer, cursorController, visible, inputConfig, stylesheet, child)
+			subeditorPane, err = NewCompositeEditorPane(subRenderer, cursorController, visible, inputConfig, stylesheet, choiceStyle, child)
+		case *editors.ChoiceEditor:
+			subeditorPane, err = NewChoiceEditorPane(subRenderer, cursorController, visible, stylesheet, inputConfig, choiceStyle, child)
+		case editors.TextEditor:
+			subeditorPane, err = NewStringEditorPane(subRenderer, cursorController, visible, stylesheet, inputConfig, child)
 		default:
 			err = fmt.Errorf("unhandled subeditor type '%T' (forgot to handle case)", child)
 		}
@@ -223,7 +230,17 @@ func translateEditorsEditorToTUI(e edit.Editor, minX, minY, maxWidth, maxHeight
 	case *editors.Composite:
 		return translateEditorsCompositeToTUI(e, minX, minY, maxWidth, maxHeight)
 
-	case *editors.StringEditor:
+	case *editors.ChoiceEditor:
+		return ui.BoxRepresentation[edit.Editor]{
+			X:          minX,
+			Y:          minY,
+			W:          maxWidth,
+			H:          1 + choiceEditorListHeight,
+			Represents: e,
+			Children:   nil,
+		}, nil
+
+	case editors.TextEditor:
 		return ui.BoxRepresentation[edit.Editor]{
 			X:          minX,
 			Y:          minY,
@@ -2,7 +2,6 @@ package panes
 
 import (
 	"fmt"
-	"math"
 
 	"github.com/rs/zerolog/log"
 
@@ -189,30 +188,18 @@ func (p *EventsPane) getEventForPos(x, y int) *ui.EventsPanePositionInfo {
 }
 
 func (p *EventsPane) computeRects(day *model.Day, offsetX, offsetY, width, height int) map[*model.Event]util.Rect {
-	activeStack := make([]*model.Event, 0)
+	stackDepths := ui.EventStackDepths(day.Events)
 	positions := make(map[*model.Event]util.Rect)
 	for _, e := range day.Events {
-		// remove all stacked elements that have finished
-		for i := len(activeStack) - 1; i >= 0; i-- {
-			if e.Start.IsAfter(activeStack[i].End) || e.Start == activeStack[i].End {
-				activeStack = activeStack[:i]
-			} else {
-				break
-			}
-		}
-		activeStack = append(activeStack, e)
 		// based on event state, draw a box or maybe a smaller one, or ...
 		x := offsetX
 		y := p.viewParams.YForTime(e.Start) + offsetY
 		w := width
 		h := p.viewParams.YForTime(e.End) + offsetY - y
 
-		// scale the width by 3/4 for every extra item on the stack, so for one
-		// item stacked underneath the current items width will be (3/4) ** 1 = 75%
-		// of the original width, for four it would be (3/4) ** 4 = (3**4)/(4**4)
-		// or 31.5 % of the width, etc.
-		widthFactor := 0.75
-		w = int(float64(w) * math.Pow(widthFactor, float64(len(activeStack)-1)))
+		// scale the width for every extra item on the stack (see
+		// ui.EventWidthFactor) and align to the right
+		w = int(float64(w) * ui.EventWidthFactor(stackDepths[e]))
 		x += (width - w)
 
 		// make the current event wider by 1 on either side
@@ -46,11 +46,10 @@ func (p *MaybePane) GetPositionInfo(x, y int) ui.PositionInfo {
 	return nil
 }
 
-// SetParent iff condition.
+// SetParent sets the parent of the underlying pane regardless of the
+// condition, as it may only hold later on.
 func (p *MaybePane) SetParent(parent ui.PaneQuerier) {
-	if p.condition() {
-		p.pane.SetParent(parent)
-	}
+	p.pane.SetParent(parent)
 }
 
 // PopModalOverlay iff condition.
@@ -0,0 +1,176 @@
+package panes
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ja-he/dayplan/internal/input"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/progress"
+	"github.com/ja-he/dayplan/internal/styling"
+	"github.com/ja-he/dayplan/internal/ui"
+	"github.com/ja-he/dayplan/internal/util"
+)
+
+// MonthGridPane shows the month of the current date as a calendar grid with a
+// row per week.
+// Each day's cell shows the date and the total time of its events, a bar of
+// the time per category, the progress towards the categories' goals for the
+// day, and as many of its longest events as fit.
+type MonthGridPane struct {
+	ui.LeafPane
+
+	currentDate *model.Date
+	day         func(model.Date) *model.Day
+
+	categories *styling.CategoryStyling
+}
+
+// Draw draws the grid.
+func (p *MonthGridPane) Draw() {
+	x, y, w, h := p.Dimensions()
+	p.Renderer.DrawBox(x, y, w, h, p.Stylesheet.Normal)
+
+	first, last := p.currentDate.MonthBounds()
+	gridStart, _ := first.WeekBounds()
+	_, gridEnd := last.WeekBounds()
+	nWeeks := (gridStart.DaysUntil(gridEnd) + 1) / 7
+
+	cellW := w / 7
+	cellH := (h - 1) / nWeeks
+	for i := 0; i < 7; i++ {
+		weekday := model.ToString(gridStart.Forward(i).ToWeekday())
+		p.Renderer.DrawText(x+i*cellW+1, y, cellW-1, 1, p.Stylesheet.Normal.Bolded(), util.TruncateAt(weekday, cellW-1))
+	}
+	knownCategories := p.categories.GetCategories()
+	for date, i := gridStart, 0; !date.IsAfter(gridEnd); date, i = date.Next(), i+1 {
+		p.drawCell(date, knownCategories, x+(i%7)*cellW, y+1+(i/7)*cellH, cellW, cellH)
+	}
+}
+
+// drawCell draws the cell for the given date, with the progress towards the
+// goals of the given known categories, leaving a gap of one column and row to
+// the next cells.
+func (p *MonthGridPane) drawCell(date model.Date, knownCategories []model.Category, x, y, w, h int) {
+	w, h = w-1, h-1
+	if w < 1 || h < 1 {
+		return
+	}
+
+	style := p.Stylesheet.NormalEmphasized
+	if date.Month != p.currentDate.Month {
+		style = p.Stylesheet.Normal.DefaultDimmed()
+	}
+	headerStyle := style.DarkenedBG(10).Bolded()
+	if date == *p.currentDate {
+		headerStyle = headerStyle.Invert()
+	}
+	p.Renderer.DrawBox(x, y, w, h, style)
+	p.Renderer.DrawBox(x, y, w, 1, headerStyle)
+	p.Renderer.DrawText(x+1, y, w-1, 1, headerStyle, fmt.Sprint(date.Day))
+
+	day := p.day(date)
+	if day == nil || date.Month != p.currentDate.Month {
+		return
+	}
+
+	minutesByCategory := day.SumUpByCategory()
+	categories := make([]model.Category, 0, len(minutesByCategory))
+	total := 0
+	for cat, minutes := range minutesByCategory {
+		categories = append(categories, cat)
+		total += minutes
+	}
+	sort.Sort(model.ByName(categories))
+	if total > 0 {
+		totalStr := formatMinutes(total)
+		p.Renderer.DrawText(x+w-len(totalStr)-1, y, len(totalStr), 1, headerStyle, totalStr)
+	}
+
+	row := 1
+	// the time per category as a stacked bar
+	if row < h && total > 0 {
+		barX := x + 1
+		barW := w - 2
+		drawn, minutesSoFar := 0, 0
+		for _, cat := range categories {
+			minutesSoFar += minutesByCategory[cat]
+			width := minutesSoFar*barW/total - drawn
+			p.Renderer.DrawBox(barX+drawn, y+row, width, 1, p.styleFor(cat))
+			drawn += width
+		}
+		row++
+	}
+
+	// the progress towards the goals for the day
+	done, goal := progress.DayFulfillment(date, day, knownCategories)
+	if row < h && goal > 0 {
+		goalStr := fmt.Sprintf("goal %.0f%%", float64(done)/float64(goal)*100)
+		goalStyle := style.Italicized()
+		if done == goal {
+			goalStr = "goal reached"
+		}
+		p.Renderer.DrawText(x+1, y+row, w-2, 1, goalStyle, util.TruncateAt(goalStr, w-2))
+		row++
+	}
+
+	// the longest events
+	events := make([]*model.Event, len(day.Events))
+	copy(events, day.Events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.DurationInMinutesUntil(events[i].End) > events[j].Start.DurationInMinutesUntil(events[j].End)
+	})
+	for i := 0; i < len(events) && row < h; i, row = i+1, row+1 {
+		name := events[i].Name
+		if name == "" {
+			name = events[i].Cat.Name
+		}
+		text := events[i].Start.ToString() + " " + name
+		p.Renderer.DrawBox(x+1, y+row, 1, 1, p.styleFor(events[i].Cat))
+		p.Renderer.DrawText(x+3, y+row, w-4, 1, style, util.TruncateAt(text, w-4))
+	}
+}
+
+func (p *MonthGridPane) styleFor(cat model.Category) styling.DrawStyling {
+	style, err := p.categories.GetStyle(cat)
+	if err != nil {
+		return p.Stylesheet.CategoryFallback
+	}
+	return style
+}
+
+// formatMinutes formats minutes compactly, e.g. "7:30".
+func formatMinutes(minutes int) string {
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+// GetPositionInfo returns information on a requested position in this pane (nil, for now).
+func (p *MonthGridPane) GetPositionInfo(_, _ int) ui.PositionInfo { return nil }
+
+// NewMonthGridPane constructs and returns a new MonthGridPane.
+func NewMonthGridPane(
+	renderer ui.ConstrainedRenderer,
+	dimensions func() (x, y, w, h int),
+	stylesheet styling.Stylesheet,
+	inputProcessor input.ModalInputProcessor,
+	currentDate *model.Date,
+	day func(model.Date) *model.Day,
+	categories *styling.CategoryStyling,
+	visible func() bool,
+) *MonthGridPane {
+	return &MonthGridPane{
+		LeafPane: ui.LeafPane{
+			BasePane: ui.BasePane{
+				ID:             ui.GeneratePaneID(),
+				InputProcessor: inputProcessor,
+				Visible:        visible,
+			},
+			Renderer:   renderer,
+			Dims:       dimensions,
+			Stylesheet: stylesheet,
+		},
+		currentDate: currentDate,
+		day:         day,
+		categories:  categories,
+	}
+}
@@ -23,12 +23,19 @@ type RootPane struct {
 	dayViewMainPane   ui.Pane
 	weekViewMainPane  ui.Pane
 	monthViewMainPane ui.Pane
+	yearViewMainPane  ui.Pane
+
+	// the agenda is outside of the day-week-month-year hierarchy, stepping into
+	// or out of it leads back to the day view
+	agendaViewMainPane ui.Pane
 
 	summary ui.Pane
 	log     ui.Pane
 
 	help ui.Pane
 
+	commandLine ui.Pane
+
 	subpanesMtx sync.Mutex
 	subpanes    []ui.Pane
 
@@ -95,6 +102,12 @@ func (p *RootPane) getCurrentlyActivePanesInOrder() (active []ui.Pane, inactive
 		inactive = append(inactive, p.help)
 	}
 
+	if p.commandLine.IsVisible() {
+		active = append(active, p.commandLine)
+	} else {
+		inactive = append(inactive, p.commandLine)
+	}
+
 	return active, inactive
 }
 
@@ -192,7 +205,11 @@ func (p *RootPane) ViewUp() {
 	case p.weekViewMainPane:
 		p.focussedViewPane = p.monthViewMainPane
 	case p.monthViewMainPane:
+		p.focussedViewPane = p.yearViewMainPane
+	case p.yearViewMainPane:
 		return
+	case p.agendaViewMainPane:
+		p.focussedViewPane = p.dayViewMainPane
 	default:
 		panic("unknown focussed pane")
 	}
@@ -206,11 +223,33 @@ func (p *RootPane) ViewDown() {
 		p.focussedViewPane = p.dayViewMainPane
 	case p.monthViewMainPane:
 		p.focussedViewPane = p.weekViewMainPane
+	case p.yearViewMainPane:
+		p.focussedViewPane = p.monthViewMainPane
+	case p.agendaViewMainPane:
+		p.focussedViewPane = p.dayViewMainPane
 	default:
 		panic("unknown focussed pane")
 	}
 }
 
+// SetView switches to the given view.
+func (p *RootPane) SetView(view ui.ActiveView) {
+	switch view {
+	case ui.ViewDay:
+		p.focussedViewPane = p.dayViewMainPane
+	case ui.ViewWeek:
+		p.focussedViewPane = p.weekViewMainPane
+	case ui.ViewMonth:
+		p.focussedViewPane = p.monthViewMainPane
+	case ui.ViewYear:
+		p.focussedViewPane = p.yearViewMainPane
+	case ui.ViewAgenda:
+		p.focussedViewPane = p.agendaViewMainPane
+	default:
+		panic("unknown view")
+	}
+}
+
 func (p *RootPane) GetView() ui.ActiveView {
 	switch p.focussedViewPane {
 	case p.dayViewMainPane:
@@ -219,6 +258,10 @@ func (p *RootPane) GetView() ui.ActiveView {
 		return ui.ViewWeek
 	case p.monthViewMainPane:
 		return ui.ViewMonth
+	case p.yearViewMainPane:
+		return ui.ViewYear
+	case p.agendaViewMainPane:
+		return ui.ViewAgenda
 	default:
 		panic("unknown focussed pane")
 	}
@@ -234,6 +277,8 @@ func (p *RootPane) FocusNext() {}
 
 func (p *RootPane) focussedPane() ui.Pane {
 	switch {
+	case p.commandLine.IsVisible():
+		return p.commandLine
 	case p.help.IsVisible():
 		return p.help
 	case p.summary.IsVisible():
@@ -317,9 +362,12 @@ func NewRootPane(
 	dayViewMainPane *Composite,
 	weekViewMainPane *Composite,
 	monthViewMainPane *Composite,
+	yearViewMainPane *Composite,
+	agendaViewMainPane *Composite,
 	summary ui.Pane,
 	log ui.Pane,
 	help ui.Pane,
+	commandLine ui.Pane,
 	performanceMetricsOverlay ui.Pane,
 	inputProcessor input.ModalInputProcessor,
 	focussedPane ui.Pane,
@@ -332,9 +380,12 @@ func NewRootPane(
 		dayViewMainPane:           dayViewMainPane,
 		weekViewMainPane:          weekViewMainPane,
 		monthViewMainPane:         monthViewMainPane,
+		yearViewMainPane:          yearViewMainPane,
+		agendaViewMainPane:        agendaViewMainPane,
 		summary:                   summary,
 		log:                       log,
 		help:                      help,
+		commandLine:               commandLine,
 		performanceMetricsOverlay: performanceMetricsOverlay,
 		inputProcessor:            inputProcessor,
 		focussedViewPane:          focussedPane,
@@ -342,10 +393,13 @@ func NewRootPane(
 	dayViewMainPane.SetParent(rootPane)
 	weekViewMainPane.SetParent(rootPane)
 	monthViewMainPane.SetParent(rootPane)
+	yearViewMainPane.SetParent(rootPane)
+	agendaViewMainPane.SetParent(rootPane)
 
 	summary.SetParent(rootPane)
 	help.SetParent(rootPane)
 	log.SetParent(rootPane)
+	commandLine.SetParent(rootPane)
 
 	return rootPane
 }
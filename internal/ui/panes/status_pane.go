@@ -1,15 +1,23 @@
 package panes
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/balance"
 	"github.com/ja-he/dayplan/internal/control/edit"
 	"github.com/ja-he/dayplan/internal/model"
 	"github.com/ja-he/dayplan/internal/styling"
+	"github.com/ja-he/dayplan/internal/tracking"
 	"github.com/ja-he/dayplan/internal/ui"
 	"github.com/ja-he/dayplan/internal/util"
 )
 
 // StatusPane is a status bar that displays the current date, weekday, and - if
-// in a multi-day view - the progress through those days.
+// in a multi-day view - the progress through those days, as well as the event
+// being tracked live, the category balances, the day's absence and a message
+// to the user, if any.
 type StatusPane struct {
 	ui.LeafPane
 
@@ -21,6 +29,10 @@ type StatusPane struct {
 	firstDayXOffset    func() int
 
 	eventEditMode func() edit.EventEditMode
+	tracking      func() *tracking.Running
+	balances      func() []balance.Account
+	absence       func() (model.Absence, bool)
+	message       func() string
 }
 
 // Draw draws this pane.
@@ -45,9 +57,44 @@ func (p *StatusPane) Draw() {
 	// weekday string
 	p.Renderer.DrawText(0, y+1, dateWidth, 1, weekdayStyle, util.TruncateAt(p.currentDate.ToWeekday().String(), dateWidth))
 
+	// absence
+	if absence, ok := p.absence(); ok {
+		absenceStr := util.TruncateAt(absence.String(), w/2)
+		p.Renderer.DrawText(dateWidth+1, y+1, len([]rune(absenceStr)), 1, bgStyleEmph.Italicized(), absenceStr)
+	}
+
+	// message
+	if message := p.message(); message != "" {
+		messageStr := util.TruncateAt(message, w/2)
+		p.Renderer.DrawText(dateWidth+1, y, len([]rune(messageStr)), 1, bgStyleEmph.Bolded(), messageStr)
+	}
+
 	// mode string
 	modeStr := eventEditModeToString(p.eventEditMode())
 	p.Renderer.DrawText(x+w-len(modeStr)-2, y+h-1, len(modeStr), 1, bgStyleEmph.DarkenedBG(10).Italicized(), modeStr)
+
+	// balances
+	if balances := p.balances(); len(balances) > 0 {
+		entries := make([]string, len(balances))
+		for i, account := range balances {
+			entries[i] = account.Category.Name + " " + balance.FormatDuration(account.Balance)
+		}
+		balanceStr := util.TruncateAt("balance: "+strings.Join(entries, ", "), w/2)
+		balanceWidth := len([]rune(balanceStr))
+		p.Renderer.DrawText(x+w-len(modeStr)-2-balanceWidth-2, y+h-1, balanceWidth, 1, bgStyleEmph, balanceStr)
+	}
+
+	// tracked event
+	if running := p.tracking(); running != nil {
+		now := model.FromTime(time.Now())
+		duration := running.Start.DurationInMinutesUntil(running.EndAt(now.Date, now.Timestamp))
+		trackingStr := util.TruncateAt(
+			fmt.Sprintf("tracking: %s (%s) since %s, %s", running.Name, running.Category, running.Start.ToString(), util.DurationToString(duration)),
+			w/2,
+		)
+		trackingWidth := len([]rune(trackingStr))
+		p.Renderer.DrawText(x+w-trackingWidth-2, y, trackingWidth, 1, bgStyleEmph.Bolded(), trackingStr)
+	}
 }
 
 func eventEditModeToString(mode edit.EventEditMode) string {
@@ -79,6 +126,10 @@ func NewStatusPane(
 	passedDaysInPeriod func() int,
 	firstDayXOffset func() int,
 	eventEditMode func() edit.EventEditMode,
+	tracking func() *tracking.Running,
+	balances func() []balance.Account,
+	absence func() (model.Absence, bool),
+	message func() string,
 ) *StatusPane {
 	return &StatusPane{
 		LeafPane: ui.LeafPane{
@@ -95,5 +146,9 @@ func NewStatusPane(
 		passedDaysInPeriod: passedDaysInPeriod,
 		firstDayXOffset:    firstDayXOffset,
 		eventEditMode:      eventEditMode,
+		tracking:           tracking,
+		balances:           balances,
+		absence:            absence,
+		message:            message,
 	}
 }
@@ -11,13 +11,16 @@ import (
 	"github.com/ja-he/dayplan/internal/input"
 	"github.com/ja-he/dayplan/internal/styling"
 	"github.com/ja-he/dayplan/internal/ui"
+	"github.com/ja-he/dayplan/internal/util"
 )
 
-// StringEditorPane visualizes the editing of a string (as seen by a StringEditorView).
+// StringEditorPane visualizes the editing of a string (as seen by a StringEditorView)
+// or of any other value edited as text, along with the error of its last
+// write, if any.
 type StringEditorPane struct {
 	ui.LeafPane
 
-	e *editors.StringEditor
+	e editors.TextEditor
 
 	cursorController ui.CursorLocationRequestHandler
 
@@ -57,6 +60,11 @@ func (p *StringEditorPane) Draw() {
 		contentXOffset := padding + nameWidth + padding + modeWidth + padding
 		p.Renderer.DrawText(x+contentXOffset, y, w-contentXOffset+padding, h, fieldStyle, p.e.GetContent())
 
+		if err := p.e.GetError(); err != nil {
+			errorXOffset := contentXOffset + len([]rune(p.e.GetContent())) + padding
+			p.Renderer.DrawText(x+errorXOffset, y, w-errorXOffset-padding, h, p.Stylesheet.LogEntryTypeError, util.TruncateAt(err.Error(), w-errorXOffset-padding))
+		}
+
 		if status == edit.EditorFocussed {
 			cursorX, cursorY := x+contentXOffset+(p.e.GetCursorPos()), y
 			p.cursorController.Put(ui.CursorLocation{X: cursorX, Y: cursorY}, p.idStr)
@@ -91,7 +99,7 @@ func NewStringEditorPane(
 	visible func() bool,
 	stylesheet styling.Stylesheet,
 	inputConfig input.InputConfig,
-	e *editors.StringEditor,
+	e editors.TextEditor,
 ) (*StringEditorPane, error) {
 	inputProcessor, err := e.CreateInputProcessor(inputConfig)
 	if err != nil {
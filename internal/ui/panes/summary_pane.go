@@ -1,11 +1,15 @@
 package panes
 
 import (
+	"fmt"
 	"sort"
+	"time"
 
 	"github.com/ja-he/dayplan/internal/input"
 	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/progress"
 	"github.com/ja-he/dayplan/internal/styling"
+	"github.com/ja-he/dayplan/internal/summary"
 	"github.com/ja-he/dayplan/internal/ui"
 	"github.com/ja-he/dayplan/internal/util"
 )
@@ -13,11 +17,17 @@ import (
 // SummaryPane shows a summary of the set of days it is provided.
 // It shows all events' times summed up (by Summarize, meaning without counting
 // any time multiple times) and visualizes the results in simple bars.
+// Below, it shows the progress of categories with goals and, in the week view,
+// whether their goals for the week are still reachable.
+// Categories and pattern goals over their limits are listed as warnings.
 type SummaryPane struct {
 	ui.LeafPane
 
-	titleString func() string
-	days        func() []*model.Day
+	titleString  func() string
+	days         func() []*model.Day
+	firstDay     func() model.Date
+	isWeekView   func() bool
+	patternGoals []model.PatternGoal
 
 	categories *styling.CategoryStyling
 }
@@ -46,7 +56,7 @@ func (p *SummaryPane) Draw() {
 		p.Renderer.DrawBox(x, y, w, 1, p.Stylesheet.SummaryTitleBox)
 		p.Renderer.DrawText(x+(w/2-len(title)/2), y, len(title), 1, p.Stylesheet.SummaryTitleBox, title)
 
-		summary := make(map[model.Category]int)
+		durations := make(map[model.Category]int)
 
 		days := p.days()
 		for i := range days {
@@ -55,15 +65,15 @@ func (p *SummaryPane) Draw() {
 			}
 			tmpSummary := days[i].SumUpByCategory()
 			for k, v := range tmpSummary {
-				summary[k] += v
+				durations[k] += v
 			}
 		}
 
 		maxDuration := 0
-		categories := make([]model.Category, len(summary))
+		categories := make([]model.Category, len(durations))
 		{ // get sorted keys to have deterministic order
 			i := 0
-			for category, duration := range summary {
+			for category, duration := range durations {
 				categories[i] = category
 				if duration > maxDuration {
 					maxDuration = duration
@@ -74,7 +84,7 @@ func (p *SummaryPane) Draw() {
 		}
 		row := 2
 		for _, category := range categories {
-			duration := summary[category]
+			duration := durations[category]
 			style, err := p.categories.GetStyle(category)
 			if err != nil {
 				style = p.Stylesheet.CategoryFallback
@@ -83,11 +93,82 @@ func (p *SummaryPane) Draw() {
 			catLen := 20
 			durationLen := 20
 			barWidth := int(float64(duration) / float64(maxDuration) * float64(w-catLen-durationLen))
+			if barWidth < 0 {
+				barWidth = 0
+			}
 			p.Renderer.DrawBox(x+catLen+durationLen, y+row, barWidth, 1, categoryStyling)
 			p.Renderer.DrawText(x, y+row, catLen, 1, p.Stylesheet.SummaryDefault, util.TruncateAt(category.Name, catLen))
 			p.Renderer.DrawText(x+catLen, y+row, durationLen, 1, categoryStyling, "("+util.DurationToString(duration)+")")
 			row++
 		}
+
+		goalProgress := progress.Compute(p.firstDay(), days, p.categories.GetCategories())
+		if len(goalProgress) > 0 {
+			row++
+			p.Renderer.DrawText(x, y+row, w, 1, p.Stylesheet.SummaryDefault.Bolded(), "GOALS:")
+			row++
+		}
+		today := model.FromTime(time.Now()).Date
+		for _, goal := range goalProgress {
+			style, err := p.categories.GetStyle(goal.Category)
+			if err != nil {
+				style = p.Stylesheet.CategoryFallback
+			}
+			catLen := 20
+			textLen := 40
+			text := fmt.Sprintf("%s / %s (%.0f%%)", util.DurationToString(int(goal.Done/time.Minute)), util.DurationToString(int(goal.Goal/time.Minute)), goal.Fraction()*100)
+			if remaining := goal.Remaining(); remaining > 0 {
+				text += ", " + util.DurationToString(int(remaining/time.Minute)) + " left"
+			}
+			p.Renderer.DrawText(x, y+row, catLen, 1, p.Stylesheet.SummaryDefault, util.TruncateAt(goal.Category.Name, catLen))
+			p.Renderer.DrawText(x+catLen, y+row, textLen, 1, p.Stylesheet.SummaryDefault, util.TruncateAt(text, textLen))
+			barWidth := w - catLen - textLen - 2
+			if barWidth < 0 {
+				barWidth = 0
+			}
+			filledWidth := barWidth
+			if goal.Fraction() < 1 {
+				filledWidth = int(goal.Fraction() * float64(barWidth))
+			}
+			p.Renderer.DrawBox(x+catLen+textLen, y+row, barWidth, 1, p.Stylesheet.SummaryDefault.DarkenedBG(10))
+			p.Renderer.DrawBox(x+catLen+textLen, y+row, filledWidth, 1, style)
+			row++
+
+			if p.isWeekView() {
+				projection := progress.ProjectWeek(goal.Category.Goal, goal.Done, p.firstDay(), today, progress.DefaultMaxPerDay)
+				var projectionStr string
+				projectionStyle := p.Stylesheet.SummaryDefault.Italicized()
+				switch {
+				case projection.Remaining == 0:
+					projectionStr = "week's goal reached"
+				case projection.Reachable:
+					projectionStr = fmt.Sprintf("reachable: %s/day over the %d days left", util.DurationToString(int(projection.PerDay/time.Minute)), projection.DaysLeft)
+				case projection.DaysLeft == 0:
+					projectionStr = "NOT reached, no days left"
+					projectionStyle = p.Stylesheet.LogEntryTypeWarn
+				default:
+					projectionStr = fmt.Sprintf("NOT reachable: would need %s/day over the %d days left", util.DurationToString(int(projection.PerDay/time.Minute)), projection.DaysLeft)
+					projectionStyle = p.Stylesheet.LogEntryTypeWarn
+				}
+				p.Renderer.DrawText(x+catLen, y+row, w-catLen, 1, projectionStyle, util.TruncateAt(projectionStr, w-catLen))
+				row++
+			}
+		}
+
+		rows, err := summary.Summarize(p.firstDay(), days, summary.GroupByCategory, func(model.Category) bool { return true }, p.patternGoals)
+		if err != nil {
+			return
+		}
+		warnings := summary.Warnings(rows)
+		if len(warnings) > 0 {
+			row++
+			p.Renderer.DrawText(x, y+row, w, 1, p.Stylesheet.LogEntryTypeWarn, "WARNINGS:")
+			row++
+		}
+		for _, warning := range warnings {
+			p.Renderer.DrawText(x+2, y+row, w-2, 1, p.Stylesheet.SummaryDefault, util.TruncateAt(warning, w-2))
+			row++
+		}
 	}
 }
 
@@ -104,6 +185,9 @@ func NewSummaryPane(
 	condition func() bool,
 	titleString func() string,
 	days func() []*model.Day,
+	firstDay func() model.Date,
+	isWeekView func() bool,
+	patternGoals []model.PatternGoal,
 	categories *styling.CategoryStyling,
 	inputProcessor input.ModalInputProcessor,
 ) *SummaryPane {
@@ -118,8 +202,11 @@ func NewSummaryPane(
 			Dims:       dimensions,
 			Stylesheet: stylesheet,
 		},
-		titleString: titleString,
-		days:        days,
-		categories:  categories,
+		titleString:  titleString,
+		days:         days,
+		firstDay:     firstDay,
+		isWeekView:   isWeekView,
+		patternGoals: patternGoals,
+		categories:   categories,
 	}
 }
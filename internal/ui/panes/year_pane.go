@@ -0,0 +1,232 @@
+package panes
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ja-he/dayplan/internal/input"
+	"github.com/ja-he/dayplan/internal/model"
+	"github.com/ja-he/dayplan/internal/progress"
+	"github.com/ja-he/dayplan/internal/styling"
+	"github.com/ja-he/dayplan/internal/ui"
+	"github.com/ja-he/dayplan/internal/util"
+)
+
+// HeatmapMode is what the days in the YearPane's heatmap are colored by.
+type HeatmapMode int
+
+const (
+	// HeatmapTotal colors the days by the total time of their events.
+	HeatmapTotal HeatmapMode = iota
+	// HeatmapCategory colors the days by the time of their events of the
+	// current category.
+	HeatmapCategory
+	// HeatmapGoals colors the days by how much of the categories' goals for
+	// them is fulfilled.
+	HeatmapGoals
+)
+
+// Next returns the mode to cycle to from the receiver.
+func (m HeatmapMode) Next() HeatmapMode {
+	return (m + 1) % 3
+}
+
+// heatmapLevels is the number of shades of the heatmap (besides the one for
+// days without any time).
+const heatmapLevels = 4
+
+// YearPane shows the year of the current date as a heatmap, with a column per
+// week and a row per weekday, in which each day's cell is colored by the day's
+// value per the HeatmapMode.
+// Days that are not loaded (yet) are shown as empty.
+type YearPane struct {
+	ui.LeafPane
+
+	currentDate     *model.Date
+	day             func(model.Date) *model.Day
+	mode            *HeatmapMode
+	currentCategory *model.Category
+
+	categories *styling.CategoryStyling
+}
+
+// Draw draws the heatmap.
+func (p *YearPane) Draw() {
+	x, y, w, h := p.Dimensions()
+	p.Renderer.DrawBox(x, y, w, h, p.Stylesheet.Normal)
+
+	first := model.Date{Year: p.currentDate.Year, Month: 1, Day: 1}
+	last := model.Date{Year: p.currentDate.Year, Month: 12, Day: 31}
+	gridStart, _ := first.WeekBounds()
+	_, gridEnd := last.WeekBounds()
+	nWeeks := (gridStart.DaysUntil(gridEnd) + 1) / 7
+
+	// the values are shaded relative to the year's highest (except for goals)
+	knownCategories := p.categories.GetCategories()
+	values := make(map[model.Date]float64)
+	maxValue := 1.0
+	for date := first; !date.IsAfter(last); date = date.Next() {
+		if value, ok := p.value(date, knownCategories); ok {
+			values[date] = value
+			if *p.mode != HeatmapGoals && value > maxValue {
+				maxValue = value
+			}
+		}
+	}
+
+	labelWidth := 4
+	cellWidth := (w - labelWidth) / nWeeks
+	if cellWidth > 4 {
+		cellWidth = 4
+	}
+	if cellWidth < 1 {
+		cellWidth = 1
+	}
+	boxWidth := cellWidth - 1
+	if boxWidth < 1 {
+		boxWidth = 1
+	}
+	gridX, gridY := x+labelWidth, y+3
+
+	title := fmt.Sprintf("%d: %s", p.currentDate.Year, p.modeDescription())
+	p.Renderer.DrawText(x+1, y, w-1, 1, p.Stylesheet.Normal.Bolded(), util.TruncateAt(title, w-1))
+
+	for month := 1; month <= 12; month++ {
+		week := gridStart.DaysUntil(model.Date{Year: first.Year, Month: month, Day: 1}) / 7
+		name := time.Month(month).String()[:3]
+		p.Renderer.DrawText(gridX+week*cellWidth, gridY-1, len(name), 1, p.Stylesheet.Normal, name)
+	}
+	for weekday := 0; weekday < 7; weekday += 2 {
+		name := model.ToString(gridStart.Forward(weekday).ToWeekday())[:3]
+		p.Renderer.DrawText(x, gridY+weekday, labelWidth-1, 1, p.Stylesheet.Normal, name)
+	}
+
+	for date := first; !date.IsAfter(last); date = date.Next() {
+		i := gridStart.DaysUntil(date)
+		style := p.levelStyle(level(values[date], maxValue))
+		if date == *p.currentDate {
+			style = p.Stylesheet.Normal.Invert()
+		}
+		p.Renderer.DrawBox(gridX+(i/7)*cellWidth, gridY+(i%7), boxWidth, 1, style)
+	}
+
+	// legend
+	legendY := gridY + 8
+	p.Renderer.DrawText(gridX, legendY, 4, 1, p.Stylesheet.Normal, "less")
+	for l := 0; l <= heatmapLevels; l++ {
+		p.Renderer.DrawBox(gridX+5+l*cellWidth, legendY, boxWidth, 1, p.levelStyle(l))
+	}
+	p.Renderer.DrawText(gridX+5+(heatmapLevels+1)*cellWidth+1, legendY, 4, 1, p.Stylesheet.Normal, "more")
+
+	// the current date's value
+	info := fmt.Sprintf("%s %s: ", p.currentDate.ToString(), p.currentDate.ToWeekday().String())
+	if value, ok := values[*p.currentDate]; !ok {
+		info += "-"
+	} else if *p.mode == HeatmapGoals {
+		info += fmt.Sprintf("%.0f%% of goals", value*100)
+	} else {
+		info += formatMinutes(int(value))
+	}
+	p.Renderer.DrawText(gridX, legendY+2, w-labelWidth, 1, p.Stylesheet.Normal, util.TruncateAt(info, w-labelWidth))
+}
+
+// value returns the value of the given date per the mode, if it has one (i.e.,
+// if it is loaded and, for goals, if any are set for it).
+// Times are in minutes, goals (of the given known categories) as the fraction
+// fulfilled.
+func (p *YearPane) value(date model.Date, knownCategories []model.Category) (float64, bool) {
+	day := p.day(date)
+	if day == nil {
+		return 0, false
+	}
+	switch *p.mode {
+	case HeatmapTotal, HeatmapCategory:
+		minutes := 0
+		for cat, catMinutes := range day.SumUpByCategory() {
+			if *p.mode == HeatmapTotal || cat.Name == p.currentCategory.Name {
+				minutes += catMinutes
+			}
+		}
+		return float64(minutes), true
+	case HeatmapGoals:
+		done, goal := progress.DayFulfillment(date, day, knownCategories)
+		if goal == 0 {
+			return 0, false
+		}
+		return float64(done) / float64(goal), true
+	default:
+		panic(fmt.Sprintf("unknown heatmap mode %d", *p.mode))
+	}
+}
+
+func (p *YearPane) modeDescription() string {
+	switch *p.mode {
+	case HeatmapTotal:
+		return "total time"
+	case HeatmapCategory:
+		return "time for " + p.currentCategory.Name
+	case HeatmapGoals:
+		return "goals"
+	default:
+		panic(fmt.Sprintf("unknown heatmap mode %d", *p.mode))
+	}
+}
+
+// level returns the level of shading for the given value, 0 only for none.
+func level(value, maxValue float64) int {
+	if value <= 0 {
+		return 0
+	}
+	l := int(math.Ceil(value / maxValue * heatmapLevels))
+	if l > heatmapLevels {
+		return heatmapLevels
+	}
+	return l
+}
+
+func (p *YearPane) levelStyle(l int) styling.DrawStyling {
+	if l == 0 {
+		return p.Stylesheet.NormalEmphasized
+	}
+	target := p.Stylesheet.Heatmap
+	if *p.mode == HeatmapCategory {
+		if style, err := p.categories.GetStyle(*p.currentCategory); err == nil {
+			target = style
+		}
+	}
+	return styling.BlendedBG(p.Stylesheet.NormalEmphasized, target, float64(l)/heatmapLevels)
+}
+
+// GetPositionInfo returns information on a requested position in this pane (nil, for now).
+func (p *YearPane) GetPositionInfo(_, _ int) ui.PositionInfo { return nil }
+
+// NewYearPane constructs and returns a new YearPane.
+func NewYearPane(
+	renderer ui.ConstrainedRenderer,
+	dimensions func() (x, y, w, h int),
+	stylesheet styling.Stylesheet,
+	inputProcessor input.ModalInputProcessor,
+	currentDate *model.Date,
+	day func(model.Date) *model.Day,
+	mode *HeatmapMode,
+	currentCategory *model.Category,
+	categories *styling.CategoryStyling,
+) *YearPane {
+	return &YearPane{
+		LeafPane: ui.LeafPane{
+			BasePane: ui.BasePane{
+				ID:             ui.GeneratePaneID(),
+				InputProcessor: inputProcessor,
+			},
+			Renderer:   renderer,
+			Dims:       dimensions,
+			Stylesheet: stylesheet,
+		},
+		currentDate:     currentDate,
+		day:             day,
+		mode:            mode,
+		currentCategory: currentCategory,
+		categories:      categories,
+	}
+}
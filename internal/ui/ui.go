@@ -108,9 +108,9 @@ func (t PaneType) ToString() string {
 	return "[UNKNOWN]"
 }
 
-// ActiveView is the active view of the UI, which could be a single day, a week
-// or a full month (or in the future any other stretch of time that's to be
-// shown).
+// ActiveView is the active view of the UI, which could be a single day, a
+// week, a full month or a full year (or in the future any other stretch of
+// time that's to be shown), or the agenda of the upcoming days.
 type ActiveView int
 
 const (
@@ -123,6 +123,12 @@ const (
 	// ViewMonth represents the view in which a full month (first to last) is
 	// visible.
 	ViewMonth
+	// ViewYear represents the view in which a full year is visible as a
+	// heatmap of its days.
+	ViewYear
+	// ViewAgenda represents the view in which the events of the upcoming days
+	// are listed.
+	ViewAgenda
 )
 
 // PaneID uniquely identifies a pane. No two panes must ever share a PaneID.
@@ -56,3 +56,31 @@ func PadCenter(s string, length int) string {
 func Enquote(s string) string {
 	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
 }
+
+// FuzzyMatch informs on whether all runes of the pattern occur in s in order
+// (ignoring case), and scores the match, a higher score being a better match.
+// Runes matched consecutively or at the beginning of a word score higher.
+func FuzzyMatch(pattern, s string) (score int, ok bool) {
+	p := []rune(strings.ToLower(pattern))
+	r := []rune(strings.ToLower(s))
+	matched := 0
+	prevMatch := -2
+	for i := 0; i < len(r) && matched < len(p); i++ {
+		if r[i] != p[matched] {
+			continue
+		}
+		score++
+		if i == prevMatch+1 {
+			score += 2
+		}
+		if i == 0 || strings.ContainsRune(" -_./", r[i-1]) {
+			score += 3
+		}
+		prevMatch = i
+		matched++
+	}
+	if matched < len(p) {
+		return 0, false
+	}
+	return score, true
+}
@@ -124,3 +124,28 @@ func TestTruncateAt(t *testing.T) {
 		}
 	}
 }
+
+func TestFuzzyMatch(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		s       string
+		ok      bool
+	}{
+		{"", "work", true},
+		{"wrk", "work", true},
+		{"WoRk", "work", true},
+		{"ca", "client-acme", true},
+		{"kw", "work", false},
+		{"works", "work", false},
+	} {
+		if _, ok := FuzzyMatch(tc.pattern, tc.s); ok != tc.ok {
+			t.Errorf("expected matching '%s' against '%s' to give %t, got %t", tc.pattern, tc.s, tc.ok, ok)
+		}
+	}
+
+	prefixScore, _ := FuzzyMatch("me", "meeting")
+	scatteredScore, _ := FuzzyMatch("me", "client-acme")
+	if prefixScore <= scatteredScore {
+		t.Errorf("expected prefix match (%d) to score higher than non-prefix match (%d)", prefixScore, scatteredScore)
+	}
+}